@@ -26,6 +26,7 @@ require (
 
 require (
 	github.com/IBM/sarama v1.42.1
+	github.com/jung-kurt/gofpdf v1.16.2
 	github.com/razorpay/razorpay-go v1.3.1
 	github.com/robfig/cron v1.2.0
 )
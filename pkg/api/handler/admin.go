@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"main/pkg/helper"
 	services "main/pkg/usecase/interface"
 	models "main/pkg/utils/models"
 	"main/pkg/utils/response"
@@ -11,12 +12,12 @@ import (
 )
 
 type AdminHandler struct {
-	adminUseCase services.AdminUseCase
+	AdminUseCase services.AdminUseCase
 }
 
 func NewAdminHandler(usecase services.AdminUseCase) *AdminHandler {
 	return &AdminHandler{
-		adminUseCase: usecase,
+		AdminUseCase: usecase,
 	}
 }
 
@@ -39,7 +40,7 @@ func (ad *AdminHandler) LoginHandler(c *gin.Context) { // login handler for the
 		return
 	}
 
-	admin, err := ad.adminUseCase.LoginHandler(adminDetails)
+	admin, err := ad.AdminUseCase.LoginHandler(adminDetails)
 	if err != nil {
 		errRes := response.ClientResponse(http.StatusBadRequest, "cannot authenticate user", nil, err.Error())
 		c.JSON(http.StatusBadRequest, errRes)
@@ -82,7 +83,7 @@ func (ad *AdminHandler) GetUsers(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, errorRes)
 		return
 	}
-	users, err := ad.adminUseCase.GetUsers(page, limit)
+	users, err := ad.AdminUseCase.GetUsers(page, limit)
 	if err != nil {
 		errorRes := response.ClientResponse(http.StatusBadRequest, "could not retrieve records", nil, err.Error())
 		c.JSON(http.StatusBadRequest, errorRes)
@@ -93,31 +94,6 @@ func (ad *AdminHandler) GetUsers(c *gin.Context) {
 
 }
 
-// @Summary		Block or unblock User
-// @Description	using this handler admins can block or unblock an user
-// @Tags			Admin User Management
-// @Accept			json
-// @Produce		json
-// @Security		Bearer
-// @Param			id	query		string	true	"user-id"
-// @Success		200	{object}	response.Response{}
-// @Failure		500	{object}	response.Response{}
-// @Router			/admin/users/toggle-block [post]
-func (ad *AdminHandler) ToggleBlockUser(c *gin.Context) {
-
-	id := c.Query("id")
-	err := ad.adminUseCase.ToggleBlockUser(id)
-	if err != nil {
-		errorRes := response.ClientResponse(http.StatusBadRequest, "user could not be blocked", nil, err.Error())
-		c.JSON(http.StatusBadRequest, errorRes)
-		return
-	}
-
-	successRes := response.ClientResponse(http.StatusOK, "Successfully blocked the user", nil, nil)
-	c.JSON(http.StatusOK, successRes)
-
-}
-
 // @Summary		Get Reports List
 // @Description	Get a paginated list of user reports
 // @Tags			Admin User Management
@@ -132,7 +108,7 @@ func (ad *AdminHandler) ToggleBlockUser(c *gin.Context) {
 func (u *AdminHandler) GetReports(c *gin.Context) {
 	page, limit := getPaginationParams(c)
 
-	reports, err := u.adminUseCase.GetReports(page, limit)
+	reports, err := u.AdminUseCase.GetReports(page, limit)
 	if err != nil {
 		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get reports", nil, err.Error())
 		c.JSON(http.StatusBadRequest, errorRes)
@@ -152,14 +128,14 @@ func getPaginationParams(c *gin.Context) (int, int) {
 }
 
 // @Summary		Add Subscription Plan
-// @Description	using this handler admins can add a new subscription plan
+// @Description	using this handler admins can add a new subscription plan, it can be bought for any creator
 // @Tags			Admin
 // @Accept			json
 // @Produce		json
 // @Security		Bearer
 // @Param			name	query		string	true	"Plan Name"
 // @Param			duration	query		int	true	"Plan Duration (in days)"
-// @Param			price	query		float64	true	"Plan Price"
+// @Param			price	query		float64	true	"Plan Price, in the platform currency"
 // @Success		201	{object}	response.Response{}
 // @Failure		400	{object}	response.Response{}
 // @Router			/admin/plans/add [post]
@@ -181,7 +157,7 @@ func (ad *AdminHandler) AddSubscriptionPlan(c *gin.Context) {
 	}
 
 	// Call the function to add the subscription plan
-	err = ad.adminUseCase.AddSubscriptionPlan(name, duration, price)
+	err = ad.AdminUseCase.AddSubscriptionPlan(name, duration, price)
 	if err != nil {
 		errorRes := response.ClientResponse(http.StatusBadRequest, "plan could not be added", nil, err.Error())
 		c.JSON(http.StatusBadRequest, errorRes)
@@ -210,7 +186,7 @@ func (ad *AdminHandler) DeleteSubscriptionPlan(c *gin.Context) {
 		return
 	}
 
-	err = ad.adminUseCase.DeleteSubscriptionPlan(planID)
+	err = ad.AdminUseCase.DeleteSubscriptionPlan(planID)
 	if err != nil {
 		errorRes := response.ClientResponse(http.StatusBadRequest, "plan could not be deleted", nil, err.Error())
 		c.JSON(http.StatusBadRequest, errorRes)
@@ -231,7 +207,7 @@ func (ad *AdminHandler) DeleteSubscriptionPlan(c *gin.Context) {
 // @Failure		400	{object}	response.Response{}
 // @Router			/admin/plans [get]
 func (ad *AdminHandler) GetSubscriptionPlans(c *gin.Context) {
-	plans, err := ad.adminUseCase.GetSubscriptionPlans()
+	plans, err := ad.AdminUseCase.GetSubscriptionPlans()
 	if err != nil {
 		errorRes := response.ClientResponse(http.StatusBadRequest, "could not fetch subscription plans", nil, err.Error())
 		c.JSON(http.StatusBadRequest, errorRes)
@@ -258,7 +234,7 @@ func (u *AdminHandler) GetReportsofuser(c *gin.Context) {
 	page, limit := getPaginationParams(c)
 	userId, _ := strconv.Atoi(c.Query("userId"))
 
-	reports, count, err := u.adminUseCase.GetUserReports(userId, page, limit)
+	reports, count, err := u.AdminUseCase.GetUserReports(userId, page, limit)
 	if err != nil {
 		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get reports", nil, err.Error())
 		c.JSON(http.StatusBadRequest, errorRes)
@@ -272,3 +248,153 @@ func (u *AdminHandler) GetReportsofuser(c *gin.Context) {
 	}, nil)
 	c.JSON(http.StatusOK, successRes)
 }
+
+// @Summary		List Admins
+// @Description	Super admin gets the admin accounts with their roles
+// @Tags			Admin Accounts
+// @Accept			json
+// @Produce		json
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=[]models.AdminAccount}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/admins [get]
+func (ad *AdminHandler) ListAdmins(c *gin.Context) {
+	admins, err := ad.AdminUseCase.ListAdmins()
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the admins", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Admins retrieved successfully", admins, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Create Admin
+// @Description	Super admin creates an admin account with a role: super_admin, moderator, finance or content_curator
+// @Tags			Admin Accounts
+// @Accept			json
+// @Produce		json
+// @Param			admin	body	models.CreateAdmin	true	"admin"
+// @Security		Bearer
+// @Success		201	{object}	response.Response{data=models.AdminAccount}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/admins [post]
+func (ad *AdminHandler) CreateAdmin(c *gin.Context) {
+	var details models.CreateAdmin
+	if err := c.BindJSON(&details); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	admin, err := ad.AdminUseCase.CreateAdmin(details)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not create the admin", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusCreated, "Successfully created the admin", admin, nil)
+	c.JSON(http.StatusCreated, successRes)
+}
+
+// @Summary		Assign Admin Role
+// @Description	Super admin changes the role of another admin, it applies to their next request
+// @Tags			Admin Accounts
+// @Accept			json
+// @Produce		json
+// @Param			id		query	int					true	"Admin ID"
+// @Param			role	body	models.AdminRole	true	"role"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/admins/role [patch]
+func (ad *AdminHandler) SetAdminRole(c *gin.Context) {
+	actorID, err := helper.GetAdminID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get adminID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	adminID, err := strconv.ParseUint(c.Query("id"), 10, 64)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Admin ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	var role models.AdminRole
+	if err := c.BindJSON(&role); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	if err := ad.AdminUseCase.SetAdminRole(actorID, uint(adminID), role.Role); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not change the role", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Successfully changed the role", nil, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Disable Admin
+// @Description	Super admin disables another admin, who can no longer log in or act
+// @Tags			Admin Accounts
+// @Accept			json
+// @Produce		json
+// @Param			id	query	int	true	"Admin ID"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/admins/disable [patch]
+func (ad *AdminHandler) DisableAdmin(c *gin.Context) {
+	ad.setAdminDisabled(c, true)
+}
+
+// @Summary		Enable Admin
+// @Description	Super admin enables a disabled admin again
+// @Tags			Admin Accounts
+// @Accept			json
+// @Produce		json
+// @Param			id	query	int	true	"Admin ID"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/admins/enable [patch]
+func (ad *AdminHandler) EnableAdmin(c *gin.Context) {
+	ad.setAdminDisabled(c, false)
+}
+
+func (ad *AdminHandler) setAdminDisabled(c *gin.Context, disabled bool) {
+	actorID, err := helper.GetAdminID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get adminID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	adminID, err := strconv.ParseUint(c.Query("id"), 10, 64)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Admin ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	if err := ad.AdminUseCase.SetAdminDisabled(actorID, uint(adminID), disabled); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not update the admin", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	message := "Successfully enabled the admin"
+	if disabled {
+		message = "Successfully disabled the admin"
+	}
+	successRes := response.ClientResponse(http.StatusOK, message, nil, nil)
+	c.JSON(http.StatusOK, successRes)
+}
@@ -0,0 +1,402 @@
+package handler
+
+import (
+	"io"
+	"log"
+	"main/pkg/helper"
+	services "main/pkg/usecase/interface"
+	"main/pkg/utils/models"
+	"main/pkg/utils/response"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AnalyticsHandler struct {
+	AnalyticsUseCase services.AnalyticsUseCase
+}
+
+func NewAnalyticsHandler(usecase services.AnalyticsUseCase) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		AnalyticsUseCase: usecase,
+	}
+}
+
+// @Summary		Get Analytics Time Series
+// @Description	Creator gets views, watch time, likes, comments, new followers, subscribers gained and lost and revenue per day, week or month, the last 30 days by default
+// @Tags			Analytics
+// @Accept			json
+// @Produce		json
+// @Param			start_date	query	string	false	"Start date (YYYY-MM-DD)"
+// @Param			end_date	query	string	false	"End date (YYYY-MM-DD)"
+// @Param			granularity	query	string	false	"day, week or month (default: day)"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=models.AnalyticsSeries}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/analytics/timeseries [get]
+func (a *AnalyticsHandler) GetTimeSeries(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	series, err := a.AnalyticsUseCase.GetTimeSeries(userID, c.Query("start_date"), c.Query("end_date"), c.DefaultQuery("granularity", "day"))
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the analytics", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Analytics retrieved successfully", series, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		List Video Analytics
+// @Description	Creator gets the views, watch time, likes and comments of each of their videos in the range, the last 30 days by default
+// @Tags			Analytics
+// @Accept			json
+// @Produce		json
+// @Param			start_date	query	string	false	"Start date (YYYY-MM-DD)"
+// @Param			end_date	query	string	false	"End date (YYYY-MM-DD)"
+// @Param			sort		query	string	false	"views, watch_time, likes, comments or newest (default: views)"
+// @Param			page		query	int		false	"Page number (default: 1)"
+// @Param			limit		query	int		false	"Limit per page (default: 20)"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=[]models.VideoAnalytics}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/analytics/videos [get]
+func (a *AnalyticsHandler) ListVideoAnalytics(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	page, limit := parsePaginationParams(c)
+
+	videos, err := a.AnalyticsUseCase.ListVideoAnalytics(userID, c.Query("start_date"), c.Query("end_date"), c.Query("sort"), page, limit)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the video analytics", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Video analytics retrieved successfully", videos, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Top Videos
+// @Description	Creator gets their best videos in the range by a metric, the last 30 days by default
+// @Tags			Analytics
+// @Accept			json
+// @Produce		json
+// @Param			start_date	query	string	false	"Start date (YYYY-MM-DD)"
+// @Param			end_date	query	string	false	"End date (YYYY-MM-DD)"
+// @Param			metric		query	string	false	"views, watch_time, likes or comments (default: views)"
+// @Param			limit		query	int		false	"Number of videos (default: 10)"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=[]models.VideoAnalytics}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/analytics/videos/top [get]
+func (a *AnalyticsHandler) TopVideos(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	videos, err := a.AnalyticsUseCase.TopVideos(userID, c.Query("start_date"), c.Query("end_date"), c.Query("metric"), limit)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the top videos", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Top videos retrieved successfully", videos, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Get Video Analytics
+// @Description	Creator gets the views, watch time, likes and comments of one of their videos per day, week or month, the last 30 days by default
+// @Tags			Analytics
+// @Accept			json
+// @Produce		json
+// @Param			video_id	query	int		true	"Video ID"
+// @Param			start_date	query	string	false	"Start date (YYYY-MM-DD)"
+// @Param			end_date	query	string	false	"End date (YYYY-MM-DD)"
+// @Param			granularity	query	string	false	"day, week or month (default: day)"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=models.AnalyticsSeries}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/analytics/video [get]
+func (a *AnalyticsHandler) GetVideoTimeSeries(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	videoID, err := strconv.ParseUint(c.Query("video_id"), 10, 64)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Video ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	series, err := a.AnalyticsUseCase.GetVideoTimeSeries(userID, uint(videoID), c.Query("start_date"), c.Query("end_date"), c.DefaultQuery("granularity", "day"))
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the video analytics", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Video analytics retrieved successfully", series, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Get Audience Retention
+// @Description	Creator gets the share of the views of one of their videos that reached every 5% of it, for views opened in the range, the last 30 days by default
+// @Tags			Analytics
+// @Accept			json
+// @Produce		json
+// @Param			video_id	query	int		true	"Video ID"
+// @Param			start_date	query	string	false	"Start date (YYYY-MM-DD)"
+// @Param			end_date	query	string	false	"End date (YYYY-MM-DD)"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=models.VideoRetention}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/analytics/retention [get]
+func (a *AnalyticsHandler) GetVideoRetention(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	videoID, err := strconv.ParseUint(c.Query("video_id"), 10, 64)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Video ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	retention, err := a.AnalyticsUseCase.GetVideoRetention(userID, uint(videoID), c.Query("start_date"), c.Query("end_date"))
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the audience retention", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Audience retention retrieved successfully", retention, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Get Dashboard
+// @Description	Admin gets the platform's active users, signups, uploads, watch time, active subscriptions, gross revenue, reports and moderation backlog for the range, the last 30 days by default, each compared with the range of the same length before it
+// @Tags			Admin
+// @Accept			json
+// @Produce		json
+// @Param			start_date	query	string	false	"Start date (YYYY-MM-DD)"
+// @Param			end_date	query	string	false	"End date (YYYY-MM-DD)"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=models.Dashboard}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/dashboard [get]
+func (a *AnalyticsHandler) GetDashboard(c *gin.Context) {
+	dashboard, err := a.AnalyticsUseCase.GetDashboard(c.Query("start_date"), c.Query("end_date"))
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the dashboard", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Dashboard retrieved successfully", dashboard, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Export Analytics
+// @Description	Creator downloads views, subscribers or revenue per day, week or month, or the stats of every video, as CSV or NDJSON, the last 30 days by default
+// @Tags			Analytics
+// @Produce		text/csv,application/x-ndjson
+// @Param			report		query	string	true	"views, subscribers, revenue or videos"
+// @Param			format		query	string	false	"csv or ndjson (default: csv)"
+// @Param			start_date	query	string	false	"Start date (YYYY-MM-DD)"
+// @Param			end_date	query	string	false	"End date (YYYY-MM-DD)"
+// @Param			granularity	query	string	false	"day, week or month (default: day)"
+// @Security		Bearer
+// @Success		200
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/analytics/export [get]
+func (a *AnalyticsHandler) ExportAnalytics(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	export := models.AnalyticsExport{
+		Report:      c.Query("report"),
+		Format:      c.Query("format"),
+		StartDate:   c.Query("start_date"),
+		EndDate:     c.Query("end_date"),
+		Granularity: c.Query("granularity"),
+	}
+
+	// the rows are streamed as they are read, so once the file has started an error can only cut it short
+	started := false
+	err = a.AnalyticsUseCase.ExportAnalytics(userID, export, func(filename, contentType string) io.Writer {
+		started = true
+		c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+		c.Header("Content-Type", contentType)
+		c.Status(http.StatusOK)
+		return c.Writer
+	})
+	if err != nil {
+		if started {
+			log.Println("Error exporting the analytics:", err)
+			return
+		}
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not export the analytics", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+	}
+}
+
+// @Summary		Get Report Settings
+// @Description	Creator gets whether they receive a weekly analytics report and in which format
+// @Tags			Analytics
+// @Accept			json
+// @Produce		json
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=models.AnalyticsReportSettings}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/analytics/reports/settings [get]
+func (a *AnalyticsHandler) GetReportSettings(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	settings, err := a.AnalyticsUseCase.GetReportSettings(userID)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the report settings", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Report settings retrieved successfully", settings, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Set Report Settings
+// @Description	Creator turns the weekly analytics report on or off, reports cover Monday to Sunday and are ready to download after the week
+// @Tags			Analytics
+// @Accept			json
+// @Produce		json
+// @Param			settings	body	models.AnalyticsReportSettings	true	"report settings"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/analytics/reports/settings [put]
+func (a *AnalyticsHandler) SetReportSettings(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	var settings models.AnalyticsReportSettings
+	if err := c.BindJSON(&settings); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	if err := a.AnalyticsUseCase.SetReportSettings(userID, settings); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not set the report settings", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Successfully set the report settings", nil, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		List Reports
+// @Description	Creator gets their weekly analytics reports, newest first
+// @Tags			Analytics
+// @Accept			json
+// @Produce		json
+// @Param			page	query	int	false	"Page number (default: 1)"
+// @Param			limit	query	int	false	"Limit per page (default: 20)"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=[]domain.AnalyticsReport}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/analytics/reports [get]
+func (a *AnalyticsHandler) ListReports(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	page, limit := parsePaginationParams(c)
+
+	reports, err := a.AnalyticsUseCase.ListReports(userID, page, limit)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the reports", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Reports retrieved successfully", reports, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Download Report
+// @Description	Creator downloads one of their weekly analytics reports
+// @Tags			Analytics
+// @Produce		text/csv,application/x-ndjson
+// @Param			report_id	query	int	true	"Report ID"
+// @Security		Bearer
+// @Success		200
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/analytics/reports/download [get]
+func (a *AnalyticsHandler) DownloadReport(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	reportID, err := strconv.ParseUint(c.Query("report_id"), 10, 64)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Report ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	content, filename, contentType, err := a.AnalyticsUseCase.GetReportFile(userID, uint(reportID))
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the report", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Data(http.StatusOK, contentType, content)
+}
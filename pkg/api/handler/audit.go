@@ -0,0 +1,69 @@
+package handler
+
+import (
+	services "main/pkg/usecase/interface"
+	"main/pkg/utils/models"
+	"main/pkg/utils/response"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	AuditUseCase services.AuditUseCase
+}
+
+func NewAuditHandler(usecase services.AuditUseCase) *AuditHandler {
+	return &AuditHandler{
+		AuditUseCase: usecase,
+	}
+}
+
+// @Summary		List Audit Logs
+// @Description	Admin searches the log of admin actions, the latest first. Defaults to the last 30 days
+// @Tags			Admin Audit
+// @Accept			json
+// @Produce		json
+// @Param			admin_id	query	int		false	"Admin who took the action"
+// @Param			action		query	string	false	"Action, such as user.toggle_block or category.delete"
+// @Param			target_type	query	string	false	"Target type, such as user, category or plan"
+// @Param			target_id	query	string	false	"Target ID"
+// @Param			start_date	query	string	false	"Start date (YYYY-MM-DD)"
+// @Param			end_date	query	string	false	"End date (YYYY-MM-DD)"
+// @Param			page		query	int		false	"Page number (default: 1)"
+// @Param			limit		query	int		false	"Limit per page (default: 20)"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=[]domain.AuditLog}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/audit [get]
+func (a *AuditHandler) ListLogs(c *gin.Context) {
+	filter := models.AuditFilter{
+		Action:     c.Query("action"),
+		TargetType: c.Query("target_type"),
+		TargetID:   c.Query("target_id"),
+		StartDate:  c.Query("start_date"),
+		EndDate:    c.Query("end_date"),
+	}
+	if admin := c.Query("admin_id"); admin != "" {
+		adminID, err := strconv.ParseUint(admin, 10, 64)
+		if err != nil {
+			errorRes := response.ClientResponse(http.StatusBadRequest, "Admin ID not in the right format", nil, err.Error())
+			c.JSON(http.StatusBadRequest, errorRes)
+			return
+		}
+		filter.AdminID = uint(adminID)
+	}
+
+	page, limit := parsePaginationParams(c)
+
+	logs, err := a.AuditUseCase.ListLogs(filter, page, limit)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the audit logs", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Audit logs retrieved successfully", logs, nil)
+	c.JSON(http.StatusOK, successRes)
+}
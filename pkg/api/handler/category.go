@@ -21,19 +21,47 @@ func NewCategoryHandler(usecase services.CategoryUseCase) *CategoryHandler {
 }
 
 // @Summary		Add Category
-// @Description	Admin can add new categories for contents
+// @Description	Admin can add new categories for contents, optionally under a parent category
 // @Tags			Admin Content Management
 // @Accept			json
 // @Produce		    json
 // @Param			category	query	string	true	"category"
+// @Param			parent_id	query	int	false	"parent category ID"
+// @Param			description	query	string	false	"description"
+// @Param			sort_order	query	int	false	"sort order"
 // @Security		Bearer
 // @Success		200	{object}	response.Response{}
 // @Failure		500	{object}	response.Response{}
 // @Router			/admin/category/add [post]
 func (Cat *CategoryHandler) AddCategory(c *gin.Context) {
 
-	cat := c.Query("category")
-	CategoryResponse, err := Cat.CategoryUseCase.AddCategory(cat)
+	category := models.AddCategory{
+		Category:    c.Query("category"),
+		Description: c.Query("description"),
+	}
+
+	if parentIDStr := c.Query("parent_id"); parentIDStr != "" {
+		parentID, err := strconv.ParseUint(parentIDStr, 10, 64)
+		if err != nil {
+			errorRes := response.ClientResponse(http.StatusBadRequest, "parent id not in right format", nil, err.Error())
+			c.JSON(http.StatusBadRequest, errorRes)
+			return
+		}
+		id := uint(parentID)
+		category.ParentID = &id
+	}
+
+	if sortOrderStr := c.Query("sort_order"); sortOrderStr != "" {
+		sortOrder, err := strconv.Atoi(sortOrderStr)
+		if err != nil {
+			errorRes := response.ClientResponse(http.StatusBadRequest, "sort order not in right format", nil, err.Error())
+			c.JSON(http.StatusBadRequest, errorRes)
+			return
+		}
+		category.SortOrder = sortOrder
+	}
+
+	CategoryResponse, err := Cat.CategoryUseCase.AddCategory(category)
 	if err != nil {
 		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not add the Category", nil, err.Error())
 		c.JSON(http.StatusBadRequest, errorRes)
@@ -46,18 +74,18 @@ func (Cat *CategoryHandler) AddCategory(c *gin.Context) {
 }
 
 // @Summary		Update Category
-// @Description	Admin can update name of a category into new name
+// @Description	Admin can update the name, slug, description, parent and sort order of a category by its ID, a parent_id of 0 moves it to the top level
 // @Tags			Admin Content Management
 // @Accept			json
 // @Produce		    json
-// @Param			set_new_name	body	models.SetNewName	true	"set new name"
+// @Param			update_category	body	models.UpdateCategory	true	"update category"
 // @Security		Bearer
 // @Success		200	{object}	response.Response{}
 // @Failure		500	{object}	response.Response{}
 // @Router			/admin/category/update [patch]
 func (Cat *CategoryHandler) UpdateCategory(c *gin.Context) {
 
-	var updateCategory models.SetNewName
+	var updateCategory models.UpdateCategory
 
 	if err := c.BindJSON(&updateCategory); err != nil {
 		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
@@ -65,24 +93,25 @@ func (Cat *CategoryHandler) UpdateCategory(c *gin.Context) {
 		return
 	}
 
-	a, err := Cat.CategoryUseCase.UpdateCategory(updateCategory.Current, updateCategory.New)
+	a, err := Cat.CategoryUseCase.UpdateCategory(updateCategory)
 	if err != nil {
 		errorRes := response.ClientResponse(http.StatusBadRequest, "could not update the Category", nil, err.Error())
 		c.JSON(http.StatusBadRequest, errorRes)
 		return
 	}
 
-	successRes := response.ClientResponse(http.StatusOK, "Successfully renamed the category", a, nil)
+	successRes := response.ClientResponse(http.StatusOK, "Successfully updated the category", a, nil)
 	c.JSON(http.StatusOK, successRes)
 
 }
 
 // @Summary		Delete Category
-// @Description	Admin can delete a category
+// @Description	Admin can delete a category, its videos are moved to the move_to category and its subcategories move up one level
 // @Tags			Admin Content Management
 // @Accept			json
 // @Produce		    json
 // @Param			id	query	string	true	"id"
+// @Param			move_to	query	string	false	"category ID to move the videos to, required when the category has videos"
 // @Security		Bearer
 // @Success		200	{object}	response.Response{}
 // @Failure		500	{object}	response.Response{}
@@ -90,7 +119,7 @@ func (Cat *CategoryHandler) UpdateCategory(c *gin.Context) {
 func (Cat *CategoryHandler) DeleteCategory(c *gin.Context) {
 
 	categoryID := c.Query("id")
-	err := Cat.CategoryUseCase.DeleteCategory(categoryID)
+	err := Cat.CategoryUseCase.DeleteCategory(categoryID, c.Query("move_to"))
 	if err != nil {
 		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
 		c.JSON(http.StatusBadRequest, errorRes)
@@ -102,6 +131,113 @@ func (Cat *CategoryHandler) DeleteCategory(c *gin.Context) {
 
 }
 
+// @Summary		Upload Category Icon
+// @Description	Admin can upload the icon image of a category
+// @Tags			Admin Content Management
+// @Accept			multipart/form-data
+// @Produce		    json
+// @Param			id	query	int	true	"category ID"
+// @Param			icon	formData	file	true	"icon image"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{}
+// @Failure		500	{object}	response.Response{}
+// @Router			/admin/category/icon [patch]
+func (Cat *CategoryHandler) UploadCategoryIcon(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Query("id"), 10, 64)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "category id not in right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	icon, err := c.FormFile("icon")
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Error retrieving icon from form", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	category, err := Cat.CategoryUseCase.UploadCategoryIcon(uint(categoryID), icon)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "could not upload the icon", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Successfully uploaded the icon", category, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Reorder Categories
+// @Description	Admin can set the display order of categories
+// @Tags			Admin Content Management
+// @Accept			json
+// @Produce		    json
+// @Param			orders	body	[]models.CategoryOrder	true	"category orders"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{}
+// @Failure		500	{object}	response.Response{}
+// @Router			/admin/category/reorder [patch]
+func (Cat *CategoryHandler) ReorderCategories(c *gin.Context) {
+	var orders []models.CategoryOrder
+
+	if err := c.BindJSON(&orders); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	if err := Cat.CategoryUseCase.ReorderCategories(orders); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "could not reorder the categories", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Successfully reordered the categories", nil, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Category Tree
+// @Description	View the categories with their subcategories nested, in display order
+// @Tags			User
+// @Accept			json
+// @Produce		    json
+// @Success		200	{object}	response.Response{data=[]models.CategoryTree}
+// @Failure		500	{object}	response.Response{}
+// @Router			/users/category/tree [get]
+func (cat *CategoryHandler) CategoryTree(c *gin.Context) {
+	tree, err := cat.CategoryUseCase.GetCategoryTree()
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "could not retrieve records", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Successfully retrieved the category tree", tree, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Get Category By Slug
+// @Description	View a category by its slug
+// @Tags			User
+// @Accept			json
+// @Produce		    json
+// @Param			slug	query	string	true	"slug"
+// @Success		200	{object}	response.Response{}
+// @Failure		500	{object}	response.Response{}
+// @Router			/users/category/slug [get]
+func (cat *CategoryHandler) CategoryBySlug(c *gin.Context) {
+	category, err := cat.CategoryUseCase.GetCategoryBySlug(c.Query("slug"))
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "could not retrieve the category", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Successfully retrieved the category", category, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
 // @Summary		List Categories
 // @Description	Admin can view the list of  Categories
 // @Tags			Admin Content Management
@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"main/pkg/helper"
+	services "main/pkg/usecase/interface"
+	"main/pkg/utils/models"
+	"main/pkg/utils/response"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CouponHandler struct {
+	CouponUseCase services.CouponUseCase
+}
+
+func NewCouponHandler(usecase services.CouponUseCase) *CouponHandler {
+	return &CouponHandler{
+		CouponUseCase: usecase,
+	}
+}
+
+// @Summary		Create Coupon
+// @Description	Admin adds a coupon, platform-wide without a creator_id or for one creator, e.g. a launch promotion
+// @Tags			Admin Coupons
+// @Accept			json
+// @Produce		json
+// @Param			coupon	body	models.AddCoupon	true	"coupon details"
+// @Security		Bearer
+// @Success		201	{object}	response.Response{data=domain.Coupon}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/coupons [post]
+func (cp *CouponHandler) CreateCoupon(c *gin.Context) {
+	var coupon models.AddCoupon
+	if err := c.BindJSON(&coupon); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	created, err := cp.CouponUseCase.CreateCoupon(coupon)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not create the coupon", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusCreated, "Successfully created the coupon", created, nil)
+	c.JSON(http.StatusCreated, successRes)
+}
+
+// @Summary		List Coupons
+// @Description	Admin gets all coupons, newest first
+// @Tags			Admin Coupons
+// @Accept			json
+// @Produce		json
+// @Param			page	query	int	false	"Page number (default: 1)"
+// @Param			limit	query	int	false	"Limit per page (default: 10)"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=[]domain.Coupon}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/coupons [get]
+func (cp *CouponHandler) ListCoupons(c *gin.Context) {
+	page, limit := getPaginationParams(c)
+
+	coupons, err := cp.CouponUseCase.ListCoupons(nil, page, limit)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the coupons", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Coupons retrieved successfully", coupons, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Deactivate Coupon
+// @Description	Admin stops a coupon from being applied
+// @Tags			Admin Coupons
+// @Accept			json
+// @Produce		json
+// @Param			id	query	int	true	"Coupon ID"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/coupons [delete]
+func (cp *CouponHandler) DeactivateCoupon(c *gin.Context) {
+	couponID, err := strconv.ParseUint(c.Query("id"), 10, 64)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Coupon ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	if err := cp.CouponUseCase.DeactivateCoupon(nil, uint(couponID)); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not deactivate the coupon", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Successfully deactivated the coupon", nil, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Create Creator Coupon
+// @Description	Creator adds a coupon for subscriptions to their own tiers
+// @Tags			Creator Coupons
+// @Accept			json
+// @Produce		json
+// @Param			coupon	body	models.AddCoupon	true	"coupon details, creator_id is ignored"
+// @Security		Bearer
+// @Success		201	{object}	response.Response{data=domain.Coupon}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/coupons [post]
+func (cp *CouponHandler) CreateCreatorCoupon(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	var coupon models.AddCoupon
+	if err := c.BindJSON(&coupon); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+	coupon.CreatorID = userID
+
+	created, err := cp.CouponUseCase.CreateCoupon(coupon)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not create the coupon", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusCreated, "Successfully created the coupon", created, nil)
+	c.JSON(http.StatusCreated, successRes)
+}
+
+// @Summary		List Creator Coupons
+// @Description	Creator gets their own coupons, newest first
+// @Tags			Creator Coupons
+// @Accept			json
+// @Produce		json
+// @Param			page	query	int	false	"Page number (default: 1)"
+// @Param			limit	query	int	false	"Limit per page (default: 10)"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=[]domain.Coupon}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/coupons [get]
+func (cp *CouponHandler) ListCreatorCoupons(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	page, limit := getPaginationParams(c)
+
+	coupons, err := cp.CouponUseCase.ListCoupons(&userID, page, limit)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the coupons", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Coupons retrieved successfully", coupons, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Deactivate Creator Coupon
+// @Description	Creator stops one of their coupons from being applied
+// @Tags			Creator Coupons
+// @Accept			json
+// @Produce		json
+// @Param			id	query	int	true	"Coupon ID"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/coupons [delete]
+func (cp *CouponHandler) DeactivateCreatorCoupon(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	couponID, err := strconv.ParseUint(c.Query("id"), 10, 64)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Coupon ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	if err := cp.CouponUseCase.DeactivateCoupon(&userID, uint(couponID)); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not deactivate the coupon", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Successfully deactivated the coupon", nil, nil)
+	c.JSON(http.StatusOK, successRes)
+}
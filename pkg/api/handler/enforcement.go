@@ -0,0 +1,377 @@
+package handler
+
+import (
+	"main/pkg/helper"
+	services "main/pkg/usecase/interface"
+	"main/pkg/utils/models"
+	"main/pkg/utils/response"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EnforcementHandler struct {
+	EnforcementUseCase services.EnforcementUseCase
+}
+
+func NewEnforcementHandler(usecase services.EnforcementUseCase) *EnforcementHandler {
+	return &EnforcementHandler{
+		EnforcementUseCase: usecase,
+	}
+}
+
+// @Summary		List My Enforcement Actions
+// @Description	Get the warnings, strikes, suspensions, takedowns and bans on your account with their reasons, including expired and lifted ones
+// @Tags			Enforcement
+// @Accept			json
+// @Produce		json
+// @Param			page	query	int	false	"Page number (default: 1)"
+// @Param			limit	query	int	false	"Limit per page (default: 20)"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=[]domain.EnforcementAction}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/enforcement [get]
+func (e *EnforcementHandler) ListMyActions(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	page, limit := parsePaginationParams(c)
+
+	actions, err := e.EnforcementUseCase.ListActions(userID, page, limit)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the actions", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Actions retrieved successfully", actions, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Appeal
+// @Description	Appeal an action on your account that is still in place, each action can be appealed once
+// @Tags			Enforcement
+// @Accept			json
+// @Produce		json
+// @Param			appeal	body	models.AppealRequest	true	"appeal"
+// @Security		Bearer
+// @Success		201	{object}	response.Response{data=domain.Appeal}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/enforcement/appeals [post]
+func (e *EnforcementHandler) Appeal(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	var request models.AppealRequest
+	if err := c.BindJSON(&request); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	appeal, err := e.EnforcementUseCase.Appeal(userID, request)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not submit the appeal", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusCreated, "Successfully submitted the appeal", appeal, nil)
+	c.JSON(http.StatusCreated, successRes)
+}
+
+// @Summary		Appeal Suspension
+// @Description	Appeal the suspension or ban that keeps you from logging in, you confirm who you are with your email and password
+// @Tags			Enforcement
+// @Accept			json
+// @Produce		json
+// @Param			appeal	body	models.SuspensionAppeal	true	"appeal"
+// @Success		201	{object}	response.Response{data=domain.Appeal}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/suspension/appeal [post]
+func (e *EnforcementHandler) AppealSuspension(c *gin.Context) {
+	var request models.SuspensionAppeal
+	if err := c.BindJSON(&request); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	appeal, err := e.EnforcementUseCase.AppealSuspension(request)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not submit the appeal", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusCreated, "Successfully submitted the appeal", appeal, nil)
+	c.JSON(http.StatusCreated, successRes)
+}
+
+// @Summary		List My Appeals
+// @Description	Get your appeals and the answers to them
+// @Tags			Enforcement
+// @Accept			json
+// @Produce		json
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=[]domain.Appeal}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/enforcement/appeals [get]
+func (e *EnforcementHandler) ListMyAppeals(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	appeals, err := e.EnforcementUseCase.ListUserAppeals(userID)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the appeals", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Appeals retrieved successfully", appeals, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Issue Enforcement Action
+// @Description	Admin warns, strikes, suspends or bans a user, or takes down a video or comment. Warnings and strikes expire, suspensions end after their duration and the user is told the reason
+// @Tags			Admin Enforcement
+// @Accept			json
+// @Produce		json
+// @Param			action	body	models.EnforcementRequest	true	"action"
+// @Security		Bearer
+// @Success		201	{object}	response.Response{data=domain.EnforcementAction}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/enforcement/actions [post]
+func (e *EnforcementHandler) IssueAction(c *gin.Context) {
+	adminID, err := helper.GetAdminID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get adminID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	var request models.EnforcementRequest
+	if err := c.BindJSON(&request); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	action, err := e.EnforcementUseCase.IssueAction(adminID, request)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not take the action", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusCreated, "Successfully took the action", action, nil)
+	c.JSON(http.StatusCreated, successRes)
+}
+
+// @Summary		Block or unblock User
+// @Description	Admin bans a user, or revokes the ban of a banned user. The reason is shown to the user
+// @Tags			Admin User Management
+// @Accept			json
+// @Produce		json
+// @Security		Bearer
+// @Param			id		query		int						true	"user-id"
+// @Param			block	body		models.BlockRequest		true	"block"
+// @Success		200	{object}	response.Response{data=domain.EnforcementAction}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/users/toggle-block [post]
+func (e *EnforcementHandler) ToggleBlockUser(c *gin.Context) {
+	adminID, err := helper.GetAdminID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get adminID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	userID, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "User ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	var request models.BlockRequest
+	if err := c.BindJSON(&request); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	action, banned, err := e.EnforcementUseCase.ToggleBan(adminID, userID, request.Reason)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "user could not be blocked or unblocked", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	message := "Successfully unblocked the user"
+	if banned {
+		message = "Successfully blocked the user"
+	}
+	successRes := response.ClientResponse(http.StatusOK, message, action, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		List User Enforcement Actions
+// @Description	Admin gets the actions taken against a user, the latest first
+// @Tags			Admin Enforcement
+// @Accept			json
+// @Produce		json
+// @Param			user_id	query	int	true	"User ID"
+// @Param			page	query	int	false	"Page number (default: 1)"
+// @Param			limit	query	int	false	"Limit per page (default: 20)"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=[]domain.EnforcementAction}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/enforcement/actions [get]
+func (e *EnforcementHandler) ListUserActions(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Query("user_id"))
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "User ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	page, limit := parsePaginationParams(c)
+
+	actions, err := e.EnforcementUseCase.ListActions(userID, page, limit)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the actions", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Actions retrieved successfully", actions, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Revoke Enforcement Action
+// @Description	Admin lifts an action early, taken down content is shown again and a blocked user is unblocked
+// @Tags			Admin Enforcement
+// @Accept			json
+// @Produce		json
+// @Param			action_id	query	int						true	"Action ID"
+// @Param			revoke		body	models.RevokeRequest	true	"revoke"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=domain.EnforcementAction}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/enforcement/actions/revoke [patch]
+func (e *EnforcementHandler) RevokeAction(c *gin.Context) {
+	adminID, err := helper.GetAdminID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get adminID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	actionID, err := strconv.ParseUint(c.Query("action_id"), 10, 64)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Action ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	var request models.RevokeRequest
+	if err := c.BindJSON(&request); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	action, err := e.EnforcementUseCase.RevokeAction(uint(actionID), adminID, request.Reason)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not revoke the action", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Successfully revoked the action", action, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		List Appeals
+// @Description	Admin gets the appeals, the oldest first
+// @Tags			Admin Enforcement
+// @Accept			json
+// @Produce		json
+// @Param			status	query	string	false	"pending, upheld or overturned"
+// @Param			page	query	int		false	"Page number (default: 1)"
+// @Param			limit	query	int		false	"Limit per page (default: 20)"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=[]domain.Appeal}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/enforcement/appeals [get]
+func (e *EnforcementHandler) ListAppeals(c *gin.Context) {
+	page, limit := parsePaginationParams(c)
+
+	appeals, err := e.EnforcementUseCase.ListAppeals(c.Query("status"), page, limit)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the appeals", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Appeals retrieved successfully", appeals, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Decide Appeal
+// @Description	Admin upholds or overturns an appeal, overturning it lifts the action. The user is told the response
+// @Tags			Admin Enforcement
+// @Accept			json
+// @Produce		json
+// @Param			appeal_id	query	int						true	"Appeal ID"
+// @Param			decision	body	models.AppealDecision	true	"decision"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=domain.Appeal}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/enforcement/appeals/decide [post]
+func (e *EnforcementHandler) DecideAppeal(c *gin.Context) {
+	adminID, err := helper.GetAdminID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get adminID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	appealID, err := strconv.ParseUint(c.Query("appeal_id"), 10, 64)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Appeal ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	var decision models.AppealDecision
+	if err := c.BindJSON(&decision); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	appeal, err := e.EnforcementUseCase.DecideAppeal(uint(appealID), adminID, decision)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not decide the appeal", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Successfully decided the appeal", appeal, nil)
+	c.JSON(http.StatusOK, successRes)
+}
@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"main/pkg/helper"
+	services "main/pkg/usecase/interface"
+	"main/pkg/utils/models"
+	"main/pkg/utils/response"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GiftHandler struct {
+	GiftUseCase services.GiftUseCase
+}
+
+func NewGiftHandler(usecase services.GiftUseCase) *GiftHandler {
+	return &GiftHandler{
+		GiftUseCase: usecase,
+	}
+}
+
+// @Summary		Buy Gift
+// @Description	Buy a subscription to a creator's plan for another user, or gift codes to hand out. A creator's codes for their own plans are free giveaways
+// @Tags			Gifts
+// @Accept			json
+// @Produce		json
+// @Param			order	body	models.GiftOrder	true	"gift order"
+// @Security		Bearer
+// @Success		201	{object}	response.Response{data=models.GiftCheckout}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/gifts [post]
+func (g *GiftHandler) PurchaseGift(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	var order models.GiftOrder
+	if err := c.BindJSON(&order); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	checkout, err := g.GiftUseCase.PurchaseGift(userID, order)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not buy the gift", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	message := "Pay the order to receive the gift codes"
+	if checkout.Paid {
+		message = "Successfully created the giveaway codes"
+	}
+	successRes := response.ClientResponse(http.StatusCreated, message, checkout, nil)
+	c.JSON(http.StatusCreated, successRes)
+}
+
+// @Summary		Verify Gift Payment
+// @Description	Confirm the payment of a gift purchase with the signature the checkout returned, the codes are made once it is verified
+// @Tags			Gifts
+// @Accept			json
+// @Produce		json
+// @Param			gift_id		query	int		true	"Gift purchase ID"
+// @Param			payment_id	query	string	true	"Gateway payment ID"
+// @Param			razor_id	query	string	true	"Gateway order ID"
+// @Param			signature	query	string	true	"Checkout signature"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=[]domain.GiftCode}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/gifts/verify [post]
+func (g *GiftHandler) VerifyGiftPayment(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	giftID, err := strconv.ParseUint(c.Query("gift_id"), 10, 64)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Gift ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	codes, err := g.GiftUseCase.VerifyGiftPayment(userID, uint(giftID), c.Query("payment_id"), c.Query("razor_id"), c.Query("signature"))
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "could not verify the payment", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Successfully paid for the gift", codes, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Redeem Gift Code
+// @Description	Redeem a gift code for a subscription to the creator's plan, it renews or changes a subscription the user already has
+// @Tags			Gifts
+// @Accept			json
+// @Produce		json
+// @Param			redemption	body	models.GiftRedemption	true	"gift code"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=models.RedeemedGift}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/gifts/redeem [post]
+func (g *GiftHandler) RedeemGiftCode(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	var redemption models.GiftRedemption
+	if err := c.BindJSON(&redemption); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	redeemed, err := g.GiftUseCase.RedeemGiftCode(userID, redemption.Code)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not redeem the gift code", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Successfully redeemed the gift code", redeemed, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		List Gift Purchases
+// @Description	Get the gifts the user bought, newest first
+// @Tags			Gifts
+// @Accept			json
+// @Produce		json
+// @Param			page	query	int	false	"Page number (default: 1)"
+// @Param			limit	query	int	false	"Limit per page (default: 20)"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=[]domain.GiftPurchase}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/gifts [get]
+func (g *GiftHandler) ListGiftPurchases(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	page, limit := parsePaginationParams(c)
+
+	purchases, err := g.GiftUseCase.ListGiftPurchases(userID, page, limit)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the gifts", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Gifts retrieved successfully", purchases, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		List Gift Codes
+// @Description	Get the codes of a gift purchase and whether each is available, redeemed or expired
+// @Tags			Gifts
+// @Accept			json
+// @Produce		json
+// @Param			gift_id	query	int	true	"Gift purchase ID"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=[]domain.GiftCode}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/gifts/codes [get]
+func (g *GiftHandler) ListGiftCodes(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	giftID, err := strconv.ParseUint(c.Query("gift_id"), 10, 64)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Gift ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	codes, err := g.GiftUseCase.ListGiftCodes(userID, uint(giftID))
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the gift codes", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Gift codes retrieved successfully", codes, nil)
+	c.JSON(http.StatusOK, successRes)
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"main/pkg/helper"
+	services "main/pkg/usecase/interface"
+	"main/pkg/utils/response"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InvoiceHandler struct {
+	InvoiceUseCase services.InvoiceUseCase
+}
+
+func NewInvoiceHandler(usecase services.InvoiceUseCase) *InvoiceHandler {
+	return &InvoiceHandler{
+		InvoiceUseCase: usecase,
+	}
+}
+
+// @Summary		List Invoices
+// @Description	User gets the invoices of their subscription payments, newest first, amounts are in minor units
+// @Tags			User Billing
+// @Accept			json
+// @Produce		json
+// @Param			page	query	int	false	"Page number (default: 1)"
+// @Param			limit	query	int	false	"Limit per page (default: 10)"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=[]domain.Invoice}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/billing/invoices [get]
+func (i *InvoiceHandler) ListInvoices(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	page, limit := parsePaginationParams(c)
+
+	invoices, err := i.InvoiceUseCase.ListInvoices(userID, page, limit)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the invoices", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Invoices retrieved successfully", invoices, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Download Invoice
+// @Description	User downloads an invoice as a PDF, or opens it as a web page with format=html
+// @Tags			User Billing
+// @Produce		application/pdf,text/html
+// @Param			id		query	int		true	"Invoice ID"
+// @Param			format	query	string	false	"pdf or html (default: pdf)"
+// @Security		Bearer
+// @Success		200
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/billing/invoices/download [get]
+func (i *InvoiceHandler) DownloadInvoice(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	invoiceID, err := strconv.ParseUint(c.Query("id"), 10, 64)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Invoice ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	switch c.DefaultQuery("format", "pdf") {
+	case "html":
+		view, err := i.InvoiceUseCase.GetInvoiceView(userID, uint(invoiceID))
+		if err != nil {
+			errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the invoice", nil, err.Error())
+			c.JSON(http.StatusBadRequest, errorRes)
+			return
+		}
+		c.HTML(http.StatusOK, "invoice.html", view)
+
+	case "pdf":
+		document, filename, err := i.InvoiceUseCase.GetInvoicePDF(userID, uint(invoiceID))
+		if err != nil {
+			errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the invoice", nil, err.Error())
+			c.JSON(http.StatusBadRequest, errorRes)
+			return
+		}
+		c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+		c.Data(http.StatusOK, "application/pdf", document)
+
+	default:
+		errorRes := response.ClientResponse(http.StatusBadRequest, "format must be pdf or html", nil, nil)
+		c.JSON(http.StatusBadRequest, errorRes)
+	}
+}
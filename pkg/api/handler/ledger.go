@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"main/pkg/helper"
+	services "main/pkg/usecase/interface"
+	"main/pkg/utils/models"
+	"main/pkg/utils/response"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LedgerHandler struct {
+	LedgerUseCase services.LedgerUseCase
+}
+
+func NewLedgerHandler(usecase services.LedgerUseCase) *LedgerHandler {
+	return &LedgerHandler{
+		LedgerUseCase: usecase,
+	}
+}
+
+// @Summary		List Ledger Transactions
+// @Description	Admin gets the ledger transactions with their entries, newest first, amounts are in minor units
+// @Tags			Admin Ledger
+// @Accept			json
+// @Produce		json
+// @Param			type		query	string	false	"charge, refund or payout"
+// @Param			creator_id	query	int		false	"Only transactions of this creator"
+// @Param			page		query	int		false	"Page number (default: 1)"
+// @Param			limit		query	int		false	"Limit per page (default: 10)"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=[]domain.LedgerTransaction}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/ledger/transactions [get]
+func (l *LedgerHandler) ListTransactions(c *gin.Context) {
+	page, limit := getPaginationParams(c)
+
+	creatorID := 0
+	if c.Query("creator_id") != "" {
+		id, err := strconv.Atoi(c.Query("creator_id"))
+		if err != nil {
+			errorRes := response.ClientResponse(http.StatusBadRequest, "Creator ID not in the right format", nil, err.Error())
+			c.JSON(http.StatusBadRequest, errorRes)
+			return
+		}
+		creatorID = id
+	}
+
+	transactions, err := l.LedgerUseCase.ListTransactions(c.Query("type"), creatorID, page, limit)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the ledger transactions", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Ledger transactions retrieved successfully", transactions, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Reconcile Ledger
+// @Description	Admin checks the ledger against the payment gateway for a period, the last 30 days by default
+// @Tags			Admin Ledger
+// @Accept			json
+// @Produce		json
+// @Param			start_date	query	string	false	"Start date (YYYY-MM-DD)"
+// @Param			end_date	query	string	false	"End date (YYYY-MM-DD)"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=models.LedgerReconciliation}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/ledger/reconcile [get]
+func (l *LedgerHandler) Reconcile(c *gin.Context) {
+	report, err := l.LedgerUseCase.Reconcile(c.Query("start_date"), c.Query("end_date"))
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not reconcile the ledger", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Ledger reconciled", report, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Get Platform Fee
+// @Description	Admin gets the percentage of each charge the platform keeps
+// @Tags			Admin Ledger
+// @Accept			json
+// @Produce		json
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=models.PlatformFee}
+// @Failure		500	{object}	response.Response{}
+// @Router			/admin/ledger/fee [get]
+func (l *LedgerHandler) GetPlatformFee(c *gin.Context) {
+	fee, err := l.LedgerUseCase.GetPlatformFee()
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusInternalServerError, "Could not get the platform fee", nil, err.Error())
+		c.JSON(http.StatusInternalServerError, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Platform fee retrieved successfully", fee, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Set Platform Fee
+// @Description	Admin changes the percentage the platform keeps from future charges
+// @Tags			Admin Ledger
+// @Accept			json
+// @Produce		json
+// @Param			fee	body	models.PlatformFee	true	"fee percentage"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/ledger/fee [put]
+func (l *LedgerHandler) SetPlatformFee(c *gin.Context) {
+	var fee models.PlatformFee
+	if err := c.BindJSON(&fee); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	if err := l.LedgerUseCase.SetPlatformFee(fee); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not set the platform fee", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Successfully set the platform fee", nil, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Get Earnings
+// @Description	Creator gets what their subscriptions earned, what was refunded and paid out, and the balance still owed, per currency
+// @Tags			Creator Earnings
+// @Accept			json
+// @Produce		json
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=[]models.CreatorEarnings}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/earnings [get]
+func (l *LedgerHandler) GetEarnings(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	earnings, err := l.LedgerUseCase.GetCreatorEarnings(userID)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the earnings", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Earnings retrieved successfully", earnings, nil)
+	c.JSON(http.StatusOK, successRes)
+}
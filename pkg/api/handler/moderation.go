@@ -0,0 +1,347 @@
+package handler
+
+import (
+	"main/pkg/domain"
+	"main/pkg/helper"
+	services "main/pkg/usecase/interface"
+	"main/pkg/utils/models"
+	"main/pkg/utils/response"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ModerationHandler struct {
+	ModerationUseCase services.ModerationUseCase
+}
+
+func NewModerationHandler(usecase services.ModerationUseCase) *ModerationHandler {
+	return &ModerationHandler{
+		ModerationUseCase: usecase,
+	}
+}
+
+// @Summary		Report User
+// @Description	Submit a report for a user
+// @Tags			User
+// @Accept		json
+// @Produce		json
+// @Param			targetUserID	query	int		true	"ID of the user being reported"
+// @Param			reason			query	string	true	"Reason for the report"
+// @Param			category		query	string	false	"spam, harassment, cheating, impersonation, copyright or other (default: other)"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/reportUser [post]
+func (m *ModerationHandler) ReportUser(c *gin.Context) {
+	// Get the reporter's user ID from the token
+	reporterUserID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get reporter's userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	// Get the target user's ID from the request
+	targetUserID, err := strconv.Atoi(c.Query("targetUserID"))
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Invalid targetUserID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	// Get the reason for the report from the request
+	reason := c.Query("reason")
+	if reason == "" {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Reason cannot be empty", nil, nil)
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	request := models.ReportRequest{
+		TargetType: domain.ReportTargetUser,
+		TargetID:   targetUserID,
+		Category:   c.DefaultQuery("category", domain.ReportOther),
+		Reason:     reason,
+	}
+	if _, err := m.ModerationUseCase.Report(reporterUserID, request); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not submit the report", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	// Report submitted successfully
+	successRes := response.ClientResponse(http.StatusOK, "Successfully submitted the report", nil, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Report
+// @Description	Report a user, video or comment for spam, harassment, cheating, impersonation, copyright or another reason, you are notified once it is reviewed
+// @Tags			User
+// @Accept			json
+// @Produce		json
+// @Param			report	body	models.ReportRequest	true	"report"
+// @Security		Bearer
+// @Success		201	{object}	response.Response{data=domain.Reports}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/reports [post]
+func (m *ModerationHandler) Report(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	var request models.ReportRequest
+	if err := c.BindJSON(&request); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	report, err := m.ModerationUseCase.Report(userID, request)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not submit the report", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusCreated, "Successfully submitted the report", report, nil)
+	c.JSON(http.StatusCreated, successRes)
+}
+
+// @Summary		List Moderation Cases
+// @Description	Admin gets the moderation queue, the most reported cases first, duplicate reports are grouped into one case
+// @Tags			Admin Moderation
+// @Accept			json
+// @Produce		json
+// @Param			status		query	string	false	"open, in_review, resolved or dismissed"
+// @Param			category	query	string	false	"Report category"
+// @Param			target_type	query	string	false	"user, video or comment"
+// @Param			assignee_id	query	int		false	"Admin the cases are assigned to"
+// @Param			page		query	int		false	"Page number (default: 1)"
+// @Param			limit		query	int		false	"Limit per page (default: 20)"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=[]domain.ModerationCase}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/moderation/cases [get]
+func (m *ModerationHandler) ListCases(c *gin.Context) {
+	filter := models.CaseFilter{
+		Status:     c.Query("status"),
+		Category:   c.Query("category"),
+		TargetType: c.Query("target_type"),
+	}
+	if assignee := c.Query("assignee_id"); assignee != "" {
+		assigneeID, err := strconv.ParseUint(assignee, 10, 64)
+		if err != nil {
+			errorRes := response.ClientResponse(http.StatusBadRequest, "Assignee ID not in the right format", nil, err.Error())
+			c.JSON(http.StatusBadRequest, errorRes)
+			return
+		}
+		filter.AssigneeID = uint(assigneeID)
+	}
+
+	page, limit := parsePaginationParams(c)
+
+	cases, err := m.ModerationUseCase.ListCases(filter, page, limit)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the cases", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Cases retrieved successfully", cases, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Get Moderation Case
+// @Description	Admin gets a case with its reports and notes
+// @Tags			Admin Moderation
+// @Accept			json
+// @Produce		json
+// @Param			case_id	query	int	true	"Case ID"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=domain.ModerationCase}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/moderation/case [get]
+func (m *ModerationHandler) GetCase(c *gin.Context) {
+	caseID, err := strconv.ParseUint(c.Query("case_id"), 10, 64)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Case ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	moderationCase, err := m.ModerationUseCase.GetCase(uint(caseID))
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the case", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Case retrieved successfully", moderationCase, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Assign Moderation Case
+// @Description	Admin assigns a case to an admin, themselves when no admin is given, and puts it in review
+// @Tags			Admin Moderation
+// @Accept			json
+// @Produce		json
+// @Param			case_id		query	int	true	"Case ID"
+// @Param			assignee_id	query	int	false	"Admin to assign the case to"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/moderation/cases/assign [patch]
+func (m *ModerationHandler) AssignCase(c *gin.Context) {
+	caseID, err := strconv.ParseUint(c.Query("case_id"), 10, 64)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Case ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	assigneeID, err := helper.GetAdminID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get adminID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+	if assignee := c.Query("assignee_id"); assignee != "" {
+		id, err := strconv.ParseUint(assignee, 10, 64)
+		if err != nil {
+			errorRes := response.ClientResponse(http.StatusBadRequest, "Assignee ID not in the right format", nil, err.Error())
+			c.JSON(http.StatusBadRequest, errorRes)
+			return
+		}
+		assigneeID = uint(id)
+	}
+
+	if err := m.ModerationUseCase.AssignCase(uint(caseID), &assigneeID); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not assign the case", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Successfully assigned the case", nil, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Unassign Moderation Case
+// @Description	Admin puts a case back in the queue for anyone to pick up
+// @Tags			Admin Moderation
+// @Accept			json
+// @Produce		json
+// @Param			case_id	query	int	true	"Case ID"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/moderation/cases/unassign [patch]
+func (m *ModerationHandler) UnassignCase(c *gin.Context) {
+	caseID, err := strconv.ParseUint(c.Query("case_id"), 10, 64)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Case ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	if err := m.ModerationUseCase.AssignCase(uint(caseID), nil); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not unassign the case", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Successfully unassigned the case", nil, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Add Case Note
+// @Description	Admin adds a note to a case, notes are only seen by admins
+// @Tags			Admin Moderation
+// @Accept			json
+// @Produce		json
+// @Param			case_id	query	int						true	"Case ID"
+// @Param			note	body	models.CaseNoteRequest	true	"note"
+// @Security		Bearer
+// @Success		201	{object}	response.Response{data=domain.CaseNote}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/moderation/cases/notes [post]
+func (m *ModerationHandler) AddCaseNote(c *gin.Context) {
+	adminID, err := helper.GetAdminID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get adminID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	caseID, err := strconv.ParseUint(c.Query("case_id"), 10, 64)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Case ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	var request models.CaseNoteRequest
+	if err := c.BindJSON(&request); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	note, err := m.ModerationUseCase.AddCaseNote(uint(caseID), adminID, request.Note)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not add the note", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusCreated, "Successfully added the note", note, nil)
+	c.JSON(http.StatusCreated, successRes)
+}
+
+// @Summary		Resolve Moderation Case
+// @Description	Admin closes a case by dismissing it, removing the reported video or comment, or blocking the reported user. The reporters are notified
+// @Tags			Admin Moderation
+// @Accept			json
+// @Produce		json
+// @Param			case_id		query	int						true	"Case ID"
+// @Param			resolution	body	models.CaseResolution	true	"resolution"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=domain.ModerationCase}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/moderation/cases/resolve [post]
+func (m *ModerationHandler) ResolveCase(c *gin.Context) {
+	adminID, err := helper.GetAdminID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get adminID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	caseID, err := strconv.ParseUint(c.Query("case_id"), 10, 64)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Case ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	var resolution models.CaseResolution
+	if err := c.BindJSON(&resolution); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	moderationCase, err := m.ModerationUseCase.ResolveCase(uint(caseID), adminID, resolution)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not resolve the case", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Successfully resolved the case", moderationCase, nil)
+	c.JSON(http.StatusOK, successRes)
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"main/pkg/helper"
+	services "main/pkg/usecase/interface"
+	"main/pkg/utils/response"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	NotificationUseCase services.NotificationUseCase
+}
+
+func NewNotificationHandler(usecase services.NotificationUseCase) *NotificationHandler {
+	return &NotificationHandler{
+		NotificationUseCase: usecase,
+	}
+}
+
+// @Summary      List Notifications
+// @Description  Get the user's notifications, newest first, with the number still unread
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        unread  query   bool  false  "Only unread notifications"
+// @Param        page    query   int   false  "Page number"
+// @Param        limit   query   int   false  "Limit per page"
+// @Success      200  {object} response.Response{data=models.NotificationList}
+// @Failure      400  {object} response.Response{}
+// @Router       /users/notifications [get]
+func (n *NotificationHandler) GetNotifications(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	page, limit := parsePaginationParams(c)
+	unreadOnly := c.Query("unread") == "true"
+
+	notifications, err := n.NotificationUseCase.GetNotifications(userID, unreadOnly, page, limit)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get notifications", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Notifications retrieved successfully", notifications, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary      Mark Notifications Read
+// @Description  Mark notifications as read, all of them when no IDs are given
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        ids  query   string  false  "Comma-separated notification IDs"
+// @Success      200  {object} response.Response{}
+// @Failure      400  {object} response.Response{}
+// @Router       /users/notifications/read [post]
+func (n *NotificationHandler) MarkNotificationsRead(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	var ids []uint
+	if idsParam := c.Query("ids"); idsParam != "" {
+		for _, idStr := range strings.Split(idsParam, ",") {
+			id, err := strconv.ParseUint(strings.TrimSpace(idStr), 10, 64)
+			if err != nil {
+				errorRes := response.ClientResponse(http.StatusBadRequest, "Invalid notification ID", nil, err.Error())
+				c.JSON(http.StatusBadRequest, errorRes)
+				return
+			}
+			ids = append(ids, uint(id))
+		}
+	}
+
+	if err := n.NotificationUseCase.MarkNotificationsRead(userID, ids); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not mark the notifications read", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Notifications marked read", nil, nil)
+	c.JSON(http.StatusOK, successRes)
+}
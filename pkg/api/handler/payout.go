@@ -0,0 +1,333 @@
+package handler
+
+import (
+	"main/pkg/helper"
+	services "main/pkg/usecase/interface"
+	"main/pkg/utils/models"
+	"main/pkg/utils/response"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PayoutHandler struct {
+	PayoutUseCase services.PayoutUseCase
+}
+
+func NewPayoutHandler(usecase services.PayoutUseCase) *PayoutHandler {
+	return &PayoutHandler{
+		PayoutUseCase: usecase,
+	}
+}
+
+// @Summary		Get Payout Account
+// @Description	Creator gets the account their payouts are sent to
+// @Tags			Creator Payouts
+// @Accept			json
+// @Produce		json
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=domain.PayoutAccount}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/payouts/account [get]
+func (p *PayoutHandler) GetPayoutAccount(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	account, err := p.PayoutUseCase.GetPayoutAccount(userID)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the payout account", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Payout account retrieved successfully", account, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Save Payout Account
+// @Description	Creator sets the bank account or UPI ID their payouts are sent to
+// @Tags			Creator Payouts
+// @Accept			json
+// @Produce		json
+// @Param			account	body	models.PayoutAccountDetails	true	"bank account with IFSC, or UPI ID"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=domain.PayoutAccount}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/payouts/account [put]
+func (p *PayoutHandler) SavePayoutAccount(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	var details models.PayoutAccountDetails
+	if err := c.BindJSON(&details); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	account, err := p.PayoutUseCase.SavePayoutAccount(userID, details)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not save the payout account", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Successfully saved the payout account", account, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Get Payout Balance
+// @Description	Creator gets what they are owed, split into what is on hold, in a payout and available for the next payout, per currency
+// @Tags			Creator Payouts
+// @Accept			json
+// @Produce		json
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=[]models.PayoutBalance}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/payouts/balance [get]
+func (p *PayoutHandler) GetBalance(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	balance, err := p.PayoutUseCase.GetBalance(userID)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the balance", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Balance retrieved successfully", balance, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		List Payouts
+// @Description	Creator gets their payouts, newest first, amounts are in minor units
+// @Tags			Creator Payouts
+// @Accept			json
+// @Produce		json
+// @Param			page	query	int	false	"Page number (default: 1)"
+// @Param			limit	query	int	false	"Limit per page (default: 10)"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=[]domain.Payout}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/payouts [get]
+func (p *PayoutHandler) ListCreatorPayouts(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	page, limit := parsePaginationParams(c)
+
+	payouts, err := p.PayoutUseCase.ListCreatorPayouts(userID, page, limit)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the payouts", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Payouts retrieved successfully", payouts, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Get Payout Settings
+// @Description	Admin gets the minimum payout and the hold period of new earnings
+// @Tags			Admin Payouts
+// @Accept			json
+// @Produce		json
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=models.PayoutSettings}
+// @Failure		500	{object}	response.Response{}
+// @Router			/admin/payouts/settings [get]
+func (p *PayoutHandler) GetPayoutSettings(c *gin.Context) {
+	settings, err := p.PayoutUseCase.GetPayoutSettings()
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusInternalServerError, "Could not get the payout settings", nil, err.Error())
+		c.JSON(http.StatusInternalServerError, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Payout settings retrieved successfully", settings, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Set Payout Settings
+// @Description	Admin changes the minimum payout and the hold period used by the next batches
+// @Tags			Admin Payouts
+// @Accept			json
+// @Produce		json
+// @Param			settings	body	models.PayoutSettings	true	"minimum payout and hold period"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/payouts/settings [put]
+func (p *PayoutHandler) SetPayoutSettings(c *gin.Context) {
+	var settings models.PayoutSettings
+	if err := c.BindJSON(&settings); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	if err := p.PayoutUseCase.SetPayoutSettings(settings); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not set the payout settings", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Successfully set the payout settings", nil, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Create Payout Batches
+// @Description	Admin makes the batches of last month now instead of waiting for the scheduled run, one is made per month and currency
+// @Tags			Admin Payouts
+// @Accept			json
+// @Produce		json
+// @Security		Bearer
+// @Success		201	{object}	response.Response{data=[]domain.PayoutBatch}
+// @Success		200	{object}	response.Response{data=[]domain.PayoutBatch}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/payouts/batches [post]
+func (p *PayoutHandler) CreatePayoutBatch(c *gin.Context) {
+	batches, created, err := p.PayoutUseCase.CreateMonthlyBatches()
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not create the payout batches", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	if created == 0 {
+		successRes := response.ClientResponse(http.StatusOK, "The payout batches of last month already exist", batches, nil)
+		c.JSON(http.StatusOK, successRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusCreated, "Successfully created the payout batches", batches, nil)
+	c.JSON(http.StatusCreated, successRes)
+}
+
+// @Summary		List Payout Batches
+// @Description	Admin gets the payout batches, newest first
+// @Tags			Admin Payouts
+// @Accept			json
+// @Produce		json
+// @Param			page	query	int	false	"Page number (default: 1)"
+// @Param			limit	query	int	false	"Limit per page (default: 10)"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=[]domain.PayoutBatch}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/payouts/batches [get]
+func (p *PayoutHandler) ListPayoutBatches(c *gin.Context) {
+	page, limit := parsePaginationParams(c)
+
+	batches, err := p.PayoutUseCase.ListPayoutBatches(page, limit)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the payout batches", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Payout batches retrieved successfully", batches, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Get Payout Batch
+// @Description	Admin gets a payout batch with its payouts and the accounts to pay them to
+// @Tags			Admin Payouts
+// @Accept			json
+// @Produce		json
+// @Param			id	query	int	true	"Batch ID"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=domain.PayoutBatch}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/payouts/batch [get]
+func (p *PayoutHandler) GetPayoutBatch(c *gin.Context) {
+	batchID, err := strconv.ParseUint(c.Query("id"), 10, 64)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Batch ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	batch, err := p.PayoutUseCase.GetPayoutBatch(uint(batchID))
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the payout batch", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Payout batch retrieved successfully", batch, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Approve Payout Batch
+// @Description	Admin approves a pending batch, its payouts can then be sent and marked paid
+// @Tags			Admin Payouts
+// @Accept			json
+// @Produce		json
+// @Param			id	query	int	true	"Batch ID"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/payouts/batches/approve [patch]
+func (p *PayoutHandler) ApprovePayoutBatch(c *gin.Context) {
+	batchID, err := strconv.ParseUint(c.Query("id"), 10, 64)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Batch ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	if err := p.PayoutUseCase.ApprovePayoutBatch(uint(batchID)); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not approve the payout batch", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Successfully approved the payout batch", nil, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Mark Payout Paid
+// @Description	Admin records the transfer sent for a payout of an approved batch
+// @Tags			Admin Payouts
+// @Accept			json
+// @Produce		json
+// @Param			payout	body	models.MarkPayoutPaid	true	"payout and transfer reference"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/payouts/paid [patch]
+func (p *PayoutHandler) MarkPayoutPaid(c *gin.Context) {
+	var paid models.MarkPayoutPaid
+	if err := c.BindJSON(&paid); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	if err := p.PayoutUseCase.MarkPayoutPaid(paid.PayoutID, paid.Reference); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not mark the payout paid", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Successfully marked the payout paid", nil, nil)
+	c.JSON(http.StatusOK, successRes)
+}
@@ -0,0 +1,240 @@
+package handler
+
+import (
+	"main/pkg/helper"
+	services "main/pkg/usecase/interface"
+	"main/pkg/utils/models"
+	"main/pkg/utils/response"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SearchHandler struct {
+	SearchUseCase services.SearchUseCase
+}
+
+func NewSearchHandler(usecase services.SearchUseCase) *SearchHandler {
+	return &SearchHandler{
+		SearchUseCase: usecase,
+	}
+}
+
+// SearchAll is a handler for searching videos, creators, tags and categories in one request.
+// @Summary      Unified Search
+// @Description  Search videos, creators, tags and categories, results are grouped by type with counts and video facets
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        searchTerm   query   string  false  "Search term"
+// @Param        type         query   string  false  "Result type (all, videos, creators, tags, categories)"
+// @Param        upload_date  query   string  false  "Upload date (hour, today, week, month, year)"
+// @Param        duration     query   string  false  "Duration (short, medium, long)"
+// @Param        category_id  query   int     false  "Category ID"
+// @Param        access       query   string  false  "Access (exclusive, free)"
+// @Param        creator_id   query   int     false  "Creator ID"
+// @Param        page         query   int     false  "Page number"
+// @Param        limit        query   int     false  "Limit per page"
+// @Success      200  {object} response.Response{data=models.SearchResults}
+// @Failure      400  {object} response.Response{}
+// @Router       /users/search/all [get]
+func (s *SearchHandler) SearchAll(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	page, limit := parsePaginationParams(c)
+
+	filter := models.SearchFilter{
+		Query:      c.Query("searchTerm"),
+		Type:       c.Query("type"),
+		UploadDate: c.Query("upload_date"),
+		Duration:   c.Query("duration"),
+		Access:     c.Query("access"),
+		Page:       page,
+		Limit:      limit,
+	}
+
+	if categoryIDStr := c.Query("category_id"); categoryIDStr != "" {
+		categoryID, err := strconv.Atoi(categoryIDStr)
+		if err != nil {
+			errorRes := response.ClientResponse(http.StatusBadRequest, "Category ID not in the right format", nil, err.Error())
+			c.JSON(http.StatusBadRequest, errorRes)
+			return
+		}
+		filter.CategoryID = categoryID
+	}
+
+	if creatorIDStr := c.Query("creator_id"); creatorIDStr != "" {
+		creatorID, err := strconv.Atoi(creatorIDStr)
+		if err != nil {
+			errorRes := response.ClientResponse(http.StatusBadRequest, "Creator ID not in the right format", nil, err.Error())
+			c.JSON(http.StatusBadRequest, errorRes)
+			return
+		}
+		filter.CreatorID = creatorID
+	}
+
+	results, err := s.SearchUseCase.SearchAll(userID, filter)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not search", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Successfully retrieved search results", results, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// Suggest is a handler for search autocomplete and spelling corrections.
+// @Summary      Search Suggestions
+// @Description  Prefix completions from video titles, creator usernames and tags ranked by popularity, with "did you mean" corrections
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        q      query   string  true   "Partial search query"
+// @Param        limit  query   int     false  "Maximum number of suggestions (default: 5)"
+// @Success      200  {object} response.Response{data=models.SuggestResults}
+// @Failure      400  {object} response.Response{}
+// @Router       /users/search/suggest [get]
+func (s *SearchHandler) Suggest(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "5"))
+
+	suggestions, err := s.SearchUseCase.Suggest(c.Query("q"), limit)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get suggestions", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Successfully retrieved suggestions", suggestions, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// RecordClick is a handler for recording which search result the user opened.
+// @Summary      Record Search Click
+// @Description  Record the result the user opened from a search, used for click-through analytics
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        search_id  query   int     true  "Search ID returned by the search"
+// @Param        type       query   string  true  "Result type (video, creator, tag, category)"
+// @Param        id         query   int     true  "Result ID"
+// @Success      200  {object} response.Response{}
+// @Failure      400  {object} response.Response{}
+// @Router       /users/search/click [post]
+func (s *SearchHandler) RecordClick(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	searchID, err := strconv.ParseUint(c.Query("search_id"), 10, 64)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Search ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	resultID, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Result ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	if err := s.SearchUseCase.RecordClick(userID, uint(searchID), c.Query("type"), resultID); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not record the click", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Successfully recorded the click", nil, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Top Search Queries
+// @Description	Get the most searched queries with result and click-through statistics
+// @Tags			Admin Search Analytics
+// @Accept		json
+// @Produce		json
+// @Param			start_date	query	string	false	"Start date (YYYY-MM-DD, default: 30 days ago)"
+// @Param			end_date	query	string	false	"End date (YYYY-MM-DD, default: today)"
+// @Param			limit	query	int	false	"Number of queries (default: 20)"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=[]models.SearchQueryStat}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/search/top [get]
+func (s *SearchHandler) TopQueries(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	stats, err := s.SearchUseCase.TopQueries(c.Query("start_date"), c.Query("end_date"), limit)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get top queries", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Top queries retrieved successfully", stats, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Zero Result Search Queries
+// @Description	Get the most searched queries that returned no results
+// @Tags			Admin Search Analytics
+// @Accept		json
+// @Produce		json
+// @Param			start_date	query	string	false	"Start date (YYYY-MM-DD, default: 30 days ago)"
+// @Param			end_date	query	string	false	"End date (YYYY-MM-DD, default: today)"
+// @Param			limit	query	int	false	"Number of queries (default: 20)"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=[]models.SearchQueryStat}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/search/zero-results [get]
+func (s *SearchHandler) ZeroResultQueries(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	stats, err := s.SearchUseCase.ZeroResultQueries(c.Query("start_date"), c.Query("end_date"), limit)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get zero result queries", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Zero result queries retrieved successfully", stats, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Search Trends
+// @Description	Get the search volume over time, optionally for a single query
+// @Tags			Admin Search Analytics
+// @Accept		json
+// @Produce		json
+// @Param			interval	query	string	false	"Interval (hour, day, week, month, default: day)"
+// @Param			start_date	query	string	false	"Start date (YYYY-MM-DD, default: 30 days ago)"
+// @Param			end_date	query	string	false	"End date (YYYY-MM-DD, default: today)"
+// @Param			query	query	string	false	"Only count this query"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=[]models.SearchTrendPoint}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/search/trends [get]
+func (s *SearchHandler) SearchTrends(c *gin.Context) {
+	trends, err := s.SearchUseCase.SearchTrends(c.Query("interval"), c.Query("start_date"), c.Query("end_date"), c.Query("query"))
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get search trends", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Search trends retrieved successfully", trends, nil)
+	c.JSON(http.StatusOK, successRes)
+}
@@ -1,9 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
+	"main/pkg/domain"
 	"main/pkg/helper"
+	"main/pkg/payments"
 	services "main/pkg/usecase/interface"
+	"main/pkg/utils/models"
 	"main/pkg/utils/response"
 	"net/http"
 	"strconv"
@@ -28,8 +32,9 @@ func NewSubscriptionHandler(usecase services.SubscriptionUseCase) *SubscriptionH
 // @Produce json
 // @Param creator_id query int true "creator ID"
 // @Param plan_id query int true "Subscription plan ID"
+// @Param code query string false "Coupon code"
 // @Security Bearer
-// @Success 200 {object} response.Response{}
+// @Success 200 {object} response.Response{data=models.PurchaseResult}
 // @Failure 400 {object} response.Response{}
 // @Failure 500 {object} response.Response{}
 // @Router /users/plans/choose-plan [post]
@@ -77,14 +82,19 @@ func (s *SubscriptionHandler) ChoosePlan(c *gin.Context) {
 
 	// Add more log statements as needed for debugging
 
-	retString, err := s.SubscriptioneUseCase.PurchasePlan(planID, creatorID, userID)
+	result, err := s.SubscriptioneUseCase.PurchasePlan(planID, creatorID, userID, c.Query("code"))
 	if err != nil {
 		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not make the order", nil, err.Error())
 		c.JSON(http.StatusBadRequest, errorRes)
 		return
 	}
 
-	successRes := response.ClientResponse(http.StatusOK, "Successfully chose the subscription plan", retString, nil)
+	message := "Successfully chose the subscription plan"
+	if result.Kind == domain.SubscriptionKindTrial {
+		message = "Successfully started the free trial"
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, message, result, nil)
 	c.JSON(http.StatusOK, successRes)
 }
 
@@ -100,26 +110,66 @@ func (s *SubscriptionHandler) MakePaymentRazorPay(c *gin.Context) {
 		return
 	}
 
-	orderDetail, err := s.SubscriptioneUseCase.MakePaymentRazorPay(planID, userID)
+	orderDetail, err := s.SubscriptioneUseCase.MakePaymentRazorPay(planID, userID, c.Query("code"))
 	if err != nil {
 		errorRes := response.ClientResponse(http.StatusInternalServerError, "could not generate order details", nil, err.Error())
 		c.JSON(http.StatusInternalServerError, errorRes)
 		return
 	}
 
+	if orderDetail.Paid {
+		successRes := response.ClientResponse(http.StatusOK, "The coupon covers the whole price, the subscription is active", orderDetail, nil)
+		c.JSON(http.StatusOK, successRes)
+		return
+	}
+
+	// The fake gateway has its own checkout page that completes the payment without leaving the site
+	if orderDetail.Gateway == "fake" {
+		c.HTML(http.StatusOK, "fakepay.html", orderDetail)
+		return
+	}
+
 	c.HTML(http.StatusOK, "razorpay.html", orderDetail)
 }
 
+// @Summary Simulate Payment
+// @Description Complete a checkout without a real payment, only available when the fake payment gateway is configured
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param razor_id query string true "Gateway order ID"
+// @Security Bearer
+// @Success 200 {object} response.Response{data=models.SimulatedPayment}
+// @Failure 400 {object} response.Response{}
+// @Router /users/plans/fake-pay [post]
+func (s *SubscriptionHandler) SimulatePayment(c *gin.Context) {
+	payment, err := s.SubscriptioneUseCase.SimulatePayment(c.Query("razor_id"))
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "could not simulate the payment", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Successfully simulated the payment", payment, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
 func (s *SubscriptionHandler) VerifyPayment(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
 
 	planID := c.Query("order_id")
 	paymentID := c.Query("payment_id")
 	razorID := c.Query("razor_id")
-	fmt.Println(paymentID, razorID, planID)
-	err := s.SubscriptioneUseCase.VerifyPayment(paymentID, razorID, planID)
+	signature := c.Query("signature")
+	err = s.SubscriptioneUseCase.VerifyPayment(userID, paymentID, razorID, planID, signature)
 	if err != nil {
-		errorRes := response.ClientResponse(http.StatusInternalServerError, "could not update payment details", nil, err.Error())
-		c.JSON(http.StatusInternalServerError, errorRes)
+		errorRes := response.ClientResponse(http.StatusBadRequest, "could not verify the payment", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
 		return
 	}
 
@@ -129,6 +179,181 @@ func (s *SubscriptionHandler) VerifyPayment(c *gin.Context) {
 
 }
 
+// @Summary Payment Webhook
+// @Description Receives payment.captured, payment.failed and refund.processed events from the payment gateway, the body must be signed with the webhook secret
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param X-Razorpay-Signature header string true "Webhook signature"
+// @Param X-Razorpay-Event-Id header string false "Event ID"
+// @Success 200 {object} response.Response{}
+// @Failure 400 {object} response.Response{}
+// @Failure 401 {object} response.Response{}
+// @Router /payments/webhook [post]
+func (s *SubscriptionHandler) PaymentWebhook(c *gin.Context) {
+	body, err := c.GetRawData()
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "could not read the webhook", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	err = s.SubscriptioneUseCase.HandleWebhook(body, c.GetHeader("X-Razorpay-Signature"), c.GetHeader("X-Razorpay-Event-Id"))
+	if errors.Is(err, payments.ErrInvalidSignature) {
+		errorRes := response.ClientResponse(http.StatusUnauthorized, "invalid webhook signature", nil, err.Error())
+		c.JSON(http.StatusUnauthorized, errorRes)
+		return
+	}
+	if err != nil {
+		// A non 2xx status makes the gateway deliver the event again
+		errorRes := response.ClientResponse(http.StatusInternalServerError, "could not process the webhook", nil, err.Error())
+		c.JSON(http.StatusInternalServerError, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Webhook processed", nil, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary List Payment Events
+// @Description Get the stored payment webhooks, newest first
+// @Tags Admin Payments
+// @Accept json
+// @Produce json
+// @Param status query string false "Status (received, processed, ignored, failed)"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Limit per page (default: 20)"
+// @Security Bearer
+// @Success 200 {object} response.Response{}
+// @Failure 400 {object} response.Response{}
+// @Router /admin/payments/events [get]
+func (s *SubscriptionHandler) ListPaymentEvents(c *gin.Context) {
+	page, limit := parsePaginationParams(c)
+
+	events, err := s.SubscriptioneUseCase.ListPaymentEvents(c.Query("status"), page, limit)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "could not get payment events", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Payment events retrieved successfully", events, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary Replay Payment Event
+// @Description Apply a stored payment webhook again
+// @Tags Admin Payments
+// @Accept json
+// @Produce json
+// @Param id query int true "Payment event ID"
+// @Security Bearer
+// @Success 200 {object} response.Response{}
+// @Failure 400 {object} response.Response{}
+// @Router /admin/payments/events/replay [post]
+func (s *SubscriptionHandler) ReplayPaymentEvent(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Query("id"), 10, 64)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Event ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	event, err := s.SubscriptioneUseCase.ReplayPaymentEvent(uint(eventID))
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "could not replay the payment event", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Payment event replayed successfully", event, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary Refund Subscription
+// @Description Refund all or part of a subscription's payment, the subscriber keeps access until the period ends unless it is revoked
+// @Tags Admin Payments
+// @Accept json
+// @Produce json
+// @Param refund body models.RefundRequest true "subscription, amount (0 refunds what is left) and whether to revoke access"
+// @Security Bearer
+// @Success 200 {object} response.Response{data=domain.PaymentRefund}
+// @Failure 400 {object} response.Response{}
+// @Router /admin/payments/refunds [post]
+func (s *SubscriptionHandler) RefundSubscription(c *gin.Context) {
+	var request models.RefundRequest
+	if err := c.BindJSON(&request); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	refund, err := s.SubscriptioneUseCase.RefundSubscription(request)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "could not refund the subscription", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Subscription refunded successfully", refund, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary List Disputes
+// @Description Get the payment disputes, newest first
+// @Tags Admin Payments
+// @Accept json
+// @Produce json
+// @Param needs_review query bool false "Only disputes waiting for a review"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Limit per page (default: 20)"
+// @Security Bearer
+// @Success 200 {object} response.Response{data=[]domain.PaymentDispute}
+// @Failure 400 {object} response.Response{}
+// @Router /admin/payments/disputes [get]
+func (s *SubscriptionHandler) ListDisputes(c *gin.Context) {
+	page, limit := parsePaginationParams(c)
+	needsReview := c.Query("needs_review") == "true"
+
+	disputes, err := s.SubscriptioneUseCase.ListDisputes(needsReview, page, limit)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "could not get the disputes", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Disputes retrieved successfully", disputes, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary Review Dispute
+// @Description Close the review of a dispute, reinstating or cancelling the subscription it suspended
+// @Tags Admin Payments
+// @Accept json
+// @Produce json
+// @Param review body models.DisputeReview true "dispute, whether to reinstate the subscription and a note"
+// @Security Bearer
+// @Success 200 {object} response.Response{}
+// @Failure 400 {object} response.Response{}
+// @Router /admin/payments/disputes/review [patch]
+func (s *SubscriptionHandler) ReviewDispute(c *gin.Context) {
+	var review models.DisputeReview
+	if err := c.BindJSON(&review); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	if err := s.SubscriptioneUseCase.ReviewDispute(review); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "could not review the dispute", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Dispute reviewed successfully", nil, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
 // // HandleUpdateSubscriptionStatus handles the manual update trigger
 // func (h *SubscriptionHandler) HandleUpdateSubscriptionStatus(c *gin.Context) {
 // 	// Perform any authentication or authorization checks if needed
@@ -142,6 +367,104 @@ func (s *SubscriptionHandler) VerifyPayment(c *gin.Context) {
 
 // HandleActivationJob handles the job triggered by the cron job
 
+// @Summary List Subscriptions
+// @Description Get the user's subscriptions with their status and current period
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} response.Response{data=[]models.UserSubscription}
+// @Failure 400 {object} response.Response{}
+// @Router /users/subscriptions [get]
+func (s *SubscriptionHandler) ListSubscriptions(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	subscriptions, err := s.SubscriptioneUseCase.ListSubscriptions(userID)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get subscriptions", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Subscriptions retrieved successfully", subscriptions, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary Cancel Subscription
+// @Description Cancel a subscription at the end of the paid period, a past due subscription is cancelled right away
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param subscription_id query int true "Subscription ID"
+// @Security Bearer
+// @Success 200 {object} response.Response{}
+// @Failure 400 {object} response.Response{}
+// @Router /users/subscriptions/cancel [post]
+func (s *SubscriptionHandler) CancelSubscription(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	subscriptionID, err := strconv.Atoi(c.Query("subscription_id"))
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Invalid subscription ID format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	if err := s.SubscriptioneUseCase.CancelSubscription(userID, subscriptionID); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not cancel the subscription", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Subscription cancelled successfully", nil, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary Resume Subscription
+// @Description Undo a cancellation that has not taken effect yet
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param subscription_id query int true "Subscription ID"
+// @Security Bearer
+// @Success 200 {object} response.Response{}
+// @Failure 400 {object} response.Response{}
+// @Router /users/subscriptions/resume [post]
+func (s *SubscriptionHandler) ResumeSubscription(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	subscriptionID, err := strconv.Atoi(c.Query("subscription_id"))
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Invalid subscription ID format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	if err := s.SubscriptioneUseCase.ResumeSubscription(userID, subscriptionID); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not resume the subscription", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Subscription resumed successfully", nil, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
 // @Summary Get Analytics
 // @Description Get analytics data for subscribers count, revenue, and more
 // @Tags User
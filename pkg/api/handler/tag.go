@@ -0,0 +1,245 @@
+package handler
+
+import (
+	services "main/pkg/usecase/interface"
+	"main/pkg/utils/response"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TagHandler struct {
+	TagUseCase services.TagUseCase
+}
+
+func NewTagHandler(usecase services.TagUseCase) *TagHandler {
+	return &TagHandler{
+		TagUseCase: usecase,
+	}
+}
+
+// @Summary		Tag Usage Stats
+// @Description	Get the canonical tags with the number of videos, followers and aliases of each, most used first
+// @Tags			Admin Tag Management
+// @Accept		json
+// @Produce		json
+// @Param			page	query	int	false	"Page number (default: 1)"
+// @Param			limit	query	int	false	"Limit per page (default: 20)"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=[]models.TagStat}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/tags/stats [get]
+func (t *TagHandler) GetTagStats(c *gin.Context) {
+	page, limit := parsePaginationParams(c)
+
+	stats, err := t.TagUseCase.GetTagStats(page, limit)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get tag stats", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Tag stats retrieved successfully", stats, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Add Tag Alias
+// @Description	Add another spelling that resolves to a canonical tag
+// @Tags			Admin Tag Management
+// @Accept		json
+// @Produce		json
+// @Param			tag_id	query	int	true	"Canonical tag ID"
+// @Param			alias	query	string	true	"Alias"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/tags/aliases [post]
+func (t *TagHandler) AddAlias(c *gin.Context) {
+	tagID, err := strconv.ParseUint(c.Query("tag_id"), 10, 64)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Tag ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	alias, err := t.TagUseCase.AddAlias(uint(tagID), c.Query("alias"))
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not add the alias", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Alias added successfully", alias, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		List Tag Aliases
+// @Description	Get the aliases of a canonical tag
+// @Tags			Admin Tag Management
+// @Accept		json
+// @Produce		json
+// @Param			tag_id	query	int	true	"Canonical tag ID"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/tags/aliases [get]
+func (t *TagHandler) GetAliases(c *gin.Context) {
+	tagID, err := strconv.ParseUint(c.Query("tag_id"), 10, 64)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Tag ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	aliases, err := t.TagUseCase.GetAliases(uint(tagID))
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the aliases", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Aliases retrieved successfully", aliases, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Delete Tag Alias
+// @Description	Delete an alias of a canonical tag
+// @Tags			Admin Tag Management
+// @Accept		json
+// @Produce		json
+// @Param			id	query	int	true	"Alias ID"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/tags/aliases [delete]
+func (t *TagHandler) DeleteAlias(c *gin.Context) {
+	aliasID, err := strconv.ParseUint(c.Query("id"), 10, 64)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Alias ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	if err := t.TagUseCase.DeleteAlias(uint(aliasID)); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not delete the alias", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Alias deleted successfully", nil, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Merge Tags
+// @Description	Merge a tag into another, its videos, followers and aliases move to the target and its name becomes an alias
+// @Tags			Admin Tag Management
+// @Accept		json
+// @Produce		json
+// @Param			source_id	query	int	true	"Tag ID to merge"
+// @Param			target_id	query	int	true	"Tag ID to merge into"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=models.TagMergeResult}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/tags/merge [post]
+func (t *TagHandler) MergeTags(c *gin.Context) {
+	sourceID, err := strconv.ParseUint(c.Query("source_id"), 10, 64)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Source tag ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	targetID, err := strconv.ParseUint(c.Query("target_id"), 10, 64)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Target tag ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	result, err := t.TagUseCase.MergeTags(uint(sourceID), uint(targetID))
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not merge the tags", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Tags merged successfully", result, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		List Banned Tags
+// @Description	Get the tags creators are not allowed to use
+// @Tags			Admin Tag Management
+// @Accept		json
+// @Produce		json
+// @Param			page	query	int	false	"Page number (default: 1)"
+// @Param			limit	query	int	false	"Limit per page (default: 20)"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/tags/banned [get]
+func (t *TagHandler) GetBannedTags(c *gin.Context) {
+	page, limit := parsePaginationParams(c)
+
+	banned, err := t.TagUseCase.GetBannedTags(page, limit)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get banned tags", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Banned tags retrieved successfully", banned, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Ban Tag
+// @Description	Ban a tag, it is removed from every video and creators can no longer use it
+// @Tags			Admin Tag Management
+// @Accept		json
+// @Produce		json
+// @Param			tag	query	string	true	"Tag"
+// @Param			reason	query	string	false	"Reason"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/tags/banned [post]
+func (t *TagHandler) BanTag(c *gin.Context) {
+	banned, err := t.TagUseCase.BanTag(c.Query("tag"), c.Query("reason"))
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not ban the tag", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Tag banned successfully", banned, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Unban Tag
+// @Description	Remove a tag from the banned list
+// @Tags			Admin Tag Management
+// @Accept		json
+// @Produce		json
+// @Param			id	query	int	true	"Banned tag ID"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/tags/banned [delete]
+func (t *TagHandler) UnbanTag(c *gin.Context) {
+	bannedID, err := strconv.ParseUint(c.Query("id"), 10, 64)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Banned tag ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	if err := t.TagUseCase.UnbanTag(uint(bannedID)); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not unban the tag", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Tag unbanned successfully", nil, nil)
+	c.JSON(http.StatusOK, successRes)
+}
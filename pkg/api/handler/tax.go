@@ -0,0 +1,91 @@
+package handler
+
+import (
+	services "main/pkg/usecase/interface"
+	"main/pkg/utils/models"
+	"main/pkg/utils/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TaxHandler struct {
+	TaxUseCase services.TaxUseCase
+}
+
+func NewTaxHandler(usecase services.TaxUseCase) *TaxHandler {
+	return &TaxHandler{
+		TaxUseCase: usecase,
+	}
+}
+
+// @Summary		List Tax Rules
+// @Description	Admin gets the tax charged in each region, region * applies to every region without its own rule
+// @Tags			Admin Taxes
+// @Accept			json
+// @Produce		json
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=[]domain.TaxRule}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/taxes [get]
+func (t *TaxHandler) ListTaxRules(c *gin.Context) {
+	rules, err := t.TaxUseCase.ListTaxRules()
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the tax rules", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Tax rules retrieved successfully", rules, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Set Tax Rule
+// @Description	Admin sets the tax of a region, e.g. GST at 18% included in the price for IN. It applies from the next checkout on
+// @Tags			Admin Taxes
+// @Accept			json
+// @Produce		json
+// @Param			rule	body	models.TaxRuleDetails	true	"tax rule"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=domain.TaxRule}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/taxes [put]
+func (t *TaxHandler) SetTaxRule(c *gin.Context) {
+	var details models.TaxRuleDetails
+	if err := c.BindJSON(&details); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	rule, err := t.TaxUseCase.SetTaxRule(details)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not set the tax rule", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Successfully set the tax rule", rule, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Delete Tax Rule
+// @Description	Admin stops charging tax in a region, the default rule then applies there if one is set
+// @Tags			Admin Taxes
+// @Accept			json
+// @Produce		json
+// @Param			region	query	string	true	"Country code or *"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/taxes [delete]
+func (t *TaxHandler) DeleteTaxRule(c *gin.Context) {
+	if err := t.TaxUseCase.DeleteTaxRule(c.Query("region")); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not delete the tax rule", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Successfully deleted the tax rule", nil, nil)
+	c.JSON(http.StatusOK, successRes)
+}
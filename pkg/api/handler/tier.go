@@ -0,0 +1,294 @@
+package handler
+
+import (
+	"main/pkg/helper"
+	services "main/pkg/usecase/interface"
+	"main/pkg/utils/models"
+	"main/pkg/utils/response"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TierHandler struct {
+	TierUseCase services.TierUseCase
+}
+
+func NewTierHandler(usecase services.TierUseCase) *TierHandler {
+	return &TierHandler{
+		TierUseCase: usecase,
+	}
+}
+
+// @Summary		Create Tier
+// @Description	Creator adds a subscription tier, the price must be within the range set by the admins
+// @Tags			Creator Tiers
+// @Accept			json
+// @Produce		json
+// @Param			tier	body	models.AddTier	true	"tier details"
+// @Security		Bearer
+// @Success		201	{object}	response.Response{data=models.Tier}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/tiers [post]
+func (t *TierHandler) CreateTier(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	var tier models.AddTier
+	if err := c.BindJSON(&tier); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	created, err := t.TierUseCase.CreateTier(userID, tier)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not create the tier", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusCreated, "Successfully created the tier", created, nil)
+	c.JSON(http.StatusCreated, successRes)
+}
+
+// @Summary		Update Tier
+// @Description	Creator changes a tier, only the fields that are set are updated
+// @Tags			Creator Tiers
+// @Accept			json
+// @Produce		json
+// @Param			tier	body	models.UpdateTier	true	"tier details"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=models.Tier}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/tiers [patch]
+func (t *TierHandler) UpdateTier(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	var tier models.UpdateTier
+	if err := c.BindJSON(&tier); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	updated, err := t.TierUseCase.UpdateTier(userID, tier)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not update the tier", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Successfully updated the tier", updated, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Delete Tier
+// @Description	Creator deletes a tier, a tier with subscriptions is archived so it can no longer be bought
+// @Tags			Creator Tiers
+// @Accept			json
+// @Produce		json
+// @Param			id	query	int	true	"Tier ID"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/tiers [delete]
+func (t *TierHandler) DeleteTier(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	tierID, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Tier ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	archived, err := t.TierUseCase.DeleteTier(userID, tierID)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not delete the tier", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	message := "Successfully deleted the tier"
+	if archived {
+		message = "The tier has subscriptions, it was archived instead of deleted"
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, message, nil, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		List Tiers
+// @Description	Get the tiers a creator offers, cheapest first, the caller's own tiers when no creator is given
+// @Tags			Creator Tiers
+// @Accept			json
+// @Produce		json
+// @Param			creator_id	query	int	false	"Creator ID"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=[]models.Tier}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/tiers [get]
+func (t *TierHandler) ListTiers(c *gin.Context) {
+	creatorID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	if creatorIDStr := c.Query("creator_id"); creatorIDStr != "" {
+		creatorID, err = strconv.Atoi(creatorIDStr)
+		if err != nil {
+			errorRes := response.ClientResponse(http.StatusBadRequest, "Creator ID not in the right format", nil, err.Error())
+			c.JSON(http.StatusBadRequest, errorRes)
+			return
+		}
+	}
+
+	tiers, err := t.TierUseCase.ListTiers(creatorID)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the tiers", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Tiers retrieved successfully", tiers, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Set Tier Videos
+// @Description	Creator sets the exclusive videos a tier unlocks, replacing the current set
+// @Tags			Creator Tiers
+// @Accept			json
+// @Produce		json
+// @Param			tier_videos	body	models.TierVideos	true	"tier and video IDs"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=[]uint}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/tiers/videos [put]
+func (t *TierHandler) SetTierVideos(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	var tierVideos models.TierVideos
+	if err := c.BindJSON(&tierVideos); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	videoIDs, err := t.TierUseCase.SetTierVideos(userID, tierVideos)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not set the tier videos", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Successfully set the tier videos", videoIDs, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Set Tier Prices
+// @Description	Creator sets what buyers in other regions pay for a tier, replacing its regional prices. Other regions pay the tier's own price
+// @Tags			Creator Tiers
+// @Accept			json
+// @Produce		json
+// @Param			tier_prices	body	models.TierPrices	true	"tier and its regional prices"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=map[string]money.Money}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/tiers/prices [put]
+func (t *TierHandler) SetTierPrices(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	var tierPrices models.TierPrices
+	if err := c.BindJSON(&tierPrices); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	prices, err := t.TierUseCase.SetTierPrices(userID, tierPrices)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not set the tier prices", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Successfully set the tier prices", prices, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Get Tier Price Limits
+// @Description	Get the lowest and highest price creators can set on a tier
+// @Tags			Admin
+// @Accept			json
+// @Produce		json
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=models.TierPriceLimits}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/plans/limits [get]
+func (t *TierHandler) GetTierPriceLimits(c *gin.Context) {
+	limits, err := t.TierUseCase.GetTierPriceLimits()
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the price limits", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Price limits retrieved successfully", limits, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Set Tier Price Limits
+// @Description	Set the lowest and highest price creators can set on a tier, existing tiers keep their price until edited
+// @Tags			Admin
+// @Accept			json
+// @Produce		json
+// @Param			limits	body	models.TierPriceLimits	true	"price limits"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/plans/limits [put]
+func (t *TierHandler) SetTierPriceLimits(c *gin.Context) {
+	var limits models.TierPriceLimits
+	if err := c.BindJSON(&limits); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	if err := t.TierUseCase.SetTierPriceLimits(limits); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not set the price limits", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Successfully set the price limits", limits, nil)
+	c.JSON(http.StatusOK, successRes)
+}
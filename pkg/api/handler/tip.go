@@ -0,0 +1,243 @@
+package handler
+
+import (
+	"main/pkg/helper"
+	services "main/pkg/usecase/interface"
+	"main/pkg/utils/models"
+	"main/pkg/utils/response"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TipHandler struct {
+	TipUseCase services.TipUseCase
+}
+
+func NewTipHandler(usecase services.TipUseCase) *TipHandler {
+	return &TipHandler{
+		TipUseCase: usecase,
+	}
+}
+
+// @Summary		Send Tip
+// @Description	Tip a creator once in the platform currency, optionally on one of their videos or on your comment there, which is then highlighted
+// @Tags			Tips
+// @Accept			json
+// @Produce		json
+// @Param			tip	body	models.TipOrder	true	"tip"
+// @Security		Bearer
+// @Success		201	{object}	response.Response{data=models.TipCheckout}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/tips [post]
+func (t *TipHandler) SendTip(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	var order models.TipOrder
+	if err := c.BindJSON(&order); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	checkout, err := t.TipUseCase.SendTip(userID, order)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not send the tip", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusCreated, "Pay the order to send the tip", checkout, nil)
+	c.JSON(http.StatusCreated, successRes)
+}
+
+// @Summary		Verify Tip Payment
+// @Description	Confirm the payment of a tip with the signature the checkout returned
+// @Tags			Tips
+// @Accept			json
+// @Produce		json
+// @Param			tip_id		query	int		true	"Tip ID"
+// @Param			payment_id	query	string	true	"Gateway payment ID"
+// @Param			razor_id	query	string	true	"Gateway order ID"
+// @Param			signature	query	string	true	"Checkout signature"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=domain.Tip}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/tips/verify [post]
+func (t *TipHandler) VerifyTipPayment(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	tipID, err := strconv.ParseUint(c.Query("tip_id"), 10, 64)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Tip ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	tip, err := t.TipUseCase.VerifyTipPayment(userID, uint(tipID), c.Query("payment_id"), c.Query("razor_id"), c.Query("signature"))
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "could not verify the payment", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Successfully sent the tip", tip, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		List Sent Tips
+// @Description	Get the tips the user paid, newest first
+// @Tags			Tips
+// @Accept			json
+// @Produce		json
+// @Param			page	query	int	false	"Page number (default: 1)"
+// @Param			limit	query	int	false	"Limit per page (default: 20)"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=[]models.TipDetails}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/tips/sent [get]
+func (t *TipHandler) ListSentTips(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	page, limit := parsePaginationParams(c)
+
+	tips, err := t.TipUseCase.ListSentTips(userID, page, limit)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the tips", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Tips retrieved successfully", tips, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		List Received Tips
+// @Description	Creator gets the tips paid to them, newest first, anonymous tips do not show who sent them
+// @Tags			Tips
+// @Accept			json
+// @Produce		json
+// @Param			page	query	int	false	"Page number (default: 1)"
+// @Param			limit	query	int	false	"Limit per page (default: 20)"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=[]models.TipDetails}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/tips/received [get]
+func (t *TipHandler) ListReceivedTips(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	page, limit := parsePaginationParams(c)
+
+	tips, err := t.TipUseCase.ListReceivedTips(userID, page, limit)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the tips", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Tips retrieved successfully", tips, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		List Video Tips
+// @Description	Get the tips left on a video, newest first, anonymous tips do not show who sent them
+// @Tags			Tips
+// @Accept			json
+// @Produce		json
+// @Param			video_id	query	int	true	"Video ID"
+// @Param			page		query	int	false	"Page number (default: 1)"
+// @Param			limit		query	int	false	"Limit per page (default: 20)"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=[]models.TipDetails}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/tips/video [get]
+func (t *TipHandler) ListVideoTips(c *gin.Context) {
+	videoID, err := strconv.ParseUint(c.Query("video_id"), 10, 64)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Video ID not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	page, limit := parsePaginationParams(c)
+
+	tips, err := t.TipUseCase.ListVideoTips(uint(videoID), page, limit)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the tips", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Tips retrieved successfully", tips, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Get Tip Limits
+// @Description	Admin gets the smallest and largest tip allowed, in the platform currency
+// @Tags			Admin
+// @Accept			json
+// @Produce		json
+// @Security		Bearer
+// @Success		200	{object}	response.Response{data=models.TipLimits}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/tips/limits [get]
+func (t *TipHandler) GetTipLimits(c *gin.Context) {
+	limits, err := t.TipUseCase.GetTipLimits()
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the tip limits", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Tip limits retrieved successfully", limits, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// @Summary		Set Tip Limits
+// @Description	Admin sets the smallest and largest tip allowed, in the platform currency
+// @Tags			Admin
+// @Accept			json
+// @Produce		json
+// @Param			limits	body	models.TipLimits	true	"tip limits"
+// @Security		Bearer
+// @Success		200	{object}	response.Response{}
+// @Failure		400	{object}	response.Response{}
+// @Router			/admin/tips/limits [put]
+func (t *TipHandler) SetTipLimits(c *gin.Context) {
+	var limits models.TipLimits
+	if err := c.BindJSON(&limits); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	if err := t.TipUseCase.SetTipLimits(limits); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not set the tip limits", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Successfully set the tip limits", nil, nil)
+	c.JSON(http.StatusOK, successRes)
+}
@@ -199,71 +199,87 @@ func (u *UserHandler) GetProfile(c *gin.Context) {
 	c.JSON(http.StatusOK, userProfile)
 }
 
-// Logout is a handler for user logout
-// @Summary		User Logout
-// @Description	Logout the currently authenticated user
-// @Tags			User
+// @Summary		Get Billing Country
+// @Description	Get the country the user is billed in, it picks the regional price and tax at checkout
+// @Tags	 		User
 // @Accept		json
 // @Produce		json
 // @Security		Bearer
-// @Success		200	{object}	response.Response{}
-// @Failure		500	{object}	response.Response{}
-// @Router			/users/logout [post]
-func (i *UserHandler) Logout(c *gin.Context) {
+// @Success		200	{object}	response.Response{data=models.BillingCountry}
+// @Failure		400	{object}	response.Response{}
+// @Router			/users/billing/country [get]
+func (u *UserHandler) GetBillingCountry(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
 
-	// Clear the access token and refresh token cookies
-	c.SetCookie("Authorization", "", -1, "/", "", false, true)
-	c.SetCookie("Refreshtoken", "", -1, "/", "", false, true)
+	country, err := u.userUseCase.GetBillingCountry(userID)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get the billing country", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
 
-	successRes := response.ClientResponse(http.StatusOK, "User successfully logged out", nil, nil)
+	successRes := response.ClientResponse(http.StatusOK, "Billing country retrieved successfully", country, nil)
 	c.JSON(http.StatusOK, successRes)
 }
 
-// @Summary		Report User
-// @Description	Submit a report for a user
-// @Tags			User
+// @Summary		Set Billing Country
+// @Description	Set the country the user is billed in, as a two letter code such as IN or US
+// @Tags	 		User
 // @Accept		json
 // @Produce		json
-// @Param			targetUserID	query	int	true	"ID of the user being reported"
-// @Param			reason		query	string	true	"Reason for the report"
+// @Param			country	body	models.BillingCountry	true	"billing country"
 // @Security		Bearer
-// @Success		200	{object}	response.Response{}
+// @Success		200	{object}	response.Response{data=models.BillingCountry}
 // @Failure		400	{object}	response.Response{}
-// @Router			/users/reportUser [post]
-func (u *UserHandler) ReportUser(c *gin.Context) {
-	// Get the reporter's user ID from the token
-	reporterUserID, err := helper.GetUserID(c)
+// @Router			/users/billing/country [put]
+func (u *UserHandler) SetBillingCountry(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
 	if err != nil {
-		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get reporter's userID", nil, err.Error())
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
 		c.JSON(http.StatusBadRequest, errorRes)
 		return
 	}
 
-	// Get the target user's ID from the request
-	targetUserID, err := strconv.Atoi(c.Query("targetUserID"))
-	if err != nil {
-		errorRes := response.ClientResponse(http.StatusBadRequest, "Invalid targetUserID", nil, err.Error())
+	var input models.BillingCountry
+	if err := c.BindJSON(&input); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
 		c.JSON(http.StatusBadRequest, errorRes)
 		return
 	}
 
-	// Get the reason for the report from the request
-	reason := c.Query("reason")
-	if reason == "" {
-		errorRes := response.ClientResponse(http.StatusBadRequest, "Reason cannot be empty", nil, nil)
+	country, err := u.userUseCase.SetBillingCountry(userID, input.Country)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not set the billing country", nil, err.Error())
 		c.JSON(http.StatusBadRequest, errorRes)
 		return
 	}
 
-	// Call the user use case to handle the reporting logic
-	if err := u.userUseCase.ReportUser(reporterUserID, targetUserID, reason); err != nil {
-		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not submit the report", nil, err.Error())
-		c.JSON(http.StatusBadRequest, errorRes)
-		return
-	}
+	successRes := response.ClientResponse(http.StatusOK, "Successfully set the billing country", country, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// Logout is a handler for user logout
+// @Summary		User Logout
+// @Description	Logout the currently authenticated user
+// @Tags			User
+// @Accept		json
+// @Produce		json
+// @Security		Bearer
+// @Success		200	{object}	response.Response{}
+// @Failure		500	{object}	response.Response{}
+// @Router			/users/logout [post]
+func (i *UserHandler) Logout(c *gin.Context) {
+
+	// Clear the access token and refresh token cookies
+	c.SetCookie("Authorization", "", -1, "/", "", false, true)
+	c.SetCookie("Refreshtoken", "", -1, "/", "", false, true)
 
-	// Report submitted successfully
-	successRes := response.ClientResponse(http.StatusOK, "Successfully submitted the report", nil, nil)
+	successRes := response.ClientResponse(http.StatusOK, "User successfully logged out", nil, nil)
 	c.JSON(http.StatusOK, successRes)
 }
 
@@ -368,6 +384,13 @@ func (u *UserHandler) GetFollowingList(c *gin.Context) {
 // @Failure	400	{object}	response.Response{}
 // @Router	/users/search [get]
 func (u *UserHandler) SearchUsers(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get user ID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
 	// Get the search term, page, and limit from the query parameters
 	searchTerm := c.Query("searchTerm")
 	if searchTerm == "" {
@@ -393,7 +416,7 @@ func (u *UserHandler) SearchUsers(c *gin.Context) {
 	}
 
 	// Call the user use case to search for users by name with pagination
-	searchResults, err := u.userUseCase.SearchUsersByNameWithPagination(searchTerm, page, limit)
+	searchResults, err := u.userUseCase.SearchUsersByNameWithPagination(userID, searchTerm, page, limit)
 	if err != nil {
 		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not search for users", nil, err.Error())
 		c.JSON(http.StatusBadRequest, errorRes)
@@ -136,7 +136,7 @@ func (u *VideoHandler) ListVideos(c *gin.Context) {
 
 // EditVideoDetails is a handler for patching video details such as title and description.
 // @Summary      Edit Video Details
-// @Description  Patch the title and description of a video
+// @Description  Patch the title and description of a video, and its tags when they are given. Tags resolve to their canonical tag and banned tags are left out and listed
 // @Tags         User
 // @Accept       json
 // @Produce      json
@@ -164,13 +164,14 @@ func (u *VideoHandler) EditVideoDetails(c *gin.Context) {
 	}
 
 	// Call the use case to edit video details
-	if err := u.VideoUseCase.EditVideoDetails(videoID, editVideoDetails.Title, editVideoDetails.Description); err != nil {
+	banned, err := u.VideoUseCase.EditVideoDetails(videoID, editVideoDetails.Title, editVideoDetails.Description, editVideoDetails.Tags)
+	if err != nil {
 		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not edit video details", nil, err.Error())
 		c.JSON(http.StatusBadRequest, errorRes)
 		return
 	}
 
-	successRes := response.ClientResponse(http.StatusOK, "Successfully edited video details", nil, nil)
+	successRes := response.ClientResponse(http.StatusOK, "Successfully edited video details", gin.H{"banned_tags": banned}, nil)
 	c.JSON(http.StatusOK, successRes)
 }
 
@@ -253,6 +254,77 @@ func (u *VideoHandler) DeleteVideo(c *gin.Context) {
 // 	c.JSON(http.StatusOK, successRes)
 // }
 
+// WatchVideo is a handler for watching a video.
+// @Summary      Watch Video
+// @Description  Get the link of a video, exclusive videos need a subscription to a tier that unlocks them
+// @Tags         User
+// @Security     Bearer
+// @Param        videoID  query   int     true    "Video ID"
+// @Success      200  {object} response.Response{}
+// @Failure      400  {object} response.Response{}
+// @Router       /users/profile/videos/watch [get]
+func (u *VideoHandler) WatchVideo(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	videoID, err := strconv.Atoi(c.Query("videoID"))
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "videoID parameter not in the right format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	videoURL, err := u.VideoUseCase.WatchVideo(userID, videoID)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not watch video", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "follow the link to watch the video", videoURL, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
+// UpdateWatchProgress is a handler for reporting how far into a video the user watched.
+// @Summary      Update Watch Progress
+// @Description  Report the furthest point reached in the video the user opened in the last day, it feeds the creator's watch time and retention
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        progress  body    models.WatchProgress  true  "watch progress"
+// @Success      200  {object} response.Response{}
+// @Failure      400  {object} response.Response{}
+// @Router       /users/profile/videos/progress [post]
+func (u *VideoHandler) UpdateWatchProgress(c *gin.Context) {
+	userID, err := helper.GetUserID(c)
+	if err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not get userID", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	var progress models.WatchProgress
+	if err := c.BindJSON(&progress); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "fields provided are in wrong format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	if err := u.VideoUseCase.UpdateWatchProgress(userID, progress); err != nil {
+		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not update the watch progress", nil, err.Error())
+		c.JSON(http.StatusBadRequest, errorRes)
+		return
+	}
+
+	successRes := response.ClientResponse(http.StatusOK, "Watch progress updated", nil, nil)
+	c.JSON(http.StatusOK, successRes)
+}
+
 // ToggleLikeVideo is a handler for toggling the like status of a video.
 // @Summary      Toggle Like Video
 // @Description  Toggle the like status of a video for the authenticated user
@@ -394,7 +466,7 @@ func (u *VideoHandler) GetCommentsHandler(c *gin.Context) {
 
 // AddTagsHandler is a handler for adding tags to the database.
 // @Summary      Add Tags
-// @Description  Add tags to the database
+// @Description  Add tags to the database, banned tags are skipped and listed
 // @Tags         Admin Content Management
 // @Security     Bearer
 // @Param        tags   query   string  true    "Comma-separated list of tags"
@@ -414,14 +486,15 @@ func (u *VideoHandler) AddTagsHandler(c *gin.Context) {
 	tags := strings.Split(tagsParam, ",")
 
 	// Call the use case to add tags to the database
-	if err := u.VideoUseCase.AddVideoTags(tags); err != nil {
+	banned, err := u.VideoUseCase.AddVideoTags(tags)
+	if err != nil {
 		errorRes := response.ClientResponse(http.StatusBadRequest, "Could not add tags", nil, err.Error())
 		c.JSON(http.StatusBadRequest, errorRes)
 		return
 	}
 
 	// Customize the response based on your needs
-	successRes := response.ClientResponse(http.StatusOK, "Tags added successfully", nil, nil)
+	successRes := response.ClientResponse(http.StatusOK, "Tags added successfully", gin.H{"banned_tags": banned}, nil)
 	c.JSON(http.StatusOK, successRes)
 }
 
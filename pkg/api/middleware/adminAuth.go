@@ -1,8 +1,10 @@
 package middleware
 
 import (
+	"context"
 	"fmt"
 	"main/pkg/helper"
+	"main/pkg/utils/models"
 	"net/http"
 	"time"
 
@@ -74,5 +76,17 @@ func AdminAuthMiddleware(c *gin.Context) {
 		c.SetCookie("Refreshtoken", newRefreshToken, 0, "/", "", false, true)
 	}
 
+	// Get admin ID from claims, it is kept on the moderation work the admin does
+	adminID, ok := claims["id"].(float64)
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Unauthorized access id"})
+		c.Abort()
+		return
+	}
+	var key models.UserKey = "adminID"
+	var val models.UserKey = models.UserKey(fmt.Sprintf("%v", adminID))
+
+	ctx := context.WithValue(c, key, val)
+	c.Request = c.Request.WithContext(ctx)
 	c.Next()
 }
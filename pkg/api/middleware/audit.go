@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"main/pkg/helper"
+	services "main/pkg/usecase/interface"
+	"main/pkg/utils/models"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// auditRoute names an audited admin action and where the id of its target is, in the query or in the JSON body,
+// if it has one
+type auditRoute struct {
+	action     string
+	targetType string
+	param      string
+	field      string
+}
+
+// maxAuditBody is the longest request body kept in the audit log
+const maxAuditBody = 64 << 10
+
+// auditRoutes are the admin routes that change something, keyed by method and route.
+// Changes on routes missing here are still recorded under their method and route.
+var auditRoutes = map[string]auditRoute{
+	"POST /admin/admins":                      {"admin.create", "", "", ""},
+	"PATCH /admin/admins/role":                {"admin.role", "admin", "id", ""},
+	"PATCH /admin/admins/disable":             {"admin.disable", "admin", "id", ""},
+	"PATCH /admin/admins/enable":              {"admin.enable", "admin", "id", ""},
+	"POST /admin/users/toggle-block":          {"user.toggle_block", "user", "id", ""},
+	"POST /admin/addtags":                     {"tag.add", "", "", ""},
+	"DELETE /admin/deletetags":                {"tag.delete", "tag", "tagID", ""},
+	"POST /admin/category/add":                {"category.add", "", "", ""},
+	"PATCH /admin/category/update":            {"category.update", "category", "", "id"},
+	"DELETE /admin/category/delete":           {"category.delete", "category", "id", ""},
+	"PATCH /admin/category/reorder":           {"category.reorder", "", "", ""},
+	"PATCH /admin/category/icon":              {"category.icon", "category", "id", ""},
+	"POST /admin/plans/add":                   {"plan.add", "", "", ""},
+	"PUT /admin/plans/limits":                 {"plan.limits", "settings", "", ""},
+	"DELETE /admin/plans/delete":              {"plan.delete", "plan", "id", ""},
+	"POST /admin/tags/aliases":                {"tag.alias_add", "tag", "tag_id", ""},
+	"DELETE /admin/tags/aliases":              {"tag.alias_delete", "tag_alias", "id", ""},
+	"POST /admin/tags/merge":                  {"tag.merge", "tag", "source_id", ""},
+	"POST /admin/tags/banned":                 {"tag.ban", "", "", ""},
+	"DELETE /admin/tags/banned":               {"tag.unban", "banned_tag", "id", ""},
+	"POST /admin/coupons":                     {"coupon.create", "", "", ""},
+	"DELETE /admin/coupons":                   {"coupon.deactivate", "coupon", "id", ""},
+	"POST /admin/payments/events/replay":      {"payment.replay", "payment_event", "id", ""},
+	"POST /admin/payments/refunds":            {"payment.refund", "subscription", "", "subscription_id"},
+	"PATCH /admin/payments/disputes/review":   {"payment.dispute_review", "payment_dispute", "", "dispute_id"},
+	"PUT /admin/tips/limits":                  {"tip.limits", "settings", "", ""},
+	"PUT /admin/ledger/fee":                   {"ledger.fee", "settings", "", ""},
+	"PUT /admin/payouts/settings":             {"payout.settings", "settings", "", ""},
+	"POST /admin/payouts/batches":             {"payout.batch_create", "", "", ""},
+	"PATCH /admin/payouts/batches/approve":    {"payout.batch_approve", "payout_batch", "id", ""},
+	"PATCH /admin/payouts/paid":               {"payout.paid", "payout", "", "payout_id"},
+	"PUT /admin/taxes":                        {"tax.set", "tax_rule", "", "region"},
+	"DELETE /admin/taxes":                     {"tax.delete", "tax_rule", "region", ""},
+	"PATCH /admin/moderation/cases/assign":    {"moderation.assign", "moderation_case", "case_id", ""},
+	"PATCH /admin/moderation/cases/unassign":  {"moderation.unassign", "moderation_case", "case_id", ""},
+	"POST /admin/moderation/cases/notes":      {"moderation.note", "moderation_case", "case_id", ""},
+	"POST /admin/moderation/cases/resolve":    {"moderation.resolve", "moderation_case", "case_id", ""},
+	"POST /admin/enforcement/actions":         {"enforcement.issue", "", "", ""},
+	"PATCH /admin/enforcement/actions/revoke": {"enforcement.revoke", "enforcement_action", "action_id", ""},
+	"POST /admin/enforcement/appeals/decide":  {"enforcement.appeal_decide", "appeal", "appeal_id", ""},
+}
+
+// AuditMiddleware records every admin request that changes something with the target before and after it,
+// it runs after AdminAuthMiddleware so the admin is known
+func AuditMiddleware(audit services.AuditUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		adminID, err := helper.GetAdminID(c)
+		if err != nil {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+
+		route, ok := auditRoutes[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			route = auditRoute{action: c.Request.Method + " " + c.FullPath()}
+		}
+		targetID := ""
+		if route.param != "" {
+			targetID = c.Query(route.param)
+		}
+
+		// the body is read here and put back for the handler, routes with their target in the body are read
+		// whatever the content type says as their handlers bind JSON regardless. Only up to maxAuditBody is read,
+		// the handler still gets a longer body but it is not recorded
+		var body []byte
+		if c.Request.Body != nil && (c.ContentType() == gin.MIMEJSON || route.field != "") {
+			body, err = io.ReadAll(io.LimitReader(c.Request.Body, maxAuditBody+1))
+			if err != nil {
+				c.AbortWithStatus(http.StatusBadRequest)
+				return
+			}
+			c.Request.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(body), c.Request.Body), c.Request.Body}
+			if len(body) > maxAuditBody {
+				body = nil
+			}
+		}
+		if route.field != "" {
+			targetID = bodyField(body, route.field)
+		}
+
+		entry := models.AuditEntry{
+			AdminID:    adminID,
+			Action:     route.action,
+			Method:     c.Request.Method,
+			Path:       c.Request.URL.Path,
+			TargetType: route.targetType,
+			TargetID:   targetID,
+			Query:      c.Request.URL.RawQuery,
+			Body:       body,
+			Before:     audit.Snapshot(route.targetType, targetID),
+			IP:         c.ClientIP(),
+		}
+
+		c.Next()
+
+		entry.Status = c.Writer.Status()
+		audit.Record(entry)
+	}
+}
+
+// bodyField returns a top level field of the JSON body as text, empty when there is none
+func bodyField(body []byte, field string) string {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return ""
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(fields[field]))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil || value == nil {
+		return ""
+	}
+
+	return strings.TrimSpace(fmt.Sprint(value))
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"context"
+	"main/pkg/domain"
+	"main/pkg/helper"
+	services "main/pkg/usecase/interface"
+	"main/pkg/utils/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAccountMiddleware turns away disabled admins and keeps the admin's role in the context for RequirePermission,
+// it runs after AdminAuthMiddleware and reads the account on every request so changes apply to tokens already issued
+func AdminAccountMiddleware(admins services.AdminUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		adminID, err := helper.GetAdminID(c)
+		if err != nil {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+
+		role, err := admins.GetAdminRole(adminID)
+		if err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
+
+		var key models.UserKey = "adminRole"
+		ctx := context.WithValue(c.Request.Context(), key, models.UserKey(role))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// RequirePermission lets through admins whose role has the permission
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var key models.UserKey = "adminRole"
+		role, _ := c.Request.Context().Value(key).(models.UserKey)
+		if !domain.AdminRolePermissions[role.String()][permission] {
+			c.JSON(http.StatusForbidden, gin.H{"error": "your role does not allow this"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"main/pkg/helper"
+	services "main/pkg/usecase/interface"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SuspensionMiddleware turns away suspended users, it runs after UserAuthMiddleware so a suspension
+// takes effect on tokens issued before it
+func SuspensionMiddleware(enforcement services.EnforcementUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := helper.GetUserID(c)
+		if err != nil {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+
+		suspension, suspended, err := enforcement.GetActiveSuspension(userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not check the account status"})
+			c.Abort()
+			return
+		}
+		if suspended {
+			c.JSON(http.StatusForbidden, gin.H{"error": helper.SuspensionMessage(*suspension.ExpiresAt, suspension.Reason)})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
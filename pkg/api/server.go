@@ -7,11 +7,13 @@ import (
 
 	handler "main/pkg/api/handler"
 	"main/pkg/routes"
+	"main/pkg/scheduler"
 )
 
 // ServerHTTP represents an HTTP server for the web application.
 type ServerHTTP struct {
-	engine *gin.Engine // engine is the core of the Gin web framework, responsible for routing HTTP requests and handling middleware.
+	engine    *gin.Engine          // engine is the core of the Gin web framework, responsible for routing HTTP requests and handling middleware.
+	scheduler *scheduler.Scheduler // scheduler runs the background jobs while the server is up.
 }
 
 /*
@@ -24,22 +26,28 @@ Parameters:
 Returns:
 - *ServerHTTP: A pointer to the newly created ServerHTTP instance.
 */
-func NewServerHTTP(userHandler *handler.UserHandler, otpHandler *handler.OtpHandler, adminHandler *handler.AdminHandler, categoryHandler *handler.CategoryHandler, videoHandler *handler.VideoHandler, subscriptionHandler *handler.SubscriptionHandler) *ServerHTTP {
+func NewServerHTTP(userHandler *handler.UserHandler, otpHandler *handler.OtpHandler, adminHandler *handler.AdminHandler, categoryHandler *handler.CategoryHandler, videoHandler *handler.VideoHandler, subscriptionHandler *handler.SubscriptionHandler, searchHandler *handler.SearchHandler, tagHandler *handler.TagHandler, notificationHandler *handler.NotificationHandler, tierHandler *handler.TierHandler, couponHandler *handler.CouponHandler, ledgerHandler *handler.LedgerHandler, payoutHandler *handler.PayoutHandler, invoiceHandler *handler.InvoiceHandler, taxHandler *handler.TaxHandler, giftHandler *handler.GiftHandler, tipHandler *handler.TipHandler, analyticsHandler *handler.AnalyticsHandler, moderationHandler *handler.ModerationHandler, enforcementHandler *handler.EnforcementHandler, auditHandler *handler.AuditHandler, jobs *scheduler.Scheduler) *ServerHTTP {
 	engine := gin.New()
 	engine.Use(gin.Logger())
 	engine.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
 	engine.LoadHTMLGlob("pkg/templates/*.html")
 
-	routes.UserRoutes(engine.Group("/users"), userHandler, otpHandler, categoryHandler, videoHandler, subscriptionHandler)
-	routes.AdminRoutes(engine.Group("/admin"), adminHandler, categoryHandler, videoHandler)
+	routes.UserRoutes(engine.Group("/users"), userHandler, otpHandler, categoryHandler, videoHandler, subscriptionHandler, searchHandler, notificationHandler, tierHandler, couponHandler, ledgerHandler, payoutHandler, invoiceHandler, giftHandler, tipHandler, analyticsHandler, moderationHandler, enforcementHandler)
+	routes.AdminRoutes(engine.Group("/admin"), adminHandler, categoryHandler, videoHandler, searchHandler, tagHandler, subscriptionHandler, tierHandler, couponHandler, ledgerHandler, payoutHandler, taxHandler, tipHandler, analyticsHandler, moderationHandler, enforcementHandler, auditHandler)
+	routes.PaymentRoutes(engine.Group("/payments"), subscriptionHandler)
 
 	return &ServerHTTP{
 
-		engine: engine,
+		engine:    engine,
+		scheduler: jobs,
 	}
 }
 
 func (sh *ServerHTTP) Start() {
+	// The jobs are started first, Run blocks until the server stops
+	sh.scheduler.Start()
+	defer sh.scheduler.Stop()
+
 	sh.engine.Run(":1245")
 
 }
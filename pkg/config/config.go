@@ -9,20 +9,26 @@ import (
 )
 
 type Config struct {
-	DBHost             string `mapstructure:"DB_HOST"`
-	DBName             string `mapstructure:"DB_NAME"`
-	DBUser             string `mapstructure:"DB_USER"`
-	DBPort             string `mapstructure:"DB_PORT"`
-	DBPassword         string `mapstructure:"DB_PASSWORD"`
-	ACCOUNTSID         string `mapstructure:"ACCOUNTSID"`
-	SERVICESID         string `mapstructure:"SERVICESID"`
-	AUTHTOKEN          string `mapstructure:"AUTHTOKEN"`
-	AWSACCESSKEYID     string `mapstructure:"AWSACCESSKEYID"`
-	AWSSECRETACCESSKEY string `mapstructure:"AWSSECRETACCESSKEY "`
+	DBHost                string `mapstructure:"DB_HOST"`
+	DBName                string `mapstructure:"DB_NAME"`
+	DBUser                string `mapstructure:"DB_USER"`
+	DBPort                string `mapstructure:"DB_PORT"`
+	DBPassword            string `mapstructure:"DB_PASSWORD"`
+	ACCOUNTSID            string `mapstructure:"ACCOUNTSID"`
+	SERVICESID            string `mapstructure:"SERVICESID"`
+	AUTHTOKEN             string `mapstructure:"AUTHTOKEN"`
+	AWSACCESSKEYID        string `mapstructure:"AWSACCESSKEYID"`
+	AWSSECRETACCESSKEY    string `mapstructure:"AWSSECRETACCESSKEY "`
+	RazorpayKeyID         string `mapstructure:"RAZORPAY_KEY_ID"`
+	RazorpayKeySecret     string `mapstructure:"RAZORPAY_KEY_SECRET"`
+	RazorpayWebhookSecret string `mapstructure:"RAZORPAY_WEBHOOK_SECRET"`
+	PaymentGateway        string `mapstructure:"PAYMENT_GATEWAY"`  // razorpay or fake, defaults to razorpay
+	PaymentCurrency       string `mapstructure:"PAYMENT_CURRENCY"` // ISO currency code, defaults to INR
 }
 
 var envs = []string{
 	"DB_HOST", "DB_NAME", "DB_USER", "DB_PORT", "DB_PASSWORD", "ACCOUNTS_ID", "SERVICES_ID", "AUTH_TOKEN", "AWSACCESSKEY_ID", "AWSSECRETACCESS_KEY",
+	"RAZORPAY_KEY_ID", "RAZORPAY_KEY_SECRET", "RAZORPAY_WEBHOOK_SECRET", "PAYMENT_GATEWAY", "PAYMENT_CURRENCY",
 }
 
 func LoadConfig() (Config, error) {
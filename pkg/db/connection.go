@@ -2,12 +2,15 @@ package db
 
 import (
 	"fmt"
+	"math"
+	"strings"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 
 	config "main/pkg/config"
 	domain "main/pkg/domain"
+	"main/pkg/money"
 )
 
 func ConnectDatabase(cfg config.Config) (*gorm.DB, error) {
@@ -15,19 +18,167 @@ func ConnectDatabase(cfg config.Config) (*gorm.DB, error) {
 	db, dbErr := gorm.Open(postgres.Open(psqlInfo), &gorm.Config{
 		SkipDefaultTransaction: true,
 	})
+	if dbErr != nil {
+		return nil, dbErr
+	}
+
+	// pg_trgm powers the typo tolerant search suggestions
+	db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm")
 
 	db.AutoMigrate(&domain.User{})
 	db.AutoMigrate(&domain.Admin{})
 	db.AutoMigrate(&domain.Reports{})
+	db.AutoMigrate(&domain.ModerationCase{})
+	db.AutoMigrate(&domain.CaseNote{})
+	db.AutoMigrate(&domain.EnforcementAction{})
+	db.AutoMigrate(&domain.Appeal{})
+	db.AutoMigrate(&domain.AuditLog{})
+	// the audit log is append only, rows can be neither changed nor removed
+	db.Exec(`CREATE OR REPLACE FUNCTION audit_logs_append_only() RETURNS trigger AS $$
+		BEGIN
+			RAISE EXCEPTION 'audit_logs is append only';
+		END;
+		$$ LANGUAGE plpgsql`)
+	db.Exec("DROP TRIGGER IF EXISTS audit_logs_append_only ON audit_logs")
+	db.Exec("CREATE TRIGGER audit_logs_append_only BEFORE UPDATE OR DELETE OR TRUNCATE ON audit_logs FOR EACH STATEMENT EXECUTE FUNCTION audit_logs_append_only()")
+	// One case is handled at a time per target and category, reports from before cases existed are grouped per reported user
+	db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_moderation_cases_open ON moderation_cases (target_type, target_id, category) WHERE status IN ('open', 'in_review')")
+	db.Exec("UPDATE reports SET target_user_id = target_id WHERE target_type = 'user' AND (target_user_id IS NULL OR target_user_id = 0)")
+	db.Exec(`INSERT INTO moderation_cases (target_type, target_id, target_user_id, category, status, report_count, created_at, updated_at)
+		SELECT target_type, target_id, MIN(target_user_id), category, 'open', COUNT(*), COALESCE(MIN(created_at), now()), now()
+		FROM reports WHERE case_id IS NULL
+		GROUP BY target_type, target_id, category
+		ON CONFLICT (target_type, target_id, category) WHERE status IN ('open', 'in_review')
+		DO UPDATE SET report_count = moderation_cases.report_count + EXCLUDED.report_count`)
+	db.Exec(`UPDATE reports r SET case_id = c.id FROM moderation_cases c
+		WHERE r.case_id IS NULL AND c.status IN ('open', 'in_review') AND c.target_type = r.target_type AND c.target_id = r.target_id AND c.category = r.category`)
 	db.AutoMigrate(&domain.Category{})
+	// Videos used to be deleted together with their category, the key is recreated as RESTRICT below
+	db.Exec(`DO $$ BEGIN
+		IF EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_videos_category' AND confdeltype = 'c') THEN
+			ALTER TABLE videos DROP CONSTRAINT fk_videos_category;
+		END IF;
+	END $$`)
 	db.AutoMigrate(&domain.Video{})
 	db.AutoMigrate(&domain.VideoLikes{})
 	db.AutoMigrate(&domain.Tag{})
 	db.AutoMigrate(&domain.UserTags{})
 	db.AutoMigrate(&domain.Comment{})
 	db.AutoMigrate(&domain.VideoTags{})
+	db.AutoMigrate(&domain.TagAlias{})
+	db.AutoMigrate(&domain.BannedTag{})
 	db.AutoMigrate(&domain.SubscriptionPlan{})
+	// Coupon discounts were stored as a decimal in the major unit, they move to the currency's minor unit like every other amount
+	db.Exec(fmt.Sprintf(`DO $$ BEGIN
+		IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'subscription_lists' AND column_name = 'discount' AND data_type IN ('numeric', 'double precision')) THEN
+			ALTER TABLE subscription_lists ALTER COLUMN discount TYPE bigint USING ROUND(discount * %s);
+		END IF;
+		IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'coupon_redemptions' AND column_name = 'discount' AND data_type IN ('numeric', 'double precision')) THEN
+			ALTER TABLE coupon_redemptions ALTER COLUMN discount TYPE bigint USING 0;
+			UPDATE coupon_redemptions r SET discount = s.discount FROM subscription_lists s WHERE s.id = r.subscription_list_id;
+		END IF;
+	END $$`, minorUnitFactor("currency")))
 	db.AutoMigrate(&domain.SubscriptionList{})
+	db.AutoMigrate(&domain.PlanPrice{})
+	db.AutoMigrate(&domain.TaxRule{})
+	db.AutoMigrate(&domain.TierVideo{})
+	db.AutoMigrate(&domain.GiftPurchase{})
+	db.AutoMigrate(&domain.GiftCode{})
+	db.AutoMigrate(&domain.Tip{})
+	db.AutoMigrate(&domain.VideoView{})
+	db.AutoMigrate(&domain.CreatorDailyStat{})
+	db.AutoMigrate(&domain.CreatorDailyRevenue{})
+	db.AutoMigrate(&domain.VideoDailyStat{})
+	db.AutoMigrate(&domain.DailyActiveUser{})
+	db.AutoMigrate(&domain.PlatformDailyStat{})
+	db.AutoMigrate(&domain.PlatformDailyRevenue{})
+	db.AutoMigrate(&domain.AnalyticsReportSetting{})
+	db.AutoMigrate(&domain.AnalyticsReport{})
+	db.AutoMigrate(&domain.Coupon{})
+	db.AutoMigrate(&domain.CouponRedemption{})
+	db.AutoMigrate(&domain.LedgerTransaction{})
+	db.AutoMigrate(&domain.LedgerEntry{})
+	db.AutoMigrate(&domain.PayoutAccount{})
+	// Batches were made once per month before earnings were kept per currency, now once per month and currency
+	db.Exec("DROP INDEX IF EXISTS idx_payout_batches_period")
+	db.AutoMigrate(&domain.PayoutBatch{})
+	db.AutoMigrate(&domain.Payout{})
+	db.AutoMigrate(&domain.Setting{})
+	db.AutoMigrate(&domain.PaymentEvent{})
+	db.AutoMigrate(&domain.PaymentRefund{})
+	db.AutoMigrate(&domain.PaymentDispute{})
+	db.AutoMigrate(&domain.Invoice{})
+	db.AutoMigrate(&domain.InvoiceSequence{})
+	db.AutoMigrate(&domain.Notification{})
 	db.AutoMigrate(&domain.Follow{})
+	db.AutoMigrate(&domain.SearchQuery{})
+
+	// Categories created before slugs existed get one from their name, duplicates are told apart by ID
+	db.Exec(`UPDATE categories SET slug = trim(both '-' from lower(regexp_replace(category, '[^a-zA-Z0-9]+', '-', 'g'))) WHERE slug IS NULL OR slug = ''`)
+	db.Exec(`UPDATE categories c SET slug = c.slug || '-' || c.id WHERE EXISTS (SELECT 1 FROM categories d WHERE d.slug = c.slug AND d.id < c.id)`)
+	db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_slug ON categories (slug)")
+
+	// Plan prices were stored as a decimal in the payment currency, they move to minor units with the currency kept alongside
+	currency := strings.ToUpper(cfg.PaymentCurrency)
+	if !money.Supported(currency) {
+		currency = "INR"
+	}
+	db.Exec(fmt.Sprintf(`DO $$ BEGIN
+		IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'subscription_plans' AND column_name = 'price') THEN
+			UPDATE subscription_plans SET price_amount = ROUND(price * %d), price_currency = '%s' WHERE price_currency IS NULL OR price_currency = '';
+			ALTER TABLE subscription_plans DROP COLUMN price;
+		END IF;
+	END $$`, int64(math.Pow10(money.Exponent(currency))), currency))
+
+	// Video tags stored before canonical tags existed are linked to the tag with the same name
+	db.Exec("UPDATE video_tags vt SET tag_id = t.id FROM tags t WHERE vt.tag_id IS NULL AND LOWER(vt.tag) = LOWER(t.tag)")
+
+	// Subscriptions paid before statuses existed start a fresh period, the old rows have no reliable start date
+	db.Exec(`UPDATE subscription_lists s SET status = 'active', subscribed_at = now(), current_period_start = now(),
+		current_period_end = now() + p.duration * interval '1 day'
+		FROM subscription_plans p
+		WHERE p.id = s.plan_id AND s.is_active = true AND s.status = 'pending' AND s.current_period_end IS NULL`)
+	db.Exec("UPDATE subscription_lists SET status = 'expired' WHERE is_active = false AND payment_status = 'PAID' AND status = 'pending'")
+
+	// A user keeps at most one open checkout per creator, older duplicates are expired before the index is created
+	db.Exec("UPDATE subscription_lists SET created_at = COALESCE(subscribed_at, now()) WHERE created_at IS NULL")
+	db.Exec(`UPDATE subscription_lists s SET status = 'expired' WHERE s.status = 'pending'
+		AND EXISTS (SELECT 1 FROM subscription_lists o WHERE o.user_id = s.user_id AND o.creator_id = s.creator_id AND o.status = 'pending' AND o.id > s.id)`)
+	db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_subscription_lists_pending ON subscription_lists (user_id, creator_id) WHERE status = 'pending'")
+
+	// Payments refunded before partial refunds were tracked were refunded in full
+	db.Exec("UPDATE subscription_lists SET amount_refunded = amount WHERE payment_status = 'REFUNDED' AND amount_refunded = 0")
+
+	// Gateway payments made before the ledger existed are charged at the platform fee admins set, the default when they have not
+	db.Exec(`INSERT INTO ledger_transactions (type, reference, subscription_list_id, creator_id, currency, description, created_at)
+		SELECT 'charge', s.payment_id, s.id, s.creator_id, s.currency, 'subscription ' || s.id || ' paid by user ' || s.user_id, COALESCE(s.subscribed_at, now())
+		FROM subscription_lists s
+		WHERE s.payment_status IN ('PAID', 'REFUNDED') AND s.amount > 0 AND s.payment_id <> '' AND s.payment_id NOT LIKE 'free_%'
+		ON CONFLICT (type, reference) DO NOTHING`)
+	db.Exec(`INSERT INTO ledger_entries (transaction_id, account, creator_id, debit, credit, created_at)
+		SELECT t.id, e.account, e.creator_id, e.debit, e.credit, t.created_at
+		FROM ledger_transactions t
+		JOIN subscription_lists s ON s.id = t.subscription_list_id
+		CROSS JOIN (SELECT COALESCE((SELECT value::numeric FROM settings WHERE key = ?), ?) AS percent) f
+		CROSS JOIN LATERAL (VALUES
+			('gateway_clearing', 0, s.amount, 0::bigint),
+			('platform_revenue', 0, 0::bigint, ROUND(s.amount * f.percent / 100)::bigint),
+			('creator_payable', s.creator_id, 0::bigint, s.amount - ROUND(s.amount * f.percent / 100)::bigint)
+		) AS e(account, creator_id, debit, credit)
+		WHERE t.type = 'charge' AND NOT EXISTS (SELECT 1 FROM ledger_entries x WHERE x.transaction_id = t.id)`,
+		domain.PlatformFeeKey, domain.DefaultPlatformFeePercent)
+
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_videos_title_trgm ON videos USING gin (title gin_trgm_ops)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_users_username_trgm ON users USING gin (username gin_trgm_ops)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_tags_tag_trgm ON tags USING gin (tag gin_trgm_ops)")
 	return db, dbErr
 }
+
+// minorUnitFactor is the SQL for what an amount in the major unit of the currency in the column is multiplied by to get its minor unit
+func minorUnitFactor(column string) string {
+	var cases strings.Builder
+	for _, currency := range money.Currencies() {
+		fmt.Fprintf(&cases, " WHEN '%s' THEN %d", currency, int64(math.Pow10(money.Exponent(currency))))
+	}
+	return fmt.Sprintf("CASE upper(%s)%s ELSE %d END", column, cases.String(), int64(math.Pow10(money.Exponent(""))))
+}
@@ -8,13 +8,15 @@ import (
 	handler "main/pkg/api/handler"
 	config "main/pkg/config"
 	db "main/pkg/db"
+	payments "main/pkg/payments"
 	repository "main/pkg/repository"
+	scheduler "main/pkg/scheduler"
 	usecase "main/pkg/usecase"
 
 	"github.com/google/wire"
 )
 
 func InitializeAPI(cfg config.Config) (*http.ServerHTTP, error) {
-	wire.Build(db.ConnectDatabase, http.NewServerHTTP, repository.NewUserRepository, usecase.NewUserUseCase, handler.NewUserHandler, repository.NewOtpRepository, usecase.NewOtpUseCase, handler.NewOtpHandler, repository.NewAdminRepository, usecase.NewAdminUseCase, handler.NewAdminHandler, repository.NewCategoryRepository, usecase.NewCategoryUseCase, handler.NewCategoryHandler, repository.NewVideoRepository, usecase.NewVideoUseCase, handler.NewVideoHandler, repository.NewsubscriptionRepository, usecase.NewSubscriptionUseCase, handler.NewSubscriptionHandler)
+	wire.Build(db.ConnectDatabase, http.NewServerHTTP, repository.NewUserRepository, usecase.NewUserUseCase, handler.NewUserHandler, repository.NewOtpRepository, usecase.NewOtpUseCase, handler.NewOtpHandler, repository.NewAdminRepository, usecase.NewAdminUseCase, handler.NewAdminHandler, repository.NewCategoryRepository, usecase.NewCategoryUseCase, handler.NewCategoryHandler, repository.NewVideoRepository, usecase.NewVideoUseCase, handler.NewVideoHandler, repository.NewsubscriptionRepository, payments.NewGateway, usecase.NewSubscriptionUseCase, handler.NewSubscriptionHandler, repository.NewSearchRepository, usecase.NewSearchUseCase, handler.NewSearchHandler, repository.NewTagRepository, usecase.NewTagUseCase, handler.NewTagHandler, repository.NewNotificationRepository, usecase.NewNotificationUseCase, handler.NewNotificationHandler, repository.NewTierRepository, repository.NewSettingRepository, usecase.NewTierUseCase, handler.NewTierHandler, repository.NewCouponRepository, usecase.NewCouponUseCase, handler.NewCouponHandler, repository.NewLedgerRepository, usecase.NewLedgerUseCase, handler.NewLedgerHandler, repository.NewPayoutRepository, usecase.NewPayoutUseCase, handler.NewPayoutHandler, repository.NewInvoiceRepository, usecase.NewInvoiceUseCase, handler.NewInvoiceHandler, repository.NewTaxRepository, usecase.NewTaxUseCase, handler.NewTaxHandler, repository.NewGiftRepository, usecase.NewGiftUseCase, handler.NewGiftHandler, repository.NewTipRepository, usecase.NewTipUseCase, handler.NewTipHandler, repository.NewAnalyticsRepository, usecase.NewAnalyticsUseCase, handler.NewAnalyticsHandler, repository.NewModerationRepository, usecase.NewModerationUseCase, handler.NewModerationHandler, repository.NewEnforcementRepository, usecase.NewEnforcementUseCase, handler.NewEnforcementHandler, repository.NewAuditRepository, usecase.NewAuditUseCase, handler.NewAuditHandler, scheduler.NewScheduler)
 	return &http.ServerHTTP{}, nil
 }
@@ -11,7 +11,9 @@ import (
 	"main/pkg/api/handler"
 	"main/pkg/config"
 	"main/pkg/db"
+	"main/pkg/payments"
 	"main/pkg/repository"
+	"main/pkg/scheduler"
 	"main/pkg/usecase"
 )
 
@@ -23,23 +25,74 @@ func InitializeAPI(cfg config.Config) (*http.ServerHTTP, error) {
 		return nil, err
 	}
 	userRepository := repository.NewUserRepository(gormDB)
-	userUseCase := usecase.NewUserUseCase(userRepository)
+	enforcementRepository := repository.NewEnforcementRepository(gormDB)
+	searchRepository := repository.NewSearchRepository(gormDB)
+	userUseCase := usecase.NewUserUseCase(userRepository, enforcementRepository, searchRepository)
 	userHandler := handler.NewUserHandler(userUseCase)
 	otpRepository := repository.NewOtpRepository(gormDB)
 	otpUseCase := usecase.NewOtpUseCase(cfg, otpRepository)
 	otpHandler := handler.NewOtpHandler(otpUseCase)
 	adminRepository := repository.NewAdminRepository(gormDB)
-	adminUseCase := usecase.NewAdminUseCase(adminRepository)
+	gateway, err := payments.NewGateway(cfg)
+	if err != nil {
+		return nil, err
+	}
+	adminUseCase := usecase.NewAdminUseCase(adminRepository, gateway)
 	adminHandler := handler.NewAdminHandler(adminUseCase)
 	categoryRepository := repository.NewCategoryRepository(gormDB)
-	categoryUseCase := usecase.NewCategoryUseCase(categoryRepository)
+	categoryUseCase := usecase.NewCategoryUseCase(cfg, categoryRepository)
 	categoryHandler := handler.NewCategoryHandler(categoryUseCase)
 	videoRepository := repository.NewVideoRepository(gormDB)
-	videoUseCase := usecase.NewVideoUseCase(videoRepository)
+	videoUseCase := usecase.NewVideoUseCase(videoRepository, searchRepository)
 	videoHandler := handler.NewVideoHandler(videoUseCase)
 	subscriptionRepository := repository.NewsubscriptionRepository(gormDB)
-	subscriptionUseCase := usecase.NewSubscriptionUseCase(subscriptionRepository)
+	notificationRepository := repository.NewNotificationRepository(gormDB)
+	couponRepository := repository.NewCouponRepository(gormDB)
+	ledgerRepository := repository.NewLedgerRepository(gormDB)
+	settingRepository := repository.NewSettingRepository(gormDB)
+	invoiceRepository := repository.NewInvoiceRepository(gormDB)
+	taxRepository := repository.NewTaxRepository(gormDB)
+	giftRepository := repository.NewGiftRepository(gormDB)
+	tipRepository := repository.NewTipRepository(gormDB)
+	subscriptionUseCase := usecase.NewSubscriptionUseCase(subscriptionRepository, notificationRepository, couponRepository, ledgerRepository, settingRepository, invoiceRepository, taxRepository, giftRepository, tipRepository, gateway)
 	subscriptionHandler := handler.NewSubscriptionHandler(subscriptionUseCase)
-	serverHTTP := http.NewServerHTTP(userHandler, otpHandler, adminHandler, categoryHandler, videoHandler, subscriptionHandler)
+	searchUseCase := usecase.NewSearchUseCase(searchRepository)
+	searchHandler := handler.NewSearchHandler(searchUseCase)
+	tagRepository := repository.NewTagRepository(gormDB)
+	tagUseCase := usecase.NewTagUseCase(tagRepository)
+	tagHandler := handler.NewTagHandler(tagUseCase)
+	notificationUseCase := usecase.NewNotificationUseCase(notificationRepository)
+	notificationHandler := handler.NewNotificationHandler(notificationUseCase)
+	tierRepository := repository.NewTierRepository(gormDB)
+	tierUseCase := usecase.NewTierUseCase(tierRepository, settingRepository, gateway)
+	tierHandler := handler.NewTierHandler(tierUseCase)
+	couponUseCase := usecase.NewCouponUseCase(couponRepository)
+	couponHandler := handler.NewCouponHandler(couponUseCase)
+	ledgerUseCase := usecase.NewLedgerUseCase(ledgerRepository, settingRepository, gateway)
+	ledgerHandler := handler.NewLedgerHandler(ledgerUseCase)
+	payoutRepository := repository.NewPayoutRepository(gormDB)
+	payoutUseCase := usecase.NewPayoutUseCase(payoutRepository, ledgerRepository, settingRepository, notificationRepository, gateway)
+	payoutHandler := handler.NewPayoutHandler(payoutUseCase)
+	invoiceUseCase := usecase.NewInvoiceUseCase(invoiceRepository)
+	invoiceHandler := handler.NewInvoiceHandler(invoiceUseCase)
+	taxUseCase := usecase.NewTaxUseCase(taxRepository)
+	taxHandler := handler.NewTaxHandler(taxUseCase)
+	giftUseCase := usecase.NewGiftUseCase(giftRepository, subscriptionRepository, ledgerRepository, settingRepository, notificationRepository, taxRepository, gateway)
+	giftHandler := handler.NewGiftHandler(giftUseCase)
+	tipUseCase := usecase.NewTipUseCase(tipRepository, ledgerRepository, settingRepository, notificationRepository, gateway)
+	tipHandler := handler.NewTipHandler(tipUseCase)
+	analyticsRepository := repository.NewAnalyticsRepository(gormDB)
+	analyticsUseCase := usecase.NewAnalyticsUseCase(analyticsRepository, settingRepository, notificationRepository, gateway)
+	analyticsHandler := handler.NewAnalyticsHandler(analyticsUseCase)
+	moderationRepository := repository.NewModerationRepository(gormDB)
+	moderationUseCase := usecase.NewModerationUseCase(moderationRepository, notificationRepository)
+	moderationHandler := handler.NewModerationHandler(moderationUseCase)
+	enforcementUseCase := usecase.NewEnforcementUseCase(enforcementRepository, notificationRepository)
+	enforcementHandler := handler.NewEnforcementHandler(enforcementUseCase)
+	auditRepository := repository.NewAuditRepository(gormDB)
+	auditUseCase := usecase.NewAuditUseCase(auditRepository)
+	auditHandler := handler.NewAuditHandler(auditUseCase)
+	schedulerScheduler := scheduler.NewScheduler(subscriptionUseCase, payoutUseCase, analyticsUseCase, videoUseCase)
+	serverHTTP := http.NewServerHTTP(userHandler, otpHandler, adminHandler, categoryHandler, videoHandler, subscriptionHandler, searchHandler, tagHandler, notificationHandler, tierHandler, couponHandler, ledgerHandler, payoutHandler, invoiceHandler, taxHandler, giftHandler, tipHandler, analyticsHandler, moderationHandler, enforcementHandler, auditHandler, schedulerScheduler)
 	return serverHTTP, nil
 }
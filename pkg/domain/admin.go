@@ -1,15 +1,98 @@
 package domain
 
+import (
+	"main/pkg/money"
+	"time"
+)
+
 // Admin represents an administrative user in the system.
+// Admins from before roles existed are super admins, a disabled admin can no longer log in or act.
 type Admin struct {
 	ID       uint   `json:"id" gorm:"unique;not null"`
 	Username string `json:"name" gorm:"validate:required"`
 	Email    string `json:"email" gorm:"validate:required"`
 	Password string `json:"password" gorm:"validate:required"`
+	Role     string `json:"role" gorm:"default:'super_admin'"`
+	Disabled bool   `json:"disabled" gorm:"default:false"`
 }
+
+// Admin roles
+const (
+	AdminSuperAdmin     = "super_admin"
+	AdminModerator      = "moderator"
+	AdminFinance        = "finance"
+	AdminContentCurator = "content_curator"
+)
+
+// Admin permissions, each admin route needs one
+const (
+	PermissionUsers      = "users"      // list, block and unblock users
+	PermissionModeration = "moderation" // reports, moderation cases, enforcement and appeals
+	PermissionContent    = "content"    // categories and tags
+	PermissionPlans      = "plans"      // subscription plans and their price limits
+	PermissionFinance    = "finance"    // coupons, payments, tips, ledger, payouts and taxes
+	PermissionAnalytics  = "analytics"  // dashboard and search analytics
+	PermissionAudit      = "audit"      // the audit log
+	PermissionAdmins     = "admins"     // admin accounts
+)
+
+// AdminRolePermissions is what each role may do
+var AdminRolePermissions = map[string]map[string]bool{
+	AdminSuperAdmin: {
+		PermissionUsers: true, PermissionModeration: true, PermissionContent: true, PermissionPlans: true,
+		PermissionFinance: true, PermissionAnalytics: true, PermissionAudit: true, PermissionAdmins: true,
+	},
+	AdminModerator: {
+		PermissionUsers: true, PermissionModeration: true, PermissionAnalytics: true,
+	},
+	AdminFinance: {
+		PermissionPlans: true, PermissionFinance: true, PermissionAnalytics: true,
+	},
+	AdminContentCurator: {
+		PermissionContent: true, PermissionAnalytics: true,
+	},
+}
+
+// SubscriptionPlan is a tier a creator offers, plans without a creator are the global plans from before tiers existed.
+// Price is what buyers pay in regions the plan has no regional price for.
 type SubscriptionPlan struct {
-	ID       int     `gorm:"primaryKey" json:"id"`
-	Name     string  `json:"name"`
-	Duration int     `json:"duration"`
-	Price    float64 `json:"price"`
+	ID        int         `gorm:"primaryKey" json:"id"`
+	CreatorID int         `json:"creator_id" gorm:"index;default:0"`
+	Name      string      `json:"name"`
+	Duration  int         `json:"duration"`
+	Price     money.Money `json:"price" gorm:"embedded;embeddedPrefix:price_"`
+	Perks     string      `json:"perks"`
+	TrialDays int         `json:"trial_days" gorm:"default:0"`   // free days for a user's first subscription to the creator
+	Archived  bool        `json:"archived" gorm:"default:false"` // a deleted tier that still has subscriptions, it can no longer be bought
 }
+
+// PlanPrice is the price of a plan for buyers in a region, the region is an ISO 3166 country code such as US.
+type PlanPrice struct {
+	ID     uint             `json:"id" gorm:"primaryKey"`
+	PlanID int              `json:"plan_id" gorm:"not null;uniqueIndex:idx_plan_price_region"`
+	Plan   SubscriptionPlan `json:"-" gorm:"foreignKey:PlanID;constraint:OnDelete:CASCADE"`
+	Region string           `json:"region" gorm:"not null;uniqueIndex:idx_plan_price_region"`
+	Price  money.Money      `json:"price" gorm:"embedded;embeddedPrefix:price_"`
+}
+
+// TierVideo is an exclusive video a tier unlocks. An exclusive video in no tier is unlocked by any of the creator's tiers.
+type TierVideo struct {
+	ID      uint             `json:"id" gorm:"primaryKey"`
+	PlanID  int              `json:"plan_id" gorm:"not null;uniqueIndex:idx_tier_video"`
+	Plan    SubscriptionPlan `json:"-" gorm:"foreignKey:PlanID;constraint:OnDelete:CASCADE"`
+	VideoID uint             `json:"video_id" gorm:"not null;uniqueIndex:idx_tier_video;index"`
+	Video   Video            `json:"-" gorm:"foreignKey:VideoID;references:ID;constraint:OnDelete:CASCADE"`
+}
+
+// Setting is a value admins can change at runtime, stored by key.
+type Setting struct {
+	Key       string    `json:"key" gorm:"primaryKey"`
+	Value     string    `json:"value"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// The platform fee is the percent of each payment the platform keeps, the default is used until an admin sets it.
+const (
+	PlatformFeeKey            = "platform_fee_percent"
+	DefaultPlatformFeePercent = 20
+)
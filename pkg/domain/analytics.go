@@ -0,0 +1,104 @@
+package domain
+
+import "time"
+
+// VideoView is one time a user started watching a video, WatchedSeconds is the furthest point they reached.
+type VideoView struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	VideoID        uint      `json:"video_id" gorm:"not null;index"`
+	UserID         int       `json:"user_id" gorm:"index"`
+	CreatorID      int       `json:"creator_id" gorm:"index"`
+	WatchedSeconds int       `json:"watched_seconds" gorm:"default:0"`
+	CreatedAt      time.Time `json:"created_at" gorm:"index"`
+}
+
+// CreatorDailyStat is what happened on one day across all of a creator's channel, it is rolled up from the raw events.
+type CreatorDailyStat struct {
+	CreatorID         int       `json:"creator_id" gorm:"primaryKey;autoIncrement:false"`
+	Day               time.Time `json:"day" gorm:"primaryKey;type:date"`
+	Views             int64     `json:"views"`
+	WatchSeconds      int64     `json:"watch_seconds"`
+	Likes             int64     `json:"likes"`
+	Comments          int64     `json:"comments"`
+	NewFollowers      int64     `json:"new_followers"`
+	SubscribersGained int64     `json:"subscribers_gained"`
+	SubscribersLost   int64     `json:"subscribers_lost"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// CreatorDailyRevenue is the creator's share of the charges less refunds posted on one day, in the currency's minor unit.
+type CreatorDailyRevenue struct {
+	CreatorID int       `json:"creator_id" gorm:"primaryKey;autoIncrement:false"`
+	Day       time.Time `json:"day" gorm:"primaryKey;type:date"`
+	Currency  string    `json:"currency" gorm:"primaryKey"`
+	Amount    int64     `json:"amount"`
+}
+
+// VideoDailyStat is what happened on one day on one video.
+type VideoDailyStat struct {
+	VideoID      uint      `json:"video_id" gorm:"primaryKey;autoIncrement:false"`
+	Day          time.Time `json:"day" gorm:"primaryKey;type:date"`
+	CreatorID    int       `json:"creator_id" gorm:"index"`
+	Views        int64     `json:"views"`
+	WatchSeconds int64     `json:"watch_seconds"`
+	Likes        int64     `json:"likes"`
+	Comments     int64     `json:"comments"`
+}
+
+// Analytics report formats
+const (
+	ReportCSV    = "csv"
+	ReportNDJSON = "ndjson"
+)
+
+// AnalyticsReportSetting is whether the creator gets a summary of every week, and in which format.
+type AnalyticsReportSetting struct {
+	CreatorID int       `json:"creator_id" gorm:"primaryKey;autoIncrement:false"`
+	Weekly    bool      `json:"weekly" gorm:"default:false"`
+	Format    string    `json:"format" gorm:"default:'csv'"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AnalyticsReport is a summary of the creator's analytics over a period, kept as the file they download.
+type AnalyticsReport struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	CreatorID   int       `json:"creator_id" gorm:"not null;uniqueIndex:idx_analytics_report_period"`
+	PeriodStart time.Time `json:"period_start" gorm:"type:date;not null;uniqueIndex:idx_analytics_report_period"`
+	PeriodEnd   time.Time `json:"period_end" gorm:"type:date;not null"`
+	Format      string    `json:"format"`
+	Filename    string    `json:"filename"`
+	Content     []byte    `json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// DailyActiveUser is a user who watched, liked, commented, followed, subscribed or tipped on the day.
+type DailyActiveUser struct {
+	Day    time.Time `json:"day" gorm:"primaryKey;type:date"`
+	UserID int       `json:"user_id" gorm:"primaryKey;autoIncrement:false"`
+}
+
+// PlatformDailyStat is the activity on the whole platform on one day. Weekly and monthly active users are of the
+// 7 and 30 days up to the day, active subscriptions are those in effect at its end. The moderation backlog can only
+// be counted as it is now, so it is unknown for days before the aggregator ran on them.
+type PlatformDailyStat struct {
+	Day                 time.Time `json:"day" gorm:"primaryKey;type:date"`
+	ActiveUsers         int64     `json:"active_users"`
+	WeeklyActiveUsers   int64     `json:"weekly_active_users"`
+	MonthlyActiveUsers  int64     `json:"monthly_active_users"`
+	Signups             int64     `json:"signups"`
+	Uploads             int64     `json:"uploads"`
+	Views               int64     `json:"views"`
+	WatchSeconds        int64     `json:"watch_seconds"`
+	ActiveSubscriptions int64     `json:"active_subscriptions"`
+	Reports             int64     `json:"reports"`
+	ModerationBacklog   *int64    `json:"moderation_backlog"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// PlatformDailyRevenue is what the gateway charged and refunded on one day, tax included, in the currency's minor unit.
+type PlatformDailyRevenue struct {
+	Day      time.Time `json:"day" gorm:"primaryKey;type:date"`
+	Currency string    `json:"currency" gorm:"primaryKey"`
+	Gross    int64     `json:"gross"`
+	Refunded int64     `json:"refunded"`
+}
@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// AuditLog records a privileged action an admin took, it is append only.
+// Before and After are the target as it was around the action, missing when the action has no single target.
+type AuditLog struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	AdminID    uint      `json:"admin_id" gorm:"index"`
+	Action     string    `json:"action" gorm:"not null;index"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	TargetType string    `json:"target_type,omitempty" gorm:"index:idx_audit_logs_target"`
+	TargetID   string    `json:"target_id,omitempty" gorm:"index:idx_audit_logs_target"`
+	Query      string    `json:"query,omitempty"`
+	Request    *string   `json:"request,omitempty" gorm:"type:jsonb"` // the JSON body, passwords removed
+	Before     *string   `json:"before,omitempty" gorm:"type:jsonb"`
+	After      *string   `json:"after,omitempty" gorm:"type:jsonb"`
+	Status     int       `json:"status"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at" gorm:"index"`
+}
@@ -7,8 +7,13 @@ import (
 )
 
 type Category struct {
-	ID       uint   `json:"id" gorm:"primarykey"`
-	Category string `json:"category" gorm:"unique;not null"`
+	ID          uint   `json:"id" gorm:"primarykey"`
+	Category    string `json:"category" gorm:"unique;not null"`
+	Slug        string `json:"slug"` // unique index is created after the backfill in db.ConnectDatabase
+	ParentID    *uint  `json:"parent_id" gorm:"index"`
+	Description string `json:"description"`
+	IconURL     string `json:"icon_url"`
+	SortOrder   int    `json:"sort_order" gorm:"default:0"`
 }
 
 //	type Video struct {
@@ -48,17 +53,21 @@ type Video struct {
 	Description string    `json:"description"`
 	URL         string    `json:"url"`
 	CategoryID  int       `json:"category_id"`
-	Category    Category  `json:"category" gorm:"foreignkey:CategoryID;constraint:OnDelete:CASCADE"`
+	Category    Category  `json:"category" gorm:"foreignkey:CategoryID;constraint:OnDelete:RESTRICT"`
 	Likes       int       `json:"likes" gorm:"default:0"`
 	Views       int       `json:"views" gorm:"default:0"`
 	Exclusive   bool      `json:"exclusive" gorm:"default:false"`
+	Duration    int       `json:"duration" gorm:"default:0"` // length of the video in seconds, 0 until it is probed and -1 if probing failed
 	CreatedAt   time.Time `json:"created_at"`
+	// TakenDownAt is set while the video is taken down, it is left out of every read until the takedown is revoked
+	TakenDownAt *time.Time `json:"-" gorm:"index"`
 }
 
 type VideoLikes struct {
-	ID      uint `json:"id" gorm:"primaryKey"`
-	UserID  uint `json:"user_id"`
-	VideoID uint `json:"video_id"`
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id"`
+	VideoID   uint       `json:"video_id"`
+	CreatedAt *time.Time `json:"created_at"` // unknown for likes made before it was recorded
 }
 
 // Comment represents a comment on a video
@@ -67,6 +76,13 @@ type Comment struct {
 	UserID  uint   `json:"user_id" gorm:"not null"`
 	VideoID uint   `json:"video_id" gorm:"not null"`
 	Content string `json:"content"`
+	// CreatedAt is unknown for comments made before it was recorded
+	CreatedAt *time.Time `json:"created_at"`
+	// TipAmount is what the commenter tipped with the comment in the platform currency's minor unit, such comments are highlighted
+	TipAmount   int64  `json:"tip_amount,omitempty" gorm:"->;-:migration"`
+	TipCurrency string `json:"tip_currency,omitempty" gorm:"->;-:migration"`
+	// TakenDownAt is set while the comment is taken down, it is left out of every read until the takedown is revoked
+	TakenDownAt *time.Time `json:"-" gorm:"index"`
 }
 
 // Tag represents a tags.
@@ -74,23 +90,83 @@ type Tag struct {
 	ID  uint   `json:"id" gorm:"primaryKey"`
 	Tag string `json:"tag" gorm:"not null"`
 }
+
+// TagAlias maps another spelling of a tag to its canonical tag, the alias is stored lowercase.
+type TagAlias struct {
+	ID    uint   `json:"id" gorm:"primaryKey"`
+	Alias string `json:"alias" gorm:"uniqueIndex;not null"`
+	TagID uint   `json:"tag_id" gorm:"not null;index"`
+	Tag   Tag    `json:"-" gorm:"foreignKey:TagID;constraint:OnDelete:CASCADE"`
+}
+
+// BannedTag is a tag creators are not allowed to use, the tag is stored lowercase.
+type BannedTag struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Tag       string    `json:"tag" gorm:"uniqueIndex;not null"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type VideoTags struct {
 	ID      uint   `json:"id" gorm:"primaryKey"`
 	UserID  int    `json:"user_id"`
 	VideoID uint   `json:"video_id" gorm:"not null"`
 	Tag     string `json:"tag" gorm:"not null"`
+	TagID   *uint  `json:"tag_id" gorm:"index"` // canonical tag, the tag text above is kept in sync with it
 	// Tag     Tag  `json:"-" gorm:"foreignKey:TagID;constraint:OnDelete:CASCADE"`
 }
 
+// Subscription statuses, a subscription moves pending -> active -> past_due -> expired,
+// or to cancelled when the user cancels and the paid period ends.
+// A subscription renewed or changed to another plan is replaced by the new one.
+// A subscription whose payment is disputed is suspended until the dispute is won or reviewed.
+const (
+	SubscriptionPending   = "pending"
+	SubscriptionActive    = "active"
+	SubscriptionPastDue   = "past_due"
+	SubscriptionCancelled = "cancelled"
+	SubscriptionExpired   = "expired"
+	SubscriptionReplaced  = "replaced"
+	SubscriptionSuspended = "suspended"
+)
+
+// Subscription kinds, how a purchase relates to the subscription the user already had with the creator.
+const (
+	SubscriptionKindNew       = "new"
+	SubscriptionKindRenewal   = "renewal"
+	SubscriptionKindUpgrade   = "upgrade"
+	SubscriptionKindDowngrade = "downgrade"
+	SubscriptionKindTrial     = "trial"
+)
+
 type SubscriptionList struct {
-	ID               int              `json:"id" gorm:"primaryKey"`
-	CreatorID        int              `json:"creator_id"`
-	UserID           int              `json:"user_id"`
-	User             User             `json:"-" gorm:"foreignKey:UserID"`
-	SubscribedAt     time.Time        `json:"subscribed_at" gorm:"-"`
-	PlanID           int              `json:"plan_id"`
-	PaymentStatus    string           `json:"paymentStatus" gorm:"default:'Pending'"`
-	SubscriptionPlan SubscriptionPlan `json:"-" gorm:"foreignKey:PlanID"`
-	PaymentID        string           `json:"paymentID"`
-	IsActive         bool             `json:"isActive" gorm:"default:false"`
+	ID                     int              `json:"id" gorm:"primaryKey"`
+	CreatorID              int              `json:"creator_id"`
+	UserID                 int              `json:"user_id"`
+	User                   User             `json:"-" gorm:"foreignKey:UserID"`
+	SubscribedAt           *time.Time       `json:"subscribed_at"`
+	PlanID                 int              `json:"plan_id"`
+	PaymentStatus          string           `json:"paymentStatus" gorm:"default:'Pending'"`
+	SubscriptionPlan       SubscriptionPlan `json:"-" gorm:"foreignKey:PlanID"`
+	PaymentID              string           `json:"paymentID"`
+	IsActive               bool             `json:"isActive" gorm:"default:false"` // true while the status is active or past_due
+	Status                 string           `json:"status" gorm:"index;default:'pending'"`
+	CurrentPeriodStart     *time.Time       `json:"current_period_start"`
+	CurrentPeriodEnd       *time.Time       `json:"current_period_end" gorm:"index"`
+	CancelAtPeriodEnd      bool             `json:"cancel_at_period_end" gorm:"default:false"`
+	CancelledAt            *time.Time       `json:"cancelled_at"`
+	RenewalReminderSentAt  *time.Time       `json:"-"`
+	RazorOrderID           string           `json:"razorOrderID" gorm:"index"` // order created on the payment gateway
+	Amount                 int64            `json:"amount"`                    // charged amount in the currency's minor unit
+	AmountRefunded         int64            `json:"amount_refunded" gorm:"default:0"`
+	Currency               string           `json:"currency"`
+	Kind                   string           `json:"kind" gorm:"default:'new'"`
+	CouponID               *uint            `json:"coupon_id"`
+	Discount               int64            `json:"discount"` // taken off the plan price by the coupon, in the currency's minor unit
+	TaxName                string           `json:"tax_name"`
+	TaxRate                float64          `json:"tax_rate"`                 // percent
+	TaxInclusive           bool             `json:"tax_inclusive"`            // the tax is part of the price rather than added to it
+	Tax                    int64            `json:"tax"`                      // part of the amount that is tax, in the currency's minor unit
+	PreviousSubscriptionID *int             `json:"previous_subscription_id"` // subscription this one renewed or replaced
+	CreatedAt              time.Time        `json:"created_at"`
 }
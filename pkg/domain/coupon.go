@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// Coupon discount types
+const (
+	CouponPercent = "percent"
+	CouponFixed   = "fixed"
+)
+
+// Coupon is a promo code for subscriptions. Coupons without a creator are platform-wide and can only be made by admins.
+type Coupon struct {
+	ID              uint       `json:"id" gorm:"primaryKey"`
+	Code            string     `json:"code" gorm:"uniqueIndex;not null"` // stored uppercase
+	CreatorID       int        `json:"creator_id" gorm:"index;default:0"`
+	DiscountType    string     `json:"discount_type" gorm:"not null"`
+	DiscountValue   float64    `json:"discount_value"`                   // percent, or a fixed amount in the platform currency's major unit
+	MaxRedemptions  int        `json:"max_redemptions" gorm:"default:0"` // 0 means unlimited
+	RedemptionCount int        `json:"redemption_count" gorm:"default:0"`
+	FirstTimeOnly   bool       `json:"first_time_only" gorm:"default:false"`
+	ExpiresAt       *time.Time `json:"expires_at"`
+	Active          bool       `json:"active" gorm:"default:true"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// CouponRedemption records a coupon used on a paid subscription, a user can redeem each coupon once.
+type CouponRedemption struct {
+	ID                 uint      `json:"id" gorm:"primaryKey"`
+	CouponID           uint      `json:"coupon_id" gorm:"not null;uniqueIndex:idx_coupon_user"`
+	Coupon             Coupon    `json:"-" gorm:"foreignKey:CouponID"`
+	UserID             int       `json:"user_id" gorm:"not null;uniqueIndex:idx_coupon_user"`
+	SubscriptionListID int       `json:"subscription_list_id" gorm:"not null;uniqueIndex"`
+	Discount           int64     `json:"discount"` // in the currency of the subscription, in its minor unit
+	CreatedAt          time.Time `json:"created_at"`
+}
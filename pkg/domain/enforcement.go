@@ -0,0 +1,51 @@
+package domain
+
+import "time"
+
+// Enforcement action types. Warnings and strikes count against the user until they expire, a suspension keeps the user
+// out until it ends, a takedown hides a video or comment and a ban blocks the user until it is revoked.
+const (
+	EnforcementWarning    = "warning"
+	EnforcementStrike     = "strike"
+	EnforcementSuspension = "suspension"
+	EnforcementTakedown   = "takedown"
+	EnforcementBan        = "ban"
+)
+
+// Appeal statuses, an overturned appeal revokes the action.
+const (
+	AppealPending    = "pending"
+	AppealUpheld     = "upheld"
+	AppealOverturned = "overturned"
+)
+
+// EnforcementAction is an action taken against a user, the reason is shown to the user.
+// Revoking an action lifts it early, a revoked takedown shows the content again and a revoked ban unblocks the user.
+type EnforcementAction struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	UserID       int        `json:"user_id" gorm:"not null;index"`
+	Type         string     `json:"type" gorm:"not null"`
+	Reason       string     `json:"reason" gorm:"not null"`
+	CaseID       *uint      `json:"case_id" gorm:"index"` // moderation case the action was taken in
+	TargetType   string     `json:"target_type,omitempty"`
+	TargetID     int        `json:"target_id,omitempty"`
+	IssuedBy     uint       `json:"issued_by"`
+	ExpiresAt    *time.Time `json:"expires_at"` // end of a suspension, or when a warning or strike stops counting
+	RevokedAt    *time.Time `json:"revoked_at"`
+	RevokedBy    *uint      `json:"revoked_by"`
+	RevokeReason string     `json:"revoke_reason,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// Appeal is a user's request to have an action against them reviewed, an action is appealed once.
+type Appeal struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	ActionID  uint       `json:"action_id" gorm:"not null;uniqueIndex"`
+	UserID    int        `json:"user_id" gorm:"not null;index"`
+	Message   string     `json:"message"`
+	Status    string     `json:"status" gorm:"index;default:'pending'"`
+	Response  string     `json:"response,omitempty"` // the admin's answer, shown to the user
+	DecidedBy *uint      `json:"decided_by"`
+	DecidedAt *time.Time `json:"decided_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,60 @@
+package domain
+
+import "time"
+
+// Gift purchase statuses
+const (
+	GiftPending  = "pending"
+	GiftPaid     = "paid"
+	GiftRefunded = "refunded"
+)
+
+// Gift code statuses, worked out from the code rather than stored
+const (
+	GiftCodeAvailable = "available"
+	GiftCodeRedeemed  = "redeemed"
+	GiftCodeExpired   = "expired"
+)
+
+// GiftPurchase is one or more gift codes bought for a creator's plan. Its codes are made once it is paid,
+// a creator's giveaway of their own plan is paid from the start and charges nothing.
+// A purchase for a recipient is a single code that is redeemed for them right away.
+type GiftPurchase struct {
+	ID           uint             `json:"id" gorm:"primaryKey"`
+	BuyerID      int              `json:"buyer_id" gorm:"not null;index"`
+	CreatorID    int              `json:"creator_id" gorm:"not null;index"`
+	PlanID       int              `json:"plan_id" gorm:"not null"`
+	Plan         SubscriptionPlan `json:"-" gorm:"foreignKey:PlanID"`
+	RecipientID  *int             `json:"recipient_id"`
+	Quantity     int              `json:"quantity"`
+	ValidDays    int              `json:"valid_days"` // the codes can be redeemed for this many days after they are made
+	Giveaway     bool             `json:"giveaway" gorm:"default:false"`
+	Status       string           `json:"status" gorm:"index;default:'pending'"`
+	Amount       int64            `json:"amount"` // charged for all the codes, in the currency's minor unit
+	Refunded     int64            `json:"refunded" gorm:"default:0"`
+	Currency     string           `json:"currency"`
+	TaxName      string           `json:"tax_name"`
+	TaxRate      float64          `json:"tax_rate"` // percent
+	TaxInclusive bool             `json:"tax_inclusive"`
+	Tax          int64            `json:"tax"` // part of the amount that is tax, in the currency's minor unit
+	RazorOrderID string           `json:"razor_order_id" gorm:"index"`
+	PaymentID    string           `json:"payment_id" gorm:"index"`
+	PaidAt       *time.Time       `json:"paid_at"`
+	CreatedAt    time.Time        `json:"created_at"`
+}
+
+// GiftCode can be redeemed once, before it expires, for a subscription to the plan it was bought for.
+type GiftCode struct {
+	ID                 uint         `json:"id" gorm:"primaryKey"`
+	Code               string       `json:"code" gorm:"uniqueIndex;not null"`
+	PurchaseID         uint         `json:"purchase_id" gorm:"not null;index"`
+	Purchase           GiftPurchase `json:"-" gorm:"foreignKey:PurchaseID;constraint:OnDelete:CASCADE"`
+	PlanID             int          `json:"plan_id" gorm:"not null"`
+	CreatorID          int          `json:"creator_id" gorm:"not null;index"`
+	ExpiresAt          time.Time    `json:"expires_at"`
+	RedeemedBy         *int         `json:"redeemed_by"`
+	RedeemedAt         *time.Time   `json:"redeemed_at"`
+	SubscriptionListID *int         `json:"subscription_list_id"`
+	Status             string       `json:"status" gorm:"-"`
+	CreatedAt          time.Time    `json:"created_at"`
+}
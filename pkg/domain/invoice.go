@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// Invoice is issued for every charge of a subscription and never changes afterwards.
+// Numbers run without gaps within a year, e.g. INV-2026-000042. Amounts are in the currency's minor unit.
+type Invoice struct {
+	ID                 uint       `json:"id" gorm:"primaryKey"`
+	Number             string     `json:"number" gorm:"uniqueIndex;not null"`
+	Year               int        `json:"-" gorm:"not null;uniqueIndex:idx_invoice_sequence"`
+	Sequence           int64      `json:"-" gorm:"not null;uniqueIndex:idx_invoice_sequence"`
+	SubscriptionListID int        `json:"subscription_list_id" gorm:"uniqueIndex;not null"`
+	PaymentID          string     `json:"payment_id"`
+	UserID             int        `json:"user_id" gorm:"index"`
+	CreatorID          int        `json:"creator_id" gorm:"index"`
+	BuyerName          string     `json:"buyer_name"`
+	BuyerEmail         string     `json:"buyer_email"`
+	CreatorName        string     `json:"creator_name"`
+	PlanName           string     `json:"plan_name"`
+	PeriodStart        *time.Time `json:"period_start"`
+	PeriodEnd          *time.Time `json:"period_end"`
+	Subtotal           int64      `json:"subtotal"` // plan price before the discount
+	Discount           int64      `json:"discount"`
+	CouponCode         string     `json:"coupon_code"`
+	TaxLabel           string     `json:"tax_label"`
+	TaxRate            float64    `json:"tax_rate"`
+	TaxInclusive       bool       `json:"tax_inclusive"` // the tax was part of the price, otherwise it was added to it
+	Tax                int64      `json:"tax"`           // included in the total either way
+	Total              int64      `json:"total"`         // what was charged
+	Currency           string     `json:"currency"`
+	IssuedAt           time.Time  `json:"issued_at"`
+}
+
+// InvoiceSequence is the last invoice number issued in a year. It is raised in the transaction that issues the invoice,
+// so a failed invoice gives its number back and the numbers stay gapless.
+type InvoiceSequence struct {
+	Year       int   `gorm:"primaryKey;autoIncrement:false"`
+	LastNumber int64 `gorm:"not null"`
+}
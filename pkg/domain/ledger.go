@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+// Ledger transaction types
+const (
+	LedgerCharge = "charge"
+	LedgerRefund = "refund"
+	LedgerPayout = "payout"
+)
+
+// Ledger accounts. Money the gateway collected sits in the clearing account until it is paid out,
+// every charge is split between the tax owed to the authorities, the platform's fee and what the platform owes the creator.
+const (
+	AccountGatewayClearing = "gateway_clearing"
+	AccountTaxPayable      = "tax_payable"
+	AccountPlatformRevenue = "platform_revenue"
+	AccountCreatorPayable  = "creator_payable"
+)
+
+// LedgerTransaction groups the entries of one money movement, its debits and credits always balance.
+// Reference is the gateway's ID for the movement, so a movement is never recorded twice.
+type LedgerTransaction struct {
+	ID                 uint          `json:"id" gorm:"primaryKey"`
+	Type               string        `json:"type" gorm:"not null;uniqueIndex:idx_ledger_reference"`
+	Reference          string        `json:"reference" gorm:"not null;uniqueIndex:idx_ledger_reference"`
+	SubscriptionListID *int          `json:"subscription_list_id" gorm:"index"`
+	CreatorID          int           `json:"creator_id" gorm:"index"`
+	Currency           string        `json:"currency"`
+	Description        string        `json:"description"`
+	Entries            []LedgerEntry `json:"entries" gorm:"foreignKey:TransactionID"`
+	CreatedAt          time.Time     `json:"created_at" gorm:"index"`
+}
+
+// LedgerEntry is one side of a transaction, amounts are in the currency's minor unit.
+// CreatorID is set on creator accounts, so each creator has their own balance.
+type LedgerEntry struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	TransactionID uint      `json:"transaction_id" gorm:"not null;index"`
+	Account       string    `json:"account" gorm:"not null;index:idx_ledger_account"`
+	CreatorID     int       `json:"creator_id" gorm:"index:idx_ledger_account"`
+	Debit         int64     `json:"debit" gorm:"default:0"`
+	Credit        int64     `json:"credit" gorm:"default:0"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,64 @@
+package domain
+
+import "time"
+
+// Report categories. Reports made before categories existed are "other".
+const (
+	ReportSpam          = "spam"
+	ReportHarassment    = "harassment"
+	ReportCheating      = "cheating"
+	ReportImpersonation = "impersonation"
+	ReportCopyright     = "copyright"
+	ReportOther         = "other"
+)
+
+// What a report is about
+const (
+	ReportTargetUser    = "user"
+	ReportTargetVideo   = "video"
+	ReportTargetComment = "comment"
+)
+
+// Moderation case statuses, a case is in review once it is assigned to an admin.
+const (
+	CaseOpen      = "open"
+	CaseInReview  = "in_review"
+	CaseResolved  = "resolved"
+	CaseDismissed = "dismissed"
+)
+
+// Actions a case is resolved with
+const (
+	ModerationDismiss       = "dismiss"
+	ModerationRemoveContent = "remove_content"
+	ModerationBlockUser     = "block_user"
+)
+
+// ModerationCase groups the reports of the same category against the same user, video or comment while it is being handled,
+// a report made after the case is closed starts a new one.
+type ModerationCase struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	TargetType   string     `json:"target_type" gorm:"not null"`
+	TargetID     int        `json:"target_id" gorm:"not null"`
+	TargetUserID int        `json:"target_user_id" gorm:"index"` // the reported user, or who posted the reported video or comment
+	Category     string     `json:"category" gorm:"not null"`
+	Status       string     `json:"status" gorm:"index;default:'open'"`
+	ReportCount  int        `json:"report_count" gorm:"default:0"`
+	AssigneeID   *uint      `json:"assignee_id" gorm:"index"` // admin handling the case
+	Action       string     `json:"action"`
+	ResolvedBy   *uint      `json:"resolved_by"`
+	ResolvedAt   *time.Time `json:"resolved_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	Reports      []Reports  `json:"reports,omitempty" gorm:"foreignKey:CaseID"`
+	Notes        []CaseNote `json:"notes,omitempty" gorm:"foreignKey:CaseID"`
+}
+
+// CaseNote is an admin's note on a case, notes are only seen by admins.
+type CaseNote struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CaseID    uint      `json:"case_id" gorm:"not null;index"`
+	AdminID   uint      `json:"admin_id"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// Notification is a message shown to a user in the app.
+type Notification struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    int       `json:"user_id" gorm:"index;not null"`
+	Type      string    `json:"type"`
+	Title     string    `json:"title"`
+	Message   string    `json:"message"`
+	Read      bool      `json:"read" gorm:"default:false"`
+	CreatedAt time.Time `json:"created_at"`
+}
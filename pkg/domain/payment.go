@@ -0,0 +1,63 @@
+package domain
+
+import "time"
+
+// PaymentEvent is a webhook delivered by the payment gateway, stored raw so it can be replayed.
+type PaymentEvent struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	EventID     string     `json:"event_id" gorm:"uniqueIndex;not null"`
+	Event       string     `json:"event" gorm:"index"`
+	Payload     string     `json:"payload" gorm:"type:text"`
+	Signature   string     `json:"signature"`
+	Status      string     `json:"status" gorm:"index;default:'received'"` // received, processed, ignored, failed
+	Error       string     `json:"error"`
+	Attempts    int        `json:"attempts" gorm:"default:0"`
+	ProcessedAt *time.Time `json:"processed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// Refund sources, a chargeback is a dispute the platform lost and is treated as a refund of the disputed amount
+const (
+	RefundSourceAdmin      = "admin"
+	RefundSourceGateway    = "gateway"
+	RefundSourceChargeback = "chargeback"
+)
+
+// PaymentRefund is money given back on a subscription's payment, RefundID is the gateway's ID so a refund is applied once.
+type PaymentRefund struct {
+	ID                 uint      `json:"id" gorm:"primaryKey"`
+	SubscriptionListID int       `json:"subscription_list_id" gorm:"not null;index"`
+	PaymentID          string    `json:"payment_id" gorm:"index"`
+	RefundID           string    `json:"refund_id" gorm:"uniqueIndex;not null"`
+	Amount             int64     `json:"amount"` // minor units
+	Reason             string    `json:"reason"`
+	Source             string    `json:"source"`
+	RevokeAccess       bool      `json:"revoke_access"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// Dispute statuses as the gateway reports them
+const (
+	DisputeOpen        = "open"
+	DisputeUnderReview = "under_review"
+	DisputeWon         = "won"
+	DisputeLost        = "lost"
+	DisputeClosed      = "closed"
+)
+
+// PaymentDispute is a chargeback raised by the subscriber's bank. The subscription is suspended while it is open
+// and the dispute needs an admin's review until one marks it reviewed.
+type PaymentDispute struct {
+	ID                 uint       `json:"id" gorm:"primaryKey"`
+	DisputeID          string     `json:"dispute_id" gorm:"uniqueIndex;not null"`
+	SubscriptionListID int        `json:"subscription_list_id" gorm:"not null;index"`
+	PaymentID          string     `json:"payment_id" gorm:"index"`
+	Amount             int64      `json:"amount"` // minor units
+	ReasonCode         string     `json:"reason_code"`
+	Status             string     `json:"status" gorm:"index"`
+	NeedsReview        bool       `json:"needs_review" gorm:"index;default:true"`
+	ReviewNote         string     `json:"review_note"`
+	ReviewedAt         *time.Time `json:"reviewed_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,60 @@
+package domain
+
+import "time"
+
+// Payout account methods
+const (
+	PayoutMethodBank = "bank"
+	PayoutMethodUPI  = "upi"
+)
+
+// Payout and payout batch statuses, a batch is approved as a whole and its payouts are then paid one by one
+const (
+	PayoutPending  = "pending"
+	PayoutApproved = "approved"
+	PayoutPaid     = "paid"
+)
+
+// PayoutAccount is where a creator's earnings are sent, creators without one are left out of payout batches.
+type PayoutAccount struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	CreatorID     int       `json:"creator_id" gorm:"uniqueIndex;not null"`
+	Method        string    `json:"method" gorm:"not null"`
+	AccountHolder string    `json:"account_holder"`
+	AccountNumber string    `json:"account_number"`
+	IFSC          string    `json:"ifsc"`
+	UPIID         string    `json:"upi_id"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// PayoutBatch pays out the balances creators earned up to a month in one currency, one batch is made per month and currency.
+// Cutoff is when the earnings it covers end, charges after it are still on hold for refunds.
+type PayoutBatch struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	Period     string     `json:"period" gorm:"not null;uniqueIndex:idx_payout_batch_period"` // YYYY-MM
+	Cutoff     time.Time  `json:"cutoff"`
+	Status     string     `json:"status" gorm:"default:'pending'"`
+	Total      int64      `json:"total"` // minor units
+	Currency   string     `json:"currency" gorm:"uniqueIndex:idx_payout_batch_period"`
+	Payouts    []Payout   `json:"payouts,omitempty" gorm:"foreignKey:BatchID"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ApprovedAt *time.Time `json:"approved_at"`
+	PaidAt     *time.Time `json:"paid_at"`
+}
+
+// Payout is what one creator is paid in a batch, the amount is in minor units.
+// Reference is the bank or UPI transfer ID entered when it is marked paid.
+type Payout struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	BatchID   uint           `json:"batch_id" gorm:"not null;uniqueIndex:idx_payout_batch_creator"`
+	CreatorID int            `json:"creator_id" gorm:"not null;uniqueIndex:idx_payout_batch_creator;index"`
+	Amount    int64          `json:"amount"`
+	Currency  string         `json:"currency"`
+	Status    string         `json:"status" gorm:"default:'pending';index"`
+	AccountID uint           `json:"account_id"`
+	Account   *PayoutAccount `json:"account,omitempty" gorm:"foreignKey:AccountID"`
+	Reference string         `json:"reference"`
+	CreatedAt time.Time      `json:"created_at"`
+	PaidAt    *time.Time     `json:"paid_at"`
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// SearchQuery is one search made by a user, the user is stored as a one way hash and is empty
+// for searches made without logging in.
+type SearchQuery struct {
+	ID              uint       `json:"id" gorm:"primaryKey"`
+	UserHash        string     `json:"user_hash" gorm:"index"`
+	Query           string     `json:"query"`
+	NormalizedQuery string     `json:"normalized_query" gorm:"index"`
+	Source          string     `json:"source"`
+	ResultCount     int64      `json:"result_count"`
+	ClickedType     string     `json:"clicked_type"`
+	ClickedID       int        `json:"clicked_id"`
+	ClickedAt       *time.Time `json:"clicked_at"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"index"`
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// TaxRegionDefault is the region of the tax rule applied to buyers in regions without a rule of their own.
+const TaxRegionDefault = "*"
+
+// TaxRule is the tax charged to buyers in a region, an ISO 3166 country code or TaxRegionDefault.
+// An inclusive rate is already part of the price, an exclusive one is added on top of it at checkout.
+type TaxRule struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Region    string    `json:"region" gorm:"uniqueIndex;not null"`
+	Name      string    `json:"name" gorm:"not null"` // e.g. GST or VAT, shown on invoices
+	Rate      float64   `json:"rate"`                 // percent
+	Inclusive bool      `json:"inclusive"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// Tip statuses
+const (
+	TipPending  = "pending"
+	TipPaid     = "paid"
+	TipRefunded = "refunded"
+)
+
+// Tip is a one-off payment to a creator outside of their plans, in the platform currency.
+// It can be left on one of the creator's videos, or on the tipper's comment there, which is then highlighted.
+// An anonymous tip does not show who sent it to anyone, the creator included.
+type Tip struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	UserID       int        `json:"user_id" gorm:"not null;index"`
+	CreatorID    int        `json:"creator_id" gorm:"not null;index"`
+	VideoID      *uint      `json:"video_id" gorm:"index"`
+	CommentID    *uint      `json:"comment_id" gorm:"index"`
+	Amount       int64      `json:"amount"` // in the currency's minor unit
+	Refunded     int64      `json:"refunded" gorm:"default:0"`
+	Currency     string     `json:"currency"`
+	Message      string     `json:"message"`
+	Anonymous    bool       `json:"anonymous" gorm:"default:false"`
+	Status       string     `json:"status" gorm:"index;default:'pending'"`
+	RazorOrderID string     `json:"razor_order_id" gorm:"index"`
+	PaymentID    string     `json:"payment_id" gorm:"index"`
+	PaidAt       *time.Time `json:"paid_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
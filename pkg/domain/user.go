@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 // User represents a user in the system.
 
 type User struct {
@@ -12,13 +14,23 @@ type User struct {
 	Permission bool   `gorm:"default:false" json:"permission"`
 	Bio        string `json:"bio"`
 	URL        string `json:"url"`
+	Country    string `json:"country"` // ISO 3166 country code the user is billed in, it picks regional prices and taxes
+	// CreatedAt is unknown for users who signed up before it was recorded
+	CreatedAt *time.Time `json:"created_at"`
 }
 
+// Reports is a user's report of a user, video or comment, TargetUserID is the user or who posted the video or comment.
 type Reports struct {
-	ID         int    `gorm:"primaryKey" json:"id"`
-	ReporterID int    `json:"reporter_id"`
-	TargetID   int    `json:"target_id"`
-	Reason     string `json:"reason"`
+	ID           int    `gorm:"primaryKey" json:"id"`
+	ReporterID   int    `json:"reporter_id"`
+	TargetType   string `json:"target_type" gorm:"default:'user'"`
+	TargetID     int    `json:"target_id"`
+	TargetUserID int    `json:"target_user_id" gorm:"index"`
+	Category     string `json:"category" gorm:"default:'other'"`
+	Reason       string `json:"reason"`
+	CaseID       *uint  `json:"case_id" gorm:"index"`
+	// CreatedAt is unknown for reports made before it was recorded
+	CreatedAt *time.Time `json:"created_at"`
 }
 type UserTags struct {
 	ID     uint `json:"id" gorm:"primaryKey"`
@@ -29,9 +41,10 @@ type UserTags struct {
 
 // Follow struct represents a user following another user
 type Follow struct {
-	ID          uint `json:"id" gorm:"primaryKey"`
-	FollowerID  int  `json:"follower_id"`
-	FollowingID int  `json:"following_id"`
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	FollowerID  int        `json:"follower_id"`
+	FollowingID int        `json:"following_id"`
+	CreatedAt   *time.Time `json:"created_at"` // unknown for follows made before it was recorded
 }
 
 // You can include additional fields if needed, such as timestamps.
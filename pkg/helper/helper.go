@@ -3,17 +3,23 @@ package helper
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	conf "main/pkg/config"
 	"main/pkg/domain"
 	"main/pkg/utils/models"
+	"math"
 	"mime/multipart"
 	"os"
 	"os/exec"
 	"strconv"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -218,6 +224,134 @@ func GetUserID(c *gin.Context) (int, error) {
 	return userID, nil
 }
 
+/*
+GetAdminID returns the adminID the admin middleware stored in the context
+
+Parameters:
+- c: gin context
+
+Returns:
+- uint: adminID
+- error: error is returned
+*/
+func GetAdminID(c *gin.Context) (uint, error) {
+	var key models.UserKey = "adminID"
+	val, ok := c.Request.Context().Value(key).(models.UserKey)
+	if !ok {
+		return 0, errors.New("adminID not found in context")
+	}
+
+	adminID, err := strconv.ParseUint(val.String(), 10, 64)
+	if err != nil {
+		return 0, errors.New("failed to convert adminID to uint")
+	}
+
+	return uint(adminID), nil
+}
+
+/*
+SuspensionMessage tells a suspended user why and until when they are suspended, and how to appeal
+
+Parameters:
+- until: end of the suspension.
+- reason: reason given for the suspension.
+
+Returns:
+- string: message shown to the user.
+*/
+func SuspensionMessage(until time.Time, reason string) string {
+	return fmt.Sprintf("your account is suspended until %s for: %s. You can appeal at /users/suspension/appeal",
+		until.UTC().Format(time.RFC1123), reason)
+}
+
+/*
+BanMessage tells a banned user why they are blocked and how to appeal
+
+Parameters:
+- reason: reason given for the ban.
+
+Returns:
+- string: message shown to the user.
+*/
+func BanMessage(reason string) string {
+	return fmt.Sprintf("your account is blocked for: %s. You can appeal at /users/suspension/appeal", reason)
+}
+
+// videoProbeTimeout stops a probe of a video that does not answer, so it cannot hold up the probes after it
+const videoProbeTimeout = time.Minute
+
+/*
+ProbeVideoDuration reads the length of a video with ffprobe, giving up after videoProbeTimeout
+
+Parameters:
+- source: path or URL of the video.
+
+Returns:
+- int: length of the video in whole seconds, at least 1.
+- error: error is returned
+*/
+func ProbeVideoDuration(source string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), videoProbeTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "ffprobe", "-v", "error", "-show_entries", "format=duration", "-of", "default=noprint_wrappers=1:nokey=1", source)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("error running ffprobe: %v, stderr: %s", err, stderr.String())
+	}
+
+	seconds, err := strconv.ParseFloat(strings.TrimSpace(string(output)), 64)
+	if err != nil || seconds <= 0 {
+		return 0, fmt.Errorf("ffprobe returned no duration for %s", source)
+	}
+
+	return int(math.Max(1, math.Round(seconds))), nil
+}
+
+/*
+AnonymizeUserID turns a user ID into a stable one way hash, so per user statistics can be kept without storing who the user is.
+
+Parameters:
+- userID: ID of the user.
+
+Returns:
+- string: Hex encoded hash of the user ID.
+*/
+func AnonymizeUserID(userID int) string {
+	mac := hmac.New(sha256.New, []byte(viper.GetString("KEY")))
+	mac.Write([]byte(strconv.Itoa(userID)))
+	return hex.EncodeToString(mac.Sum(nil))[:32]
+}
+
+/*
+Slugify turns a name into a lowercase, URL safe slug, e.g. "Battle Royale!" becomes "battle-royale".
+
+Parameters:
+- name: Name to turn into a slug.
+
+Returns:
+- string: The slug, empty if the name has no letters or digits.
+*/
+func Slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return b.String()
+}
+
 /*
 PasswordHashing hashes a password.
 
@@ -0,0 +1,68 @@
+// Package invoice renders subscription invoices as PDF documents.
+package invoice
+
+import (
+	"bytes"
+	"main/pkg/utils/models"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// PDF lays the invoice out on a single A4 page with the core fonts, which cover Latin-1 only,
+// so text outside it is transliterated by the font's code page.
+func PDF(view models.InvoiceView) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle("Invoice "+view.Number, true)
+	pdf.SetAuthor(view.Issuer, true)
+	pdf.SetMargins(20, 20, 20)
+	pdf.AddPage()
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetFont("Helvetica", "B", 20)
+	pdf.CellFormat(0, 10, tr(view.Issuer), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.CellFormat(0, 6, "Invoice "+view.Number, "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 6, "Issued on "+view.IssuedAt, "", 1, "L", false, 0, "")
+	pdf.Ln(6)
+
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.CellFormat(85, 6, "Billed to", "", 0, "L", false, 0, "")
+	pdf.CellFormat(0, 6, "Creator", "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.CellFormat(85, 6, tr(view.BuyerName), "", 0, "L", false, 0, "")
+	pdf.CellFormat(0, 6, tr(view.CreatorName), "", 1, "L", false, 0, "")
+	pdf.CellFormat(85, 6, tr(view.BuyerEmail), "", 1, "L", false, 0, "")
+	pdf.Ln(6)
+
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.CellFormat(120, 8, "Description", "B", 0, "L", false, 0, "")
+	pdf.CellFormat(0, 8, "Amount", "B", 1, "R", false, 0, "")
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.CellFormat(0, 7, tr(view.PlanName+" subscription"), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.CellFormat(0, 5, "Period: "+view.Period, "", 1, "L", false, 0, "")
+	pdf.Ln(2)
+
+	pdf.SetFont("Helvetica", "", 11)
+	for _, line := range view.Lines {
+		pdf.CellFormat(120, 7, tr(line.Label), "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 7, line.Amount, "", 1, "R", false, 0, "")
+	}
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.CellFormat(120, 8, "Total paid", "T", 0, "L", false, 0, "")
+	pdf.CellFormat(0, 8, view.Total, "T", 1, "R", false, 0, "")
+	pdf.Ln(6)
+
+	pdf.SetFont("Helvetica", "", 9)
+	if view.TaxNote != "" {
+		pdf.MultiCell(0, 5, tr(view.TaxNote), "", "L", false)
+	}
+	pdf.MultiCell(0, 5, "Payment reference: "+view.PaymentID, "", "L", false)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,83 @@
+package money
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Money is an amount in the minor unit of its currency, e.g. 49900 INR is 499.00 rupees.
+// Stored in the tables that embed it as <prefix>amount and <prefix>currency.
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// exponents are the digits after the decimal point of the currencies prices can be set in
+var exponents = map[string]int{
+	"INR": 2,
+	"USD": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"AED": 2,
+	"SGD": 2,
+	"AUD": 2,
+	"CAD": 2,
+	"MYR": 2,
+	"BDT": 2,
+	"LKR": 2,
+	"NPR": 2,
+	"JPY": 0,
+	"KWD": 3,
+	"BHD": 3,
+	"OMR": 3,
+}
+
+// Supported tells whether prices can be set and charged in the currency.
+func Supported(currency string) bool {
+	_, ok := exponents[strings.ToUpper(currency)]
+	return ok
+}
+
+// Currencies lists the currencies prices can be set in, in alphabetical order.
+func Currencies() []string {
+	currencies := make([]string, 0, len(exponents))
+	for currency := range exponents {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	return currencies
+}
+
+// Exponent is the number of digits after the decimal point, 2 for currencies that are not listed.
+func Exponent(currency string) int {
+	if exponent, ok := exponents[strings.ToUpper(currency)]; ok {
+		return exponent
+	}
+	return 2
+}
+
+// FromMajor converts an amount in the major unit, rounding to the nearest minor unit.
+func FromMajor(amount float64, currency string) Money {
+	currency = strings.ToUpper(currency)
+	return Money{
+		Amount:   int64(math.Round(amount * math.Pow10(Exponent(currency)))),
+		Currency: currency,
+	}
+}
+
+// Major is the amount in the major unit, e.g. 499.5 for 49950 INR.
+func (m Money) Major() float64 {
+	return float64(m.Amount) / math.Pow10(Exponent(m.Currency))
+}
+
+// String shows the amount in the major unit with its currency, e.g. "499.00 INR".
+func (m Money) String() string {
+	return fmt.Sprintf("%.*f %s", Exponent(m.Currency), m.Major(), m.Currency)
+}
+
+// Percent is the given percentage of the amount, rounded to the nearest minor unit.
+func (m Money) Percent(percent float64) int64 {
+	return int64(math.Round(float64(m.Amount) * percent / 100))
+}
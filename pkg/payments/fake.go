@@ -0,0 +1,159 @@
+package payments
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// fakeSecret and fakeWebhookSecret sign the fake checkouts and webhooks, they are not real credentials
+const (
+	fakeSecret        = "fake_gateway_secret"
+	fakeWebhookSecret = "fake_webhook_secret"
+)
+
+// Simulator is implemented by gateways that can complete a checkout without a real payment.
+type Simulator interface {
+	// SimulatePayment captures a payment for the order and returns it with the checkout signature.
+	SimulatePayment(orderID string) (Payment, string, error)
+	// SignWebhook signs a webhook body the way the gateway would, to deliver simulated webhooks.
+	SignWebhook(body []byte) string
+}
+
+// FakeGateway keeps orders, payments and refunds in memory, so the purchase flow can run offline.
+type FakeGateway struct {
+	mu       sync.Mutex
+	currency string
+	sequence int
+	orders   map[string]Order
+	payments map[string]Payment
+	refunded map[string]int64
+}
+
+func NewFakeGateway(currency string) *FakeGateway {
+	return &FakeGateway{
+		currency: currency,
+		orders:   make(map[string]Order),
+		payments: make(map[string]Payment),
+		refunded: make(map[string]int64),
+	}
+}
+
+func (f *FakeGateway) Name() string {
+	return "fake"
+}
+
+func (f *FakeGateway) KeyID() string {
+	return "fake_key"
+}
+
+func (f *FakeGateway) Currency() string {
+	return f.currency
+}
+
+func (f *FakeGateway) nextID(prefix string) string {
+	f.sequence++
+	return fmt.Sprintf("%s_fake_%06d", prefix, f.sequence)
+}
+
+func (f *FakeGateway) CreateOrder(amount int64, currency string, receipt string) (Order, error) {
+	if amount <= 0 {
+		return Order{}, errors.New("amount must be positive")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	order := Order{
+		ID:       f.nextID("order"),
+		Amount:   amount,
+		Currency: currency,
+		Receipt:  receipt,
+		Status:   "created",
+	}
+	f.orders[order.ID] = order
+
+	return order, nil
+}
+
+func (f *FakeGateway) SimulatePayment(orderID string) (Payment, string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	order, ok := f.orders[orderID]
+	if !ok {
+		return Payment{}, "", errors.New("order not found")
+	}
+	if order.Status == "paid" {
+		return Payment{}, "", errors.New("order is already paid")
+	}
+
+	payment := Payment{
+		ID:       f.nextID("pay"),
+		OrderID:  order.ID,
+		Amount:   order.Amount,
+		Currency: order.Currency,
+		Status:   "captured",
+		Method:   "fake",
+	}
+	f.payments[payment.ID] = payment
+
+	order.Status = "paid"
+	f.orders[order.ID] = order
+
+	return payment, paymentSignature(fakeSecret, order.ID, payment.ID), nil
+}
+
+func (f *FakeGateway) VerifySignature(orderID, paymentID, signature string) bool {
+	return validSignature(fakeSecret, orderID, paymentID, signature)
+}
+
+func (f *FakeGateway) VerifyWebhookSignature(body []byte, signature string) bool {
+	return validBodySignature(fakeWebhookSecret, body, signature)
+}
+
+func (f *FakeGateway) SignWebhook(body []byte) string {
+	return bodySignature(fakeWebhookSecret, body)
+}
+
+func (f *FakeGateway) Refund(paymentID string, amount int64) (Refund, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	payment, ok := f.payments[paymentID]
+	if !ok {
+		return Refund{}, errors.New("payment not found")
+	}
+	if payment.Status != "captured" {
+		return Refund{}, errors.New("only captured payments can be refunded")
+	}
+	if amount <= 0 || f.refunded[paymentID]+amount > payment.Amount {
+		return Refund{}, errors.New("refund amount must be between 1 and the amount left on the payment")
+	}
+
+	f.refunded[paymentID] += amount
+	payment.AmountRefunded = f.refunded[paymentID]
+	if payment.AmountRefunded == payment.Amount {
+		payment.Status = "refunded"
+	}
+	f.payments[paymentID] = payment
+
+	return Refund{
+		ID:        f.nextID("rfnd"),
+		PaymentID: paymentID,
+		Amount:    amount,
+		Status:    "processed",
+	}, nil
+}
+
+func (f *FakeGateway) FetchPayment(paymentID string) (Payment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	payment, ok := f.payments[paymentID]
+	if !ok {
+		return Payment{}, errors.New("payment not found")
+	}
+
+	return payment, nil
+}
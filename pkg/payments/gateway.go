@@ -0,0 +1,149 @@
+package payments
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"main/pkg/config"
+	"strings"
+)
+
+// ErrInvalidSignature is returned when a webhook is not signed by the gateway.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Gateway is a payment provider the subscription flow charges through.
+// Amounts are always in the currency's minor unit, e.g. paise for INR.
+type Gateway interface {
+	// Name identifies the provider, it picks the checkout page rendered to the user.
+	Name() string
+	// KeyID is the public key the checkout page authenticates with.
+	KeyID() string
+	// Currency is the platform's home currency, tiers are priced in it unless the creator picks another.
+	Currency() string
+	CreateOrder(amount int64, currency string, receipt string) (Order, error)
+	// VerifySignature checks the signature the checkout returned for a payment of the order.
+	VerifySignature(orderID, paymentID, signature string) bool
+	// VerifyWebhookSignature checks the signature sent with a webhook against its raw body.
+	VerifyWebhookSignature(body []byte, signature string) bool
+	Refund(paymentID string, amount int64) (Refund, error)
+	FetchPayment(paymentID string) (Payment, error)
+}
+
+type Order struct {
+	ID       string `json:"id"`
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+	Receipt  string `json:"receipt"`
+	Status   string `json:"status"`
+}
+
+type Payment struct {
+	ID       string `json:"id"`
+	OrderID  string `json:"order_id"`
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+	Status   string `json:"status"` // created, authorized, captured, refunded, failed
+	Method   string `json:"method"`
+	// AmountRefunded is the part of the amount refunded so far, a partly refunded payment stays captured
+	AmountRefunded int64 `json:"amount_refunded"`
+}
+
+type Refund struct {
+	ID        string `json:"id"`
+	PaymentID string `json:"payment_id"`
+	Amount    int64  `json:"amount"`
+	Status    string `json:"status"`
+}
+
+// NewGateway returns the gateway selected by PAYMENT_GATEWAY.
+func NewGateway(cfg config.Config) (Gateway, error) {
+	currency := strings.ToUpper(cfg.PaymentCurrency)
+	if currency == "" {
+		currency = "INR"
+	}
+
+	switch strings.ToLower(cfg.PaymentGateway) {
+	case "", "razorpay":
+		if cfg.RazorpayKeyID == "" || cfg.RazorpayKeySecret == "" {
+			return nil, errors.New("RAZORPAY_KEY_ID and RAZORPAY_KEY_SECRET are required for the razorpay gateway")
+		}
+		return NewRazorpayGateway(cfg.RazorpayKeyID, cfg.RazorpayKeySecret, cfg.RazorpayWebhookSecret, currency), nil
+	case "fake":
+		return NewFakeGateway(currency), nil
+	default:
+		return nil, errors.New("unknown payment gateway " + cfg.PaymentGateway)
+	}
+}
+
+// paymentSignature is the hex HMAC-SHA256 of "order_id|payment_id", the scheme Razorpay signs checkouts with
+func paymentSignature(secret, orderID, paymentID string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(orderID + "|" + paymentID))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func validSignature(secret, orderID, paymentID, signature string) bool {
+	expected := paymentSignature(secret, orderID, paymentID)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// bodySignature is the hex HMAC-SHA256 of a webhook body
+func bodySignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func validBodySignature(secret string, body []byte, signature string) bool {
+	if secret == "" {
+		return false
+	}
+	expected := bodySignature(secret, body)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// WebhookEvent is the part of a Razorpay webhook body the subscription flow reads.
+type WebhookEvent struct {
+	Event   string `json:"event"`
+	Payload struct {
+		Payment struct {
+			Entity struct {
+				ID      string `json:"id"`
+				OrderID string `json:"order_id"`
+				Amount  int64  `json:"amount"`
+				Status  string `json:"status"`
+			} `json:"entity"`
+		} `json:"payment"`
+		Refund struct {
+			Entity struct {
+				ID        string `json:"id"`
+				PaymentID string `json:"payment_id"`
+				Amount    int64  `json:"amount"`
+				Status    string `json:"status"`
+			} `json:"entity"`
+		} `json:"refund"`
+		Dispute struct {
+			Entity struct {
+				ID         string `json:"id"`
+				PaymentID  string `json:"payment_id"`
+				Amount     int64  `json:"amount"`
+				ReasonCode string `json:"reason_code"`
+				Status     string `json:"status"`
+			} `json:"entity"`
+		} `json:"dispute"`
+	} `json:"payload"`
+}
+
+func ParseWebhook(body []byte) (WebhookEvent, error) {
+	var event WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return WebhookEvent{}, err
+	}
+	if event.Event == "" {
+		return WebhookEvent{}, errors.New("webhook has no event type")
+	}
+
+	return event, nil
+}
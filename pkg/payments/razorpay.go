@@ -0,0 +1,115 @@
+package payments
+
+import (
+	"errors"
+
+	"github.com/razorpay/razorpay-go"
+)
+
+type razorpayGateway struct {
+	client    *razorpay.Client
+	keyID     string
+	keySecret string
+	// webhookSecret is set per webhook in the Razorpay dashboard, separate from the API key secret
+	webhookSecret string
+	currency      string
+}
+
+func NewRazorpayGateway(keyID, keySecret, webhookSecret, currency string) Gateway {
+	return &razorpayGateway{
+		client:        razorpay.NewClient(keyID, keySecret),
+		keyID:         keyID,
+		keySecret:     keySecret,
+		webhookSecret: webhookSecret,
+		currency:      currency,
+	}
+}
+
+func (r *razorpayGateway) Name() string {
+	return "razorpay"
+}
+
+func (r *razorpayGateway) KeyID() string {
+	return r.keyID
+}
+
+func (r *razorpayGateway) Currency() string {
+	return r.currency
+}
+
+func (r *razorpayGateway) CreateOrder(amount int64, currency string, receipt string) (Order, error) {
+	data := map[string]interface{}{
+		"amount":   amount,
+		"currency": currency,
+		"receipt":  receipt,
+	}
+
+	body, err := r.client.Order.Create(data, nil)
+	if err != nil {
+		return Order{}, err
+	}
+
+	id, ok := body["id"].(string)
+	if !ok || id == "" {
+		return Order{}, errors.New("razorpay did not return an order id")
+	}
+
+	return Order{
+		ID:       id,
+		Amount:   amount,
+		Currency: currency,
+		Receipt:  receipt,
+		Status:   stringField(body, "status"),
+	}, nil
+}
+
+func (r *razorpayGateway) VerifySignature(orderID, paymentID, signature string) bool {
+	return validSignature(r.keySecret, orderID, paymentID, signature)
+}
+
+func (r *razorpayGateway) VerifyWebhookSignature(body []byte, signature string) bool {
+	return validBodySignature(r.webhookSecret, body, signature)
+}
+
+func (r *razorpayGateway) Refund(paymentID string, amount int64) (Refund, error) {
+	body, err := r.client.Payment.Refund(paymentID, int(amount), nil, nil)
+	if err != nil {
+		return Refund{}, err
+	}
+
+	return Refund{
+		ID:        stringField(body, "id"),
+		PaymentID: paymentID,
+		Amount:    int64Field(body, "amount"),
+		Status:    stringField(body, "status"),
+	}, nil
+}
+
+func (r *razorpayGateway) FetchPayment(paymentID string) (Payment, error) {
+	body, err := r.client.Payment.Fetch(paymentID, nil, nil)
+	if err != nil {
+		return Payment{}, err
+	}
+
+	return Payment{
+		ID:       stringField(body, "id"),
+		OrderID:  stringField(body, "order_id"),
+		Amount:   int64Field(body, "amount"),
+		Currency: stringField(body, "currency"),
+		Status:   stringField(body, "status"),
+		Method:   stringField(body, "method"),
+
+		AmountRefunded: int64Field(body, "amount_refunded"),
+	}, nil
+}
+
+func stringField(body map[string]interface{}, key string) string {
+	value, _ := body[key].(string)
+	return value
+}
+
+// int64Field reads a number from a decoded JSON body, where numbers arrive as float64
+func int64Field(body map[string]interface{}, key string) int64 {
+	value, _ := body[key].(float64)
+	return int64(value)
+}
@@ -1,11 +1,9 @@
 package repository
 
 import (
-	"fmt"
 	"main/pkg/domain"
 	interfaces "main/pkg/repository/interface"
 	"main/pkg/utils/models"
-	"strconv"
 
 	"gorm.io/gorm"
 )
@@ -47,34 +45,6 @@ func (ad *adminRepository) GetUsers(page int, limit int) ([]models.UserDetailsAt
 
 }
 
-func (ad *adminRepository) GetUserByID(id string) (domain.User, error) {
-
-	user_id, err := strconv.Atoi(id)
-	if err != nil {
-		return domain.User{}, err
-	}
-
-	query := fmt.Sprintf("select * from users where id = '%d'", user_id)
-	var userDetails domain.User
-
-	if err := ad.DB.Raw(query).Scan(&userDetails).Error; err != nil {
-		return domain.User{}, err
-	}
-
-	return userDetails, nil
-}
-
-// function which will both block and unblock a user
-func (ad *adminRepository) UpdateBlockUserByID(user domain.User) error {
-
-	err := ad.DB.Exec("update users set permission = ? where id = ?", user.Permission, user.ID).Error
-	if err != nil {
-		return err
-	}
-
-	return nil
-
-}
 func (i *adminRepository) GetReports(page, limit int) ([]domain.Reports, error) {
 	var reports []domain.Reports
 	offset := (page - 1) * limit
@@ -85,19 +55,8 @@ func (i *adminRepository) GetReports(page, limit int) ([]domain.Reports, error)
 
 	return reports, nil
 }
-func (ar *adminRepository) AddSubscriptionPlan(name string, duration int, price float64) error {
-	// Build the raw SQL query
-	query := "INSERT INTO subscription_plans (name, duration, price) VALUES (?, ?, ?)"
-
-	// Execute the raw SQL query
-	result := ar.DB.Exec(query, name, duration, price)
-	if result.Error != nil {
-		// Handle any error during the execution, you can log or perform additional actions as needed
-		return result.Error
-	}
-
-	// Return nil if the subscription plan was inserted successfully
-	return nil
+func (ar *adminRepository) AddSubscriptionPlan(plan *domain.SubscriptionPlan) error {
+	return ar.DB.Create(plan).Error
 }
 func (ar *adminRepository) DeleteSubscriptionPlan(planID int) error {
 	// Build the raw SQL query to delete the subscription plan by ID
@@ -131,7 +90,7 @@ func (i *adminRepository) GetUserReports(target_id, page, limit int) ([]domain.R
 	var reports []domain.Reports
 	offset := (page - 1) * limit
 
-	if err := i.DB.Where("target_id  = ?", target_id).Offset(offset).Limit(limit).Find(&reports).Error; err != nil {
+	if err := i.DB.Where("target_user_id = ?", target_id).Offset(offset).Limit(limit).Find(&reports).Error; err != nil {
 		return nil, err
 	}
 
@@ -140,9 +99,51 @@ func (i *adminRepository) GetUserReports(target_id, page, limit int) ([]domain.R
 
 func (i *adminRepository) GetUserReportsCount(target_id int) (int64, error) {
 	var count int64
-	if err := i.DB.Model(&domain.Reports{}).Where("target_id  = ?", target_id).Count(&count).Error; err != nil {
+	if err := i.DB.Model(&domain.Reports{}).Where("target_user_id = ?", target_id).Count(&count).Error; err != nil {
 		return 0, err
 	}
 
 	return count, nil
 }
+
+func (ad *adminRepository) GetAdminByID(adminID uint) (domain.Admin, bool, error) {
+	var admins []domain.Admin
+	if err := ad.DB.Where("id = ?", adminID).Limit(1).Find(&admins).Error; err != nil {
+		return domain.Admin{}, false, err
+	}
+	if len(admins) == 0 {
+		return domain.Admin{}, false, nil
+	}
+
+	return admins[0], true, nil
+}
+
+func (ad *adminRepository) AdminEmailExists(email string) (bool, error) {
+	var count int64
+	err := ad.DB.Model(&domain.Admin{}).Where("lower(email) = lower(?)", email).Count(&count).Error
+
+	return count > 0, err
+}
+
+func (ad *adminRepository) CreateAdmin(admin *domain.Admin) error {
+	return ad.DB.Create(admin).Error
+}
+
+func (ad *adminRepository) ListAdmins() ([]models.AdminAccount, error) {
+	var admins []models.AdminAccount
+	err := ad.DB.Model(&domain.Admin{}).Select("id", "username", "email", "role", "disabled").Order("id").Scan(&admins).Error
+
+	return admins, err
+}
+
+func (ad *adminRepository) UpdateAdmin(adminID uint, values map[string]interface{}) error {
+	return ad.DB.Model(&domain.Admin{}).Where("id = ?", adminID).Updates(values).Error
+}
+
+// CountActiveSuperAdmins counts the super admins that are not disabled, there must always be one left
+func (ad *adminRepository) CountActiveSuperAdmins() (int64, error) {
+	var count int64
+	err := ad.DB.Model(&domain.Admin{}).Where("role = ? AND disabled = false", domain.AdminSuperAdmin).Count(&count).Error
+
+	return count, err
+}
@@ -0,0 +1,310 @@
+package repository
+
+import (
+	"errors"
+	"main/pkg/domain"
+	interfaces "main/pkg/repository/interface"
+	"main/pkg/utils/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type analyticsRepository struct {
+	DB *gorm.DB
+}
+
+func NewAnalyticsRepository(DB *gorm.DB) interfaces.AnalyticsRepository {
+	return &analyticsRepository{DB}
+}
+
+// rollupQueries rebuild the rollup rows of one day from the raw events, @from and @to bound the day.
+// Likes and comments count those still there, one taken back is no longer in the day it was made.
+var rollupQueries = []string{
+	`DELETE FROM creator_daily_stats WHERE day = @day`,
+	`INSERT INTO creator_daily_stats (creator_id, day, views, watch_seconds, likes, comments, new_followers, subscribers_gained, subscribers_lost, updated_at)
+	SELECT creator_id, @day, SUM(views), SUM(watch_seconds), SUM(likes), SUM(comments), SUM(new_followers), SUM(gained), SUM(lost), now()
+	FROM (
+		SELECT creator_id, COUNT(*) AS views, SUM(watched_seconds) AS watch_seconds, 0 AS likes, 0 AS comments, 0 AS new_followers, 0 AS gained, 0 AS lost
+		FROM video_views WHERE created_at >= @from AND created_at < @to GROUP BY creator_id
+		UNION ALL
+		SELECT v.user_id, 0, 0, COUNT(*), 0, 0, 0, 0
+		FROM video_likes l JOIN videos v ON v.id = l.video_id WHERE l.created_at >= @from AND l.created_at < @to GROUP BY v.user_id
+		UNION ALL
+		SELECT v.user_id, 0, 0, 0, COUNT(*), 0, 0, 0
+		FROM comments c JOIN videos v ON v.id = c.video_id WHERE c.created_at >= @from AND c.created_at < @to GROUP BY v.user_id
+		UNION ALL
+		SELECT following_id, 0, 0, 0, 0, COUNT(*), 0, 0
+		FROM follows WHERE created_at >= @from AND created_at < @to GROUP BY following_id
+		UNION ALL
+		SELECT creator_id, 0, 0, 0, 0, 0, COUNT(*), 0
+		FROM subscription_lists WHERE kind IN ('new', 'trial') AND subscribed_at >= @from AND subscribed_at < @to GROUP BY creator_id
+		UNION ALL
+		SELECT creator_id, 0, 0, 0, 0, 0, 0, COUNT(*)
+		FROM subscription_lists
+		WHERE subscribed_at IS NOT NULL AND status IN ('expired', 'cancelled')
+			AND COALESCE(CASE WHEN status = 'cancelled' THEN cancelled_at END, current_period_end) >= @from
+			AND COALESCE(CASE WHEN status = 'cancelled' THEN cancelled_at END, current_period_end) < @to
+		GROUP BY creator_id
+	) s
+	GROUP BY creator_id`,
+	`DELETE FROM creator_daily_revenues WHERE day = @day`,
+	`INSERT INTO creator_daily_revenues (creator_id, day, currency, amount)
+	SELECT e.creator_id, @day, t.currency, SUM(e.credit - e.debit)
+	FROM ledger_entries e
+	JOIN ledger_transactions t ON t.id = e.transaction_id
+	WHERE e.account = 'creator_payable' AND t.type IN ('charge', 'refund') AND t.created_at >= @from AND t.created_at < @to
+	GROUP BY e.creator_id, t.currency`,
+	`DELETE FROM video_daily_stats WHERE day = @day`,
+	`INSERT INTO video_daily_stats (video_id, day, creator_id, views, watch_seconds, likes, comments)
+	SELECT s.video_id, @day, v.user_id, SUM(views), SUM(watch_seconds), SUM(likes), SUM(comments)
+	FROM (
+		SELECT video_id, COUNT(*) AS views, SUM(watched_seconds) AS watch_seconds, 0 AS likes, 0 AS comments
+		FROM video_views WHERE created_at >= @from AND created_at < @to GROUP BY video_id
+		UNION ALL
+		SELECT video_id, 0, 0, COUNT(*), 0 FROM video_likes WHERE created_at >= @from AND created_at < @to GROUP BY video_id
+		UNION ALL
+		SELECT video_id, 0, 0, 0, COUNT(*) FROM comments WHERE created_at >= @from AND created_at < @to GROUP BY video_id
+	) s
+	JOIN videos v ON v.id = s.video_id
+	GROUP BY s.video_id, v.user_id`,
+	`DELETE FROM daily_active_users WHERE day = @day`,
+	`INSERT INTO daily_active_users (day, user_id)
+	SELECT @day, user_id FROM (
+		SELECT user_id FROM video_views WHERE created_at >= @from AND created_at < @to
+		UNION SELECT user_id FROM video_likes WHERE created_at >= @from AND created_at < @to
+		UNION SELECT user_id FROM comments WHERE created_at >= @from AND created_at < @to
+		UNION SELECT follower_id FROM follows WHERE created_at >= @from AND created_at < @to
+		UNION SELECT user_id FROM subscription_lists WHERE subscribed_at >= @from AND subscribed_at < @to
+		UNION SELECT user_id FROM tips WHERE paid_at >= @from AND paid_at < @to
+	) a
+	WHERE user_id > 0`,
+	// the backlog is only counted on the current day, a day rolled up again later keeps the count it had
+	`INSERT INTO platform_daily_stats (day, active_users, weekly_active_users, monthly_active_users, signups, uploads, views, watch_seconds,
+		active_subscriptions, reports, moderation_backlog, updated_at)
+	SELECT @day,
+		(SELECT COUNT(*) FROM daily_active_users WHERE day = @day),
+		(SELECT COUNT(DISTINCT user_id) FROM daily_active_users WHERE day > CAST(@day AS date) - 7 AND day <= @day),
+		(SELECT COUNT(DISTINCT user_id) FROM daily_active_users WHERE day > CAST(@day AS date) - 30 AND day <= @day),
+		(SELECT COUNT(*) FROM users WHERE created_at >= @from AND created_at < @to),
+		(SELECT COUNT(*) FROM videos WHERE created_at >= @from AND created_at < @to),
+		(SELECT COUNT(*) FROM video_views WHERE created_at >= @from AND created_at < @to),
+		(SELECT COALESCE(SUM(watched_seconds), 0) FROM video_views WHERE created_at >= @from AND created_at < @to),
+		(SELECT COUNT(*) FROM (
+			SELECT DISTINCT user_id, creator_id FROM subscription_lists
+			WHERE status <> 'pending' AND subscribed_at IS NOT NULL AND current_period_start < @at
+				AND CASE WHEN status = 'cancelled' AND cancelled_at IS NOT NULL THEN LEAST(cancelled_at, current_period_end) ELSE current_period_end END >= @at
+		) s),
+		(SELECT COUNT(*) FROM reports WHERE created_at >= @from AND created_at < @to),
+		CASE WHEN @current THEN (SELECT COUNT(*) FROM moderation_cases WHERE status IN ('open', 'in_review')) END,
+		now()
+	ON CONFLICT (day) DO UPDATE SET
+		active_users = EXCLUDED.active_users, weekly_active_users = EXCLUDED.weekly_active_users, monthly_active_users = EXCLUDED.monthly_active_users,
+		signups = EXCLUDED.signups, uploads = EXCLUDED.uploads, views = EXCLUDED.views, watch_seconds = EXCLUDED.watch_seconds,
+		active_subscriptions = EXCLUDED.active_subscriptions, reports = EXCLUDED.reports,
+		moderation_backlog = COALESCE(EXCLUDED.moderation_backlog, platform_daily_stats.moderation_backlog), updated_at = EXCLUDED.updated_at`,
+	`DELETE FROM platform_daily_revenues WHERE day = @day`,
+	`INSERT INTO platform_daily_revenues (day, currency, gross, refunded)
+	SELECT @day, t.currency, SUM(CASE WHEN t.type = 'charge' THEN e.debit ELSE 0 END), SUM(CASE WHEN t.type = 'refund' THEN e.credit ELSE 0 END)
+	FROM ledger_entries e
+	JOIN ledger_transactions t ON t.id = e.transaction_id
+	WHERE e.account = 'gateway_clearing' AND t.type IN ('charge', 'refund') AND t.created_at >= @from AND t.created_at < @to
+	GROUP BY t.currency`,
+}
+
+// RollupDay replaces the rollups of the day in one go, so a day rolled up again is never half counted.
+// The counts kept as of the end of the day are taken as of now on the current day.
+func (a *analyticsRepository) RollupDay(day time.Time) error {
+	from := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	to := from.AddDate(0, 0, 1)
+	now := time.Now()
+	at := to
+	if now.Before(to) {
+		at = now
+	}
+	args := map[string]interface{}{
+		"day":     from.Format("2006-01-02"),
+		"from":    from,
+		"to":      to,
+		"at":      at,
+		"current": !now.Before(from) && now.Before(to),
+	}
+
+	return a.DB.Transaction(func(tx *gorm.DB) error {
+		for _, query := range rollupQueries {
+			if err := tx.Exec(query, args).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetCreatorDailyStats returns the creator's days with activity between the dates, both included, oldest first.
+func (a *analyticsRepository) GetCreatorDailyStats(creatorID int, from, to time.Time) ([]domain.CreatorDailyStat, error) {
+	var stats []domain.CreatorDailyStat
+	err := a.DB.Where("creator_id = ? AND day BETWEEN ? AND ?", creatorID, from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Order("day").Find(&stats).Error
+
+	return stats, err
+}
+
+func (a *analyticsRepository) GetCreatorDailyRevenue(creatorID int, from, to time.Time) ([]domain.CreatorDailyRevenue, error) {
+	var revenue []domain.CreatorDailyRevenue
+	err := a.DB.Where("creator_id = ? AND day BETWEEN ? AND ?", creatorID, from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Order("day, currency").Find(&revenue).Error
+
+	return revenue, err
+}
+
+// GetPlatformDailyStats returns the days rolled up between the dates, both included, oldest first.
+func (a *analyticsRepository) GetPlatformDailyStats(from, to time.Time) ([]domain.PlatformDailyStat, error) {
+	var stats []domain.PlatformDailyStat
+	err := a.DB.Where("day BETWEEN ? AND ?", from.Format("2006-01-02"), to.Format("2006-01-02")).Order("day").Find(&stats).Error
+
+	return stats, err
+}
+
+func (a *analyticsRepository) GetPlatformDailyRevenue(from, to time.Time) ([]domain.PlatformDailyRevenue, error) {
+	var revenue []domain.PlatformDailyRevenue
+	err := a.DB.Where("day BETWEEN ? AND ?", from.Format("2006-01-02"), to.Format("2006-01-02")).Order("day, currency").Find(&revenue).Error
+
+	return revenue, err
+}
+
+func (a *analyticsRepository) GetVideoDailyStats(videoID uint, from, to time.Time) ([]domain.VideoDailyStat, error) {
+	var stats []domain.VideoDailyStat
+	err := a.DB.Where("video_id = ? AND day BETWEEN ? AND ?", videoID, from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Order("day").Find(&stats).Error
+
+	return stats, err
+}
+
+// videoAnalyticsSorts maps the sorts the creator can ask for to their columns, anything else is not put in the query.
+var videoAnalyticsSorts = map[string]string{
+	"views":      "views DESC",
+	"watch_time": "watch_seconds DESC",
+	"likes":      "likes DESC",
+	"comments":   "comments DESC",
+	"newest":     "v.created_at DESC",
+}
+
+// ListVideoAnalytics sums the activity on each of the creator's videos between the dates, videos without any included.
+func (a *analyticsRepository) ListVideoAnalytics(creatorID int, from, to time.Time, sort string, page, limit int) ([]models.VideoAnalytics, error) {
+	order, ok := videoAnalyticsSorts[sort]
+	if !ok {
+		order = videoAnalyticsSorts["views"]
+	}
+
+	var videos []models.VideoAnalytics
+	query := `
+		SELECT v.id AS video_id, v.title, v.duration,
+			COALESCE(SUM(s.views), 0) AS views, COALESCE(SUM(s.watch_seconds), 0) AS watch_seconds,
+			COALESCE(SUM(s.likes), 0) AS likes, COALESCE(SUM(s.comments), 0) AS comments
+		FROM videos v
+		LEFT JOIN video_daily_stats s ON s.video_id = v.id AND s.day BETWEEN ? AND ?
+		WHERE v.user_id = ?
+		GROUP BY v.id, v.title, v.duration, v.created_at
+		ORDER BY ` + order + `, v.id DESC
+		LIMIT ? OFFSET ?`
+	err := a.DB.Raw(query, from.Format("2006-01-02"), to.Format("2006-01-02"), creatorID, limit, (page-1)*limit).Scan(&videos).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range videos {
+		if videos[i].Views > 0 {
+			videos[i].AverageViewSeconds = float64(videos[i].WatchSeconds) / float64(videos[i].Views)
+		}
+	}
+
+	return videos, nil
+}
+
+func (a *analyticsRepository) GetVideo(videoID uint) (domain.Video, bool, error) {
+	var video domain.Video
+	if err := a.DB.First(&video, videoID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Video{}, false, nil
+		}
+		return domain.Video{}, false, err
+	}
+
+	return video, true, nil
+}
+
+// GetRetention counts the views opened between the times that reached each step percent of the video, from its start to its end.
+func (a *analyticsRepository) GetRetention(videoID uint, duration int, from, to time.Time, step int) ([]models.RetentionPoint, error) {
+	var points []models.RetentionPoint
+	query := `
+		SELECT p.percent, p.percent * ? / 100 AS second,
+			COUNT(vv.id) FILTER (WHERE vv.watched_seconds * 100 >= p.percent * ?) AS views
+		FROM generate_series(0, 100, ?) AS p(percent)
+		LEFT JOIN video_views vv ON vv.video_id = ? AND vv.created_at >= ? AND vv.created_at < ?
+		GROUP BY p.percent
+		ORDER BY p.percent`
+	err := a.DB.Raw(query, duration, duration, step, videoID, from, to).Scan(&points).Error
+
+	return points, err
+}
+
+func (a *analyticsRepository) GetReportSettings(creatorID int) (domain.AnalyticsReportSetting, bool, error) {
+	var setting domain.AnalyticsReportSetting
+	if err := a.DB.Where("creator_id = ?", creatorID).First(&setting).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.AnalyticsReportSetting{}, false, nil
+		}
+		return domain.AnalyticsReportSetting{}, false, err
+	}
+
+	return setting, true, nil
+}
+
+func (a *analyticsRepository) SaveReportSettings(setting domain.AnalyticsReportSetting) error {
+	return a.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "creator_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"weekly", "format", "updated_at"}),
+	}).Create(&setting).Error
+}
+
+// ListWeeklyReportsDue returns the creators who want weekly reports and have none yet for the week starting on the day.
+func (a *analyticsRepository) ListWeeklyReportsDue(periodStart time.Time) ([]domain.AnalyticsReportSetting, error) {
+	var settings []domain.AnalyticsReportSetting
+	err := a.DB.Where("weekly = true").
+		Where("NOT EXISTS (SELECT 1 FROM analytics_reports r WHERE r.creator_id = analytics_report_settings.creator_id AND r.period_start = ?)", periodStart.Format("2006-01-02")).
+		Order("creator_id").Find(&settings).Error
+
+	return settings, err
+}
+
+// CreateReport stores the report unless the creator already has one for the period, it reports whether it was stored.
+func (a *analyticsRepository) CreateReport(report *domain.AnalyticsReport) (bool, error) {
+	result := a.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(report)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// ListReports returns the creator's reports newest first, without their files.
+func (a *analyticsRepository) ListReports(creatorID int, page, limit int) ([]domain.AnalyticsReport, error) {
+	var reports []domain.AnalyticsReport
+	err := a.DB.Omit("content").Where("creator_id = ?", creatorID).
+		Order("period_start DESC").Offset((page - 1) * limit).Limit(limit).Find(&reports).Error
+
+	return reports, err
+}
+
+func (a *analyticsRepository) GetReport(reportID uint) (domain.AnalyticsReport, error) {
+	var report domain.AnalyticsReport
+	if err := a.DB.First(&report, reportID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.AnalyticsReport{}, errors.New("report not found")
+		}
+		return domain.AnalyticsReport{}, err
+	}
+
+	return report, nil
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"main/pkg/domain"
+	interfaces "main/pkg/repository/interface"
+	"main/pkg/utils/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// auditTables are where the targets of audited actions are stored and the column they are found by
+var auditTables = map[string]struct{ table, column string }{
+	"admin":              {"admins", "id"},
+	"user":               {"users", "id"},
+	"category":           {"categories", "id"},
+	"subscription":       {"subscription_lists", "id"},
+	"payment_dispute":    {"payment_disputes", "id"},
+	"payout":             {"payouts", "id"},
+	"plan":               {"subscription_plans", "id"},
+	"tag":                {"tags", "id"},
+	"tag_alias":          {"tag_aliases", "id"},
+	"banned_tag":         {"banned_tags", "id"},
+	"coupon":             {"coupons", "id"},
+	"payment_event":      {"payment_events", "id"},
+	"payout_batch":       {"payout_batches", "id"},
+	"tax_rule":           {"tax_rules", "region"},
+	"moderation_case":    {"moderation_cases", "id"},
+	"enforcement_action": {"enforcement_actions", "id"},
+	"appeal":             {"appeals", "id"},
+}
+
+type auditRepository struct {
+	DB *gorm.DB
+}
+
+func NewAuditRepository(DB *gorm.DB) interfaces.AuditRepository {
+	return &auditRepository{DB}
+}
+
+// Snapshot returns the target as JSON without its password, or nil when it does not exist.
+// The id is matched ignoring case, as codes such as tax regions are stored uppercase whatever the admin typed.
+// The settings are taken as a whole since they have no single row.
+func (a *auditRepository) Snapshot(targetType, targetID string) (*string, error) {
+	var snapshots []*string
+	if targetType == "settings" {
+		if err := a.DB.Raw("SELECT jsonb_object_agg(key, value) FROM settings").Scan(&snapshots).Error; err != nil {
+			return nil, err
+		}
+	} else {
+		target, ok := auditTables[targetType]
+		if !ok || targetID == "" {
+			return nil, nil
+		}
+		query := "SELECT to_jsonb(t) - 'password' FROM " + target.table + " t WHERE lower(" + target.column + "::text) = lower(?)"
+		if err := a.DB.Raw(query, targetID).Scan(&snapshots).Error; err != nil {
+			return nil, err
+		}
+	}
+	if len(snapshots) == 0 {
+		return nil, nil
+	}
+
+	return snapshots[0], nil
+}
+
+func (a *auditRepository) CreateLog(log *domain.AuditLog) error {
+	return a.DB.Create(log).Error
+}
+
+// ListLogs returns the logs matching the filter between from and to, the latest first.
+func (a *auditRepository) ListLogs(filter models.AuditFilter, from, to time.Time, page, limit int) ([]domain.AuditLog, error) {
+	query := a.DB.Model(&domain.AuditLog{}).Where("created_at BETWEEN ? AND ?", from, to)
+	if filter.AdminID != 0 {
+		query = query.Where("admin_id = ?", filter.AdminID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+
+	var logs []domain.AuditLog
+	err := query.Order("created_at DESC, id DESC").Offset((page - 1) * limit).Limit(limit).Find(&logs).Error
+
+	return logs, err
+}
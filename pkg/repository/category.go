@@ -8,6 +8,7 @@ import (
 	"strconv"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type categoryRepository struct {
@@ -18,31 +19,12 @@ func NewCategoryRepository(DB *gorm.DB) interfaces.CategoryRepository {
 	return &categoryRepository{DB}
 }
 
-func (p *categoryRepository) AddCategory(cat string) (domain.Category, error) {
-
-	var b string
-	err := p.DB.Raw("INSERT INTO categories (category) VALUES (?) RETURNING category", cat).Scan(&b).Error
-	if err != nil {
-		return domain.Category{}, err
-	}
-
-	var categoryResponse domain.Category
-	err = p.DB.Raw(`
-	SELECT
-		p.id,
-		p.category
-		FROM
-			categories p
-		WHERE
-			p.category = ?
-			`, b).Scan(&categoryResponse).Error
-
-	if err != nil {
+func (p *categoryRepository) AddCategory(category domain.Category) (domain.Category, error) {
+	if err := p.DB.Create(&category).Error; err != nil {
 		return domain.Category{}, err
 	}
 
-	return categoryResponse, nil
-
+	return category, nil
 }
 
 func (p *categoryRepository) CheckCategory(current string) (bool, error) {
@@ -59,40 +41,93 @@ func (p *categoryRepository) CheckCategory(current string) (bool, error) {
 	return true, err
 }
 
-func (p *categoryRepository) UpdateCategory(current, new string) (domain.Category, error) {
-
-	// Check the database connection
-	if p.DB == nil {
-		return domain.Category{}, errors.New("database connection is nil")
+func (p *categoryRepository) GetCategoryByID(categoryID uint) (domain.Category, error) {
+	var category domain.Category
+	if err := p.DB.First(&category, categoryID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Category{}, errors.New("category not found")
+		}
+		return domain.Category{}, err
 	}
 
-	// Update the category
-	if err := p.DB.Exec("UPDATE categories SET category = ? WHERE category = ?", new, current).Error; err != nil {
+	return category, nil
+}
+
+func (p *categoryRepository) GetCategoryBySlug(slug string) (domain.Category, error) {
+	var category domain.Category
+	if err := p.DB.Where("slug = ?", slug).First(&category).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Category{}, errors.New("category not found")
+		}
 		return domain.Category{}, err
 	}
 
-	// Retrieve the updated category
-	var newcat domain.Category
-	if err := p.DB.First(&newcat, "category = ?", new).Error; err != nil {
-		return domain.Category{}, err
+	return category, nil
+}
+
+func (p *categoryRepository) SlugExists(slug string, excludeID uint) (bool, error) {
+	var count int64
+	if err := p.DB.Raw("SELECT COUNT(*) FROM categories WHERE slug = ? AND id <> ?", slug, excludeID).Scan(&count).Error; err != nil {
+		return false, err
 	}
 
-	return newcat, nil
+	return count > 0, nil
 }
 
-func (c *categoryRepository) DeleteCategory(categoryID string) error {
-	id, err := strconv.Atoi(categoryID)
+func (p *categoryRepository) UpdateCategory(category domain.Category) (domain.Category, error) {
+
+	// Check the database connection
+	if p.DB == nil {
+		return domain.Category{}, errors.New("database connection is nil")
+	}
+
+	err := p.DB.Model(&domain.Category{}).Where("id = ?", category.ID).Updates(map[string]interface{}{
+		"category":    category.Category,
+		"slug":        category.Slug,
+		"parent_id":   category.ParentID,
+		"description": category.Description,
+		"icon_url":    category.IconURL,
+		"sort_order":  category.SortOrder,
+	}).Error
 	if err != nil {
-		return errors.New("converting into integer not happened")
+		return domain.Category{}, err
 	}
 
-	result := c.DB.Exec("DELETE FROM categories WHERE id = ?", id)
+	return p.GetCategoryByID(category.ID)
+}
 
-	if result.RowsAffected < 1 {
-		return errors.New("no records with that ID exist")
+func (c *categoryRepository) CountCategoryVideos(categoryID uint) (int64, error) {
+	var count int64
+	if err := c.DB.Raw("SELECT COUNT(*) FROM videos WHERE category_id = ?", categoryID).Scan(&count).Error; err != nil {
+		return 0, err
 	}
 
-	return nil
+	return count, nil
+}
+
+// DeleteCategory moves the videos of the category to moveTo, hands its children to its own parent and then deletes it.
+func (c *categoryRepository) DeleteCategory(categoryID, moveTo uint) error {
+	return c.DB.Transaction(func(tx *gorm.DB) error {
+		var category domain.Category
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&category, categoryID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("no records with that ID exist")
+			}
+			return err
+		}
+
+		if moveTo != 0 {
+			if err := tx.Exec("UPDATE videos SET category_id = ? WHERE category_id = ?", moveTo, categoryID).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Exec("UPDATE categories SET parent_id = ? WHERE parent_id = ?", category.ParentID, categoryID).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec("DELETE FROM categories WHERE id = ?", categoryID).Error
+	})
 }
 
 func (c *categoryRepository) GetCategories(page, limit int) ([]domain.Category, error) {
@@ -105,13 +140,45 @@ func (c *categoryRepository) GetCategories(page, limit int) ([]domain.Category,
 	offset := (page - 1) * limit
 	var categories []domain.Category
 
-	if err := c.DB.Raw("select id,category from categories limit ? offset ?", limit, offset).Scan(&categories).Error; err != nil {
+	if err := c.DB.Raw("SELECT * FROM categories ORDER BY sort_order, category LIMIT ? OFFSET ?", limit, offset).Scan(&categories).Error; err != nil {
 		return []domain.Category{}, err
 	}
 
 	return categories, nil
 }
 
+// GetAllCategories returns every category with the number of videos directly in it, in display order.
+func (c *categoryRepository) GetAllCategories() ([]models.CategoryTree, error) {
+	var categories []models.CategoryTree
+
+	query := `
+		SELECT c.id, c.category, c.slug, c.parent_id, c.description, c.icon_url, c.sort_order,
+			(SELECT COUNT(*) FROM videos v WHERE v.category_id = c.id AND v.taken_down_at IS NULL) AS videos
+		FROM categories c
+		ORDER BY c.sort_order, c.category`
+
+	if err := c.DB.Raw(query).Scan(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (c *categoryRepository) ReorderCategories(orders []models.CategoryOrder) error {
+	return c.DB.Transaction(func(tx *gorm.DB) error {
+		for _, order := range orders {
+			result := tx.Exec("UPDATE categories SET sort_order = ? WHERE id = ?", order.SortOrder, order.ID)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected < 1 {
+				return errors.New("category " + strconv.Itoa(int(order.ID)) + " does not exist")
+			}
+		}
+		return nil
+	})
+}
+
 // func (vr *categoryRepository) ListVideosByCategory(categoryID, page, limit int) (models.VideoResponses, error) {
 // 	// Validate categoryID
 // 	if categoryID <= 0 {
@@ -158,8 +225,20 @@ func (vr *categoryRepository) ListVideosByCategory(categoryID, page, limit int)
 	var videos []models.VideoResponses
 
 	// Fetch videos from the database based on category ID, page, and limit
-	if err := vr.DB.Raw("SELECT id, user_id, title, description, url, category_id FROM videos WHERE category_id = ? OFFSET ? LIMIT ?", categoryID, offset, limit).
-		Scan(&videos).Error; err != nil {
+	// Videos of the subcategories are listed together with the category's own
+	query := `
+		WITH RECURSIVE tree AS (
+			SELECT id FROM categories WHERE id = ?
+			UNION ALL
+			SELECT c.id FROM categories c JOIN tree t ON c.parent_id = t.id
+		)
+		SELECT id, user_id, title, description, url, category_id
+		FROM videos
+		WHERE category_id IN (SELECT id FROM tree) AND taken_down_at IS NULL
+		ORDER BY id DESC
+		OFFSET ? LIMIT ?`
+
+	if err := vr.DB.Raw(query, categoryID, offset, limit).Scan(&videos).Error; err != nil {
 		return nil, err
 	}
 
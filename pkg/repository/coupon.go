@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"errors"
+	"main/pkg/domain"
+	interfaces "main/pkg/repository/interface"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type couponRepository struct {
+	DB *gorm.DB
+}
+
+func NewCouponRepository(DB *gorm.DB) interfaces.CouponRepository {
+	return &couponRepository{DB}
+}
+
+func (c *couponRepository) CreateCoupon(coupon *domain.Coupon) error {
+	var count int64
+	if err := c.DB.Model(&domain.Coupon{}).Where("code = ?", coupon.Code).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("a coupon with that code already exists")
+	}
+
+	return c.DB.Create(coupon).Error
+}
+
+func (c *couponRepository) GetCoupon(couponID uint) (domain.Coupon, error) {
+	var coupon domain.Coupon
+	if err := c.DB.First(&coupon, couponID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Coupon{}, errors.New("coupon not found")
+		}
+		return domain.Coupon{}, err
+	}
+
+	return coupon, nil
+}
+
+func (c *couponRepository) GetCouponByCode(code string) (domain.Coupon, error) {
+	var coupon domain.Coupon
+	if err := c.DB.Where("code = ?", strings.ToUpper(strings.TrimSpace(code))).First(&coupon).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Coupon{}, errors.New("coupon not found")
+		}
+		return domain.Coupon{}, err
+	}
+
+	return coupon, nil
+}
+
+// ListCoupons returns the newest coupons first, only the creator's when creatorID is set.
+func (c *couponRepository) ListCoupons(creatorID *int, page, limit int) ([]domain.Coupon, error) {
+	var coupons []domain.Coupon
+
+	query := c.DB.Order("id DESC").Offset((page - 1) * limit).Limit(limit)
+	if creatorID != nil {
+		query = query.Where("creator_id = ?", *creatorID)
+	}
+
+	if err := query.Find(&coupons).Error; err != nil {
+		return nil, err
+	}
+
+	return coupons, nil
+}
+
+// DeactivateCoupon stops the coupon from being applied, redemptions already made stay recorded.
+func (c *couponRepository) DeactivateCoupon(couponID uint) error {
+	return c.DB.Model(&domain.Coupon{}).Where("id = ?", couponID).Update("active", false).Error
+}
+
+func (c *couponRepository) HasRedeemed(couponID uint, userID int) (bool, error) {
+	var count int64
+	if err := c.DB.Model(&domain.CouponRedemption{}).Where("coupon_id = ? AND user_id = ?", couponID, userID).Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
@@ -0,0 +1,311 @@
+package repository
+
+import (
+	"errors"
+	"main/pkg/domain"
+	interfaces "main/pkg/repository/interface"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// contentTables are the tables of the content that can be reported and taken down
+var contentTables = map[string]string{
+	domain.ReportTargetVideo:   "videos",
+	domain.ReportTargetComment: "comments",
+}
+
+type enforcementRepository struct {
+	DB *gorm.DB
+}
+
+func NewEnforcementRepository(DB *gorm.DB) interfaces.EnforcementRepository {
+	return &enforcementRepository{DB}
+}
+
+func (e *enforcementRepository) UserExists(userID int) (bool, error) {
+	var count int64
+	err := e.DB.Model(&domain.User{}).Where("id = ?", userID).Count(&count).Error
+
+	return count > 0, err
+}
+
+func (e *enforcementRepository) FindContentOwner(targetType string, targetID int) (int, bool, error) {
+	if _, ok := contentTables[targetType]; !ok {
+		return 0, false, errors.New("only videos and comments can be taken down")
+	}
+
+	return findTargetUser(e.DB, targetType, targetID)
+}
+
+// findTargetUser returns the user itself, or who posted the video or comment.
+func findTargetUser(db *gorm.DB, targetType string, targetID int) (int, bool, error) {
+	var query string
+	switch targetType {
+	case domain.ReportTargetUser:
+		query = "SELECT id FROM users WHERE id = ?"
+	case domain.ReportTargetVideo:
+		query = "SELECT user_id FROM videos WHERE id = ?"
+	case domain.ReportTargetComment:
+		query = "SELECT user_id FROM comments WHERE id = ?"
+	default:
+		return 0, false, errors.New("unknown report target")
+	}
+
+	var userIDs []int
+	if err := db.Raw(query, targetID).Scan(&userIDs).Error; err != nil {
+		return 0, false, err
+	}
+	if len(userIDs) == 0 {
+		return 0, false, nil
+	}
+
+	return userIDs[0], true, nil
+}
+
+func (e *enforcementRepository) CreateAction(action *domain.EnforcementAction) error {
+	return e.DB.Create(action).Error
+}
+
+func (e *enforcementRepository) TakeDown(action *domain.EnforcementAction) error {
+	return e.DB.Transaction(func(tx *gorm.DB) error {
+		return takeDown(tx, action)
+	})
+}
+
+// takeDown records the takedown and hides the video or comment. Nothing is deleted, so its likes, tags, tiers and
+// comments are still there when a revoke shows it again.
+func takeDown(tx *gorm.DB, action *domain.EnforcementAction) error {
+	table, ok := contentTables[action.TargetType]
+	if !ok {
+		return errors.New("only videos and comments can be taken down")
+	}
+
+	if err := tx.Create(action).Error; err != nil {
+		return err
+	}
+
+	result := tx.Exec("UPDATE "+table+" SET taken_down_at = ? WHERE id = ? AND taken_down_at IS NULL", action.CreatedAt, action.TargetID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New(action.TargetType + " not found")
+	}
+
+	return nil
+}
+
+func (e *enforcementRepository) Ban(action *domain.EnforcementAction) error {
+	return e.DB.Transaction(func(tx *gorm.DB) error {
+		return ban(tx, action)
+	})
+}
+
+// ban records the ban and blocks the user, revoking it unblocks them.
+func ban(tx *gorm.DB, action *domain.EnforcementAction) error {
+	if err := tx.Create(action).Error; err != nil {
+		return err
+	}
+
+	return tx.Exec("UPDATE users SET permission = false WHERE id = ?", action.UserID).Error
+}
+
+// CountActiveStrikes counts the strikes against the user that have not expired or been revoked.
+func (e *enforcementRepository) CountActiveStrikes(userID int) (int64, error) {
+	var count int64
+	err := e.DB.Model(&domain.EnforcementAction{}).
+		Where("user_id = ? AND type = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", userID, domain.EnforcementStrike, time.Now()).
+		Count(&count).Error
+
+	return count, err
+}
+
+func (e *enforcementRepository) GetAction(actionID uint) (domain.EnforcementAction, bool, error) {
+	var action domain.EnforcementAction
+	err := e.DB.First(&action, actionID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.EnforcementAction{}, false, nil
+		}
+		return domain.EnforcementAction{}, false, err
+	}
+
+	return action, true, nil
+}
+
+// ListActions returns the actions against the user, the latest first.
+func (e *enforcementRepository) ListActions(userID int, page, limit int) ([]domain.EnforcementAction, error) {
+	var actions []domain.EnforcementAction
+	err := e.DB.Where("user_id = ?", userID).Order("created_at DESC, id DESC").Offset((page - 1) * limit).Limit(limit).Find(&actions).Error
+
+	return actions, err
+}
+
+// GetActiveSuspension returns the suspension of the user that ends last, if any is in force.
+func (e *enforcementRepository) GetActiveSuspension(userID int) (domain.EnforcementAction, bool, error) {
+	var actions []domain.EnforcementAction
+	err := e.DB.Where("user_id = ? AND type = ? AND revoked_at IS NULL AND expires_at > ?", userID, domain.EnforcementSuspension, time.Now()).
+		Order("expires_at DESC").Limit(1).Find(&actions).Error
+	if err != nil {
+		return domain.EnforcementAction{}, false, err
+	}
+	if len(actions) == 0 {
+		return domain.EnforcementAction{}, false, nil
+	}
+
+	return actions[0], true, nil
+}
+
+// GetActiveBan returns the ban of the user that was not revoked, if any.
+func (e *enforcementRepository) GetActiveBan(userID int) (domain.EnforcementAction, bool, error) {
+	var actions []domain.EnforcementAction
+	err := e.DB.Where("user_id = ? AND type = ? AND revoked_at IS NULL", userID, domain.EnforcementBan).
+		Order("created_at DESC, id DESC").Limit(1).Find(&actions).Error
+	if err != nil {
+		return domain.EnforcementAction{}, false, err
+	}
+	if len(actions) == 0 {
+		return domain.EnforcementAction{}, false, nil
+	}
+
+	return actions[0], true, nil
+}
+
+// RevokeAction lifts an action that was not revoked yet and undoes it. It reports whether the action was still in place.
+func (e *enforcementRepository) RevokeAction(actionID, adminID uint, reason string) (domain.EnforcementAction, bool, error) {
+	var action domain.EnforcementAction
+	revoked := false
+	err := e.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		action, revoked, err = revokeAction(tx, actionID, adminID, reason)
+		return err
+	})
+
+	return action, revoked, err
+}
+
+func revokeAction(tx *gorm.DB, actionID, adminID uint, reason string) (domain.EnforcementAction, bool, error) {
+	var action domain.EnforcementAction
+	result := tx.Raw(`
+		UPDATE enforcement_actions SET revoked_at = ?, revoked_by = ?, revoke_reason = ?
+		WHERE id = ? AND revoked_at IS NULL
+		RETURNING *`, time.Now(), adminID, reason, actionID).Scan(&action)
+	if result.Error != nil {
+		return domain.EnforcementAction{}, false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.EnforcementAction{}, false, nil
+	}
+
+	switch action.Type {
+	case domain.EnforcementTakedown:
+		table := contentTables[action.TargetType]
+		if err := tx.Exec("UPDATE "+table+" SET taken_down_at = NULL WHERE id = ?", action.TargetID).Error; err != nil {
+			return domain.EnforcementAction{}, false, err
+		}
+	case domain.EnforcementBan:
+		// a user banned again in another case stays blocked until every ban is revoked
+		err := tx.Exec("UPDATE users SET permission = true WHERE id = ? AND NOT EXISTS (SELECT 1 FROM enforcement_actions WHERE user_id = ? AND type = ? AND revoked_at IS NULL)",
+			action.UserID, action.UserID, domain.EnforcementBan).Error
+		if err != nil {
+			return domain.EnforcementAction{}, false, err
+		}
+	}
+
+	return action, true, nil
+}
+
+// FindUserCredentials returns the id and password hash of the user with the email.
+func (e *enforcementRepository) FindUserCredentials(email string) (int, string, bool, error) {
+	var user domain.User
+	err := e.DB.Select("id", "password").Where("email = ?", email).First(&user).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, "", false, nil
+		}
+		return 0, "", false, err
+	}
+
+	return user.ID, user.Password, true, nil
+}
+
+// CreateAppeal stores the appeal, it reports false when the action was already appealed.
+func (e *enforcementRepository) CreateAppeal(appeal *domain.Appeal) (bool, error) {
+	result := e.DB.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "action_id"}}, DoNothing: true}).Create(appeal)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (e *enforcementRepository) GetAppeal(appealID uint) (domain.Appeal, bool, error) {
+	var appeal domain.Appeal
+	err := e.DB.First(&appeal, appealID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Appeal{}, false, nil
+		}
+		return domain.Appeal{}, false, err
+	}
+
+	return appeal, true, nil
+}
+
+func (e *enforcementRepository) ListUserAppeals(userID int) ([]domain.Appeal, error) {
+	var appeals []domain.Appeal
+	err := e.DB.Where("user_id = ?", userID).Order("created_at DESC, id DESC").Find(&appeals).Error
+
+	return appeals, err
+}
+
+// ListAppeals returns the appeals with the status, all of them when it is empty, the oldest first so they are answered in order.
+func (e *enforcementRepository) ListAppeals(status string, page, limit int) ([]domain.Appeal, error) {
+	query := e.DB.Model(&domain.Appeal{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var appeals []domain.Appeal
+	err := query.Order("created_at, id").Offset((page - 1) * limit).Limit(limit).Find(&appeals).Error
+
+	return appeals, err
+}
+
+// DecideAppeal answers a pending appeal, overturning it revokes the action in the same transaction.
+// It reports whether the appeal was still pending.
+func (e *enforcementRepository) DecideAppeal(appealID, adminID uint, overturn bool, response string) (domain.Appeal, bool, error) {
+	status := domain.AppealUpheld
+	if overturn {
+		status = domain.AppealOverturned
+	}
+
+	var appeal domain.Appeal
+	decided := false
+	err := e.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Raw(`
+			UPDATE appeals SET status = ?, response = ?, decided_by = ?, decided_at = ?
+			WHERE id = ? AND status = 'pending'
+			RETURNING *`, status, response, adminID, time.Now(), appealID).Scan(&appeal)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		decided = true
+
+		if overturn {
+			_, _, err := revokeAction(tx, appeal.ActionID, adminID, "appeal overturned: "+response)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.Appeal{}, false, err
+	}
+
+	return appeal, decided, nil
+}
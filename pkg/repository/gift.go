@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"errors"
+	"main/pkg/domain"
+	interfaces "main/pkg/repository/interface"
+	"main/pkg/utils/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type giftRepository struct {
+	DB *gorm.DB
+}
+
+func NewGiftRepository(DB *gorm.DB) interfaces.GiftRepository {
+	return &giftRepository{DB}
+}
+
+func (g *giftRepository) CreateGiftPurchase(purchase *domain.GiftPurchase) error {
+	return g.DB.Create(purchase).Error
+}
+
+// SetGiftOrder keeps the gateway order of the purchase so the checkout and webhooks can be matched back to it.
+func (g *giftRepository) SetGiftOrder(purchaseID uint, razorOrderID string) error {
+	return g.DB.Model(&domain.GiftPurchase{}).Where("id = ?", purchaseID).Update("razor_order_id", razorOrderID).Error
+}
+
+func (g *giftRepository) GetGiftPurchase(purchaseID uint) (domain.GiftPurchase, error) {
+	var purchase domain.GiftPurchase
+	if err := g.DB.First(&purchase, purchaseID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.GiftPurchase{}, errors.New("gift purchase not found")
+		}
+		return domain.GiftPurchase{}, err
+	}
+
+	return purchase, nil
+}
+
+func (g *giftRepository) FindGiftPurchaseByRazorOrderID(razorOrderID string) (domain.GiftPurchase, bool, error) {
+	return g.findGiftPurchase("razor_order_id = ?", razorOrderID)
+}
+
+func (g *giftRepository) FindGiftPurchaseByPaymentID(paymentID string) (domain.GiftPurchase, bool, error) {
+	return g.findGiftPurchase("payment_id = ?", paymentID)
+}
+
+func (g *giftRepository) findGiftPurchase(query string, value string) (domain.GiftPurchase, bool, error) {
+	if value == "" {
+		return domain.GiftPurchase{}, false, nil
+	}
+
+	var purchases []domain.GiftPurchase
+	if err := g.DB.Where(query, value).Limit(1).Find(&purchases).Error; err != nil {
+		return domain.GiftPurchase{}, false, err
+	}
+	if len(purchases) == 0 {
+		return domain.GiftPurchase{}, false, nil
+	}
+
+	return purchases[0], true, nil
+}
+
+// FulfilGiftPurchase marks the purchase paid and stores its codes in one transaction,
+// fulfilled is false when the purchase was already paid, e.g. by the webhook racing the checkout.
+func (g *giftRepository) FulfilGiftPurchase(purchaseID uint, paymentID string, codes []domain.GiftCode) (bool, error) {
+	fulfilled := false
+
+	err := g.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec("UPDATE gift_purchases SET status = 'paid', payment_id = ?, paid_at = ? WHERE id = ? AND status = 'pending'",
+			paymentID, time.Now(), purchaseID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		fulfilled = true
+
+		return tx.Create(&codes).Error
+	})
+
+	return fulfilled, err
+}
+
+// ListGiftPurchases returns the buyer's purchases, newest first.
+func (g *giftRepository) ListGiftPurchases(buyerID int, page, limit int) ([]domain.GiftPurchase, error) {
+	var purchases []domain.GiftPurchase
+	err := g.DB.Where("buyer_id = ?", buyerID).Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&purchases).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return purchases, nil
+}
+
+func (g *giftRepository) ListGiftCodes(purchaseID uint) ([]domain.GiftCode, error) {
+	var codes []domain.GiftCode
+	if err := g.DB.Where("purchase_id = ?", purchaseID).Order("id").Find(&codes).Error; err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func (g *giftRepository) GetGiftCode(code string) (domain.GiftCode, error) {
+	var giftCode domain.GiftCode
+	if err := g.DB.Where("code = ?", strings.ToUpper(strings.TrimSpace(code))).First(&giftCode).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.GiftCode{}, errors.New("gift code not found")
+		}
+		return domain.GiftCode{}, err
+	}
+
+	return giftCode, nil
+}
+
+// RedeemGiftCode claims the code and starts the subscription it pays for in one transaction, the subscription it renews
+// or replaces is closed alongside. redeemed is false when the code was redeemed meanwhile or has expired.
+func (g *giftRepository) RedeemGiftCode(codeID uint, subscription *domain.SubscriptionList, activation models.SubscriptionActivation) (bool, error) {
+	redeemed := false
+
+	err := g.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Exec("UPDATE gift_codes SET redeemed_by = ?, redeemed_at = ? WHERE id = ? AND redeemed_by IS NULL AND expires_at > ?",
+			subscription.UserID, now, codeID, now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		redeemed = true
+
+		subscription.SubscribedAt = &now
+		subscription.PaymentStatus = "GIFT"
+		subscription.IsActive = true
+		subscription.Status = domain.SubscriptionActive
+		subscription.Kind = activation.Kind
+		subscription.PreviousSubscriptionID = activation.PreviousSubscriptionID
+		subscription.CurrentPeriodStart = &activation.PeriodStart
+		subscription.CurrentPeriodEnd = &activation.PeriodEnd
+		if err := tx.Create(subscription).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec("UPDATE gift_codes SET subscription_list_id = ? WHERE id = ?", subscription.ID, codeID).Error; err != nil {
+			return err
+		}
+
+		if activation.PreviousSubscriptionID == nil {
+			return nil
+		}
+
+		return tx.Exec("UPDATE subscription_lists SET status = 'replaced', is_active = false WHERE id = ? AND status IN ('active', 'past_due')", *activation.PreviousSubscriptionID).Error
+	})
+
+	return redeemed, err
+}
+
+// AddGiftRefund adds a refund to the purchase. Once it is refunded in full the codes that were not redeemed yet expire,
+// subscriptions already started from it run until their period ends.
+func (g *giftRepository) AddGiftRefund(purchaseID uint, amount int64) error {
+	return g.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(`UPDATE gift_purchases SET refunded = refunded + ?,
+			status = CASE WHEN refunded + ? >= amount THEN 'refunded' ELSE status END
+			WHERE id = ?`, amount, amount, purchaseID)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		return tx.Exec(`UPDATE gift_codes SET expires_at = ? WHERE purchase_id = ? AND redeemed_by IS NULL AND expires_at > ?
+			AND EXISTS (SELECT 1 FROM gift_purchases p WHERE p.id = ? AND p.status = 'refunded')`,
+			time.Now(), purchaseID, time.Now(), purchaseID).Error
+	})
+}
@@ -8,12 +8,16 @@ import (
 type AdminRepository interface {
 	LoginHandler(adminDetails models.AdminLogin) (domain.Admin, error)
 	GetUsers(page int, limit int) ([]models.UserDetailsAtAdmin, error)
-	GetUserByID(id string) (domain.User, error)
-	UpdateBlockUserByID(user domain.User) error
 	GetReports(page, limit int) ([]domain.Reports, error)
-	AddSubscriptionPlan(name string, duration int, price float64) error
+	AddSubscriptionPlan(plan *domain.SubscriptionPlan) error
 	DeleteSubscriptionPlan(planID int) error
 	GetSubscriptionPlans() ([]domain.SubscriptionPlan, error)
 	GetUserReports(userId, page, limit int) ([]domain.Reports, error)
 	GetUserReportsCount(userId int) (int64, error)
+	GetAdminByID(adminID uint) (domain.Admin, bool, error)
+	AdminEmailExists(email string) (bool, error)
+	CreateAdmin(admin *domain.Admin) error
+	ListAdmins() ([]models.AdminAccount, error)
+	UpdateAdmin(adminID uint, values map[string]interface{}) error
+	CountActiveSuperAdmins() (int64, error)
 }
@@ -0,0 +1,25 @@
+package interfaces
+
+import (
+	"main/pkg/domain"
+	"main/pkg/utils/models"
+	"time"
+)
+
+type AnalyticsRepository interface {
+	RollupDay(day time.Time) error
+	GetCreatorDailyStats(creatorID int, from, to time.Time) ([]domain.CreatorDailyStat, error)
+	GetCreatorDailyRevenue(creatorID int, from, to time.Time) ([]domain.CreatorDailyRevenue, error)
+	GetPlatformDailyStats(from, to time.Time) ([]domain.PlatformDailyStat, error)
+	GetPlatformDailyRevenue(from, to time.Time) ([]domain.PlatformDailyRevenue, error)
+	GetVideoDailyStats(videoID uint, from, to time.Time) ([]domain.VideoDailyStat, error)
+	ListVideoAnalytics(creatorID int, from, to time.Time, sort string, page, limit int) ([]models.VideoAnalytics, error)
+	GetVideo(videoID uint) (domain.Video, bool, error)
+	GetRetention(videoID uint, duration int, from, to time.Time, step int) ([]models.RetentionPoint, error)
+	GetReportSettings(creatorID int) (domain.AnalyticsReportSetting, bool, error)
+	SaveReportSettings(setting domain.AnalyticsReportSetting) error
+	ListWeeklyReportsDue(periodStart time.Time) ([]domain.AnalyticsReportSetting, error)
+	CreateReport(report *domain.AnalyticsReport) (bool, error)
+	ListReports(creatorID int, page, limit int) ([]domain.AnalyticsReport, error)
+	GetReport(reportID uint) (domain.AnalyticsReport, error)
+}
@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"main/pkg/domain"
+	"main/pkg/utils/models"
+	"time"
+)
+
+type AuditRepository interface {
+	Snapshot(targetType, targetID string) (*string, error)
+	CreateLog(log *domain.AuditLog) error
+	ListLogs(filter models.AuditFilter, from, to time.Time, page, limit int) ([]domain.AuditLog, error)
+}
@@ -6,11 +6,17 @@ import (
 )
 
 type CategoryRepository interface {
-	AddCategory(category string) (domain.Category, error)
+	AddCategory(category domain.Category) (domain.Category, error)
 	CheckCategory(currrent string) (bool, error)
-	UpdateCategory(current, new string) (domain.Category, error)
-	DeleteCategory(categoryID string) error
+	GetCategoryByID(categoryID uint) (domain.Category, error)
+	GetCategoryBySlug(slug string) (domain.Category, error)
+	SlugExists(slug string, excludeID uint) (bool, error)
+	UpdateCategory(category domain.Category) (domain.Category, error)
+	CountCategoryVideos(categoryID uint) (int64, error)
+	DeleteCategory(categoryID, moveTo uint) error
 	GetCategories(page, limit int) ([]domain.Category, error)
+	GetAllCategories() ([]models.CategoryTree, error)
+	ReorderCategories(orders []models.CategoryOrder) error
 	// ListVideosByCategory(categoryID, page, limit int) (models.VideoResponses, error)
 	ListVideosByCategory(categoryID, page, limit int) ([]models.VideoResponses, error)
 }
@@ -0,0 +1,12 @@
+package interfaces
+
+import "main/pkg/domain"
+
+type CouponRepository interface {
+	CreateCoupon(coupon *domain.Coupon) error
+	GetCoupon(couponID uint) (domain.Coupon, error)
+	GetCouponByCode(code string) (domain.Coupon, error)
+	ListCoupons(creatorID *int, page, limit int) ([]domain.Coupon, error)
+	DeactivateCoupon(couponID uint) error
+	HasRedeemed(couponID uint, userID int) (bool, error)
+}
@@ -0,0 +1,23 @@
+package interfaces
+
+import "main/pkg/domain"
+
+type EnforcementRepository interface {
+	UserExists(userID int) (bool, error)
+	FindContentOwner(targetType string, targetID int) (int, bool, error)
+	CreateAction(action *domain.EnforcementAction) error
+	TakeDown(action *domain.EnforcementAction) error
+	Ban(action *domain.EnforcementAction) error
+	CountActiveStrikes(userID int) (int64, error)
+	GetAction(actionID uint) (domain.EnforcementAction, bool, error)
+	ListActions(userID int, page, limit int) ([]domain.EnforcementAction, error)
+	GetActiveSuspension(userID int) (domain.EnforcementAction, bool, error)
+	GetActiveBan(userID int) (domain.EnforcementAction, bool, error)
+	RevokeAction(actionID, adminID uint, reason string) (domain.EnforcementAction, bool, error)
+	FindUserCredentials(email string) (int, string, bool, error)
+	CreateAppeal(appeal *domain.Appeal) (bool, error)
+	GetAppeal(appealID uint) (domain.Appeal, bool, error)
+	ListUserAppeals(userID int) ([]domain.Appeal, error)
+	ListAppeals(status string, page, limit int) ([]domain.Appeal, error)
+	DecideAppeal(appealID, adminID uint, overturn bool, response string) (domain.Appeal, bool, error)
+}
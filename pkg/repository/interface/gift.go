@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"main/pkg/domain"
+	"main/pkg/utils/models"
+)
+
+type GiftRepository interface {
+	CreateGiftPurchase(purchase *domain.GiftPurchase) error
+	SetGiftOrder(purchaseID uint, razorOrderID string) error
+	GetGiftPurchase(purchaseID uint) (domain.GiftPurchase, error)
+	FindGiftPurchaseByRazorOrderID(razorOrderID string) (domain.GiftPurchase, bool, error)
+	FindGiftPurchaseByPaymentID(paymentID string) (domain.GiftPurchase, bool, error)
+	FulfilGiftPurchase(purchaseID uint, paymentID string, codes []domain.GiftCode) (bool, error)
+	ListGiftPurchases(buyerID int, page, limit int) ([]domain.GiftPurchase, error)
+	ListGiftCodes(purchaseID uint) ([]domain.GiftCode, error)
+	GetGiftCode(code string) (domain.GiftCode, error)
+	RedeemGiftCode(codeID uint, subscription *domain.SubscriptionList, activation models.SubscriptionActivation) (bool, error)
+	AddGiftRefund(purchaseID uint, amount int64) error
+}
@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"main/pkg/domain"
+	"main/pkg/utils/models"
+)
+
+type InvoiceRepository interface {
+	IssueInvoice(invoice *domain.Invoice) (bool, error)
+	GetInvoiceParties(userID, creatorID int) (models.InvoiceParties, error)
+	GetInvoice(invoiceID uint) (domain.Invoice, error)
+	ListUserInvoices(userID int, page, limit int) ([]domain.Invoice, error)
+}
@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"main/pkg/domain"
+	"main/pkg/utils/models"
+	"time"
+)
+
+type LedgerRepository interface {
+	PostTransaction(transaction *domain.LedgerTransaction) (bool, error)
+	GetTransaction(kind, reference string) (domain.LedgerTransaction, bool, error)
+	ListTransactions(kind string, creatorID int, page, limit int) ([]domain.LedgerTransaction, error)
+	GetCreatorTotals(creatorID int, startDate, endDate string) (map[string]map[string]int64, error)
+	GetCharges(from, to time.Time, limit int) ([]models.LedgerCharge, error)
+	GetPaidSubscriptionsWithoutCharge(from, to time.Time) ([]domain.SubscriptionList, error)
+	GetUnbalancedTransactions() ([]uint, error)
+}
@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"main/pkg/domain"
+	"main/pkg/utils/models"
+)
+
+type ModerationRepository interface {
+	FindReportTarget(targetType string, targetID int) (int, bool, error)
+	HasOpenReport(reporterID int, targetType string, targetID int, category string) (bool, error)
+	FileReport(report *domain.Reports) (domain.ModerationCase, error)
+	ListCases(filter models.CaseFilter, page, limit int) ([]domain.ModerationCase, error)
+	GetCase(caseID uint) (domain.ModerationCase, error)
+	AdminExists(adminID uint) (bool, error)
+	AssignCase(caseID uint, assigneeID *uint) (bool, error)
+	AddCaseNote(note *domain.CaseNote) error
+	ResolveCase(caseID, adminID uint, action, reason string) (bool, error)
+	ListCaseReporters(caseID uint) ([]int, error)
+}
@@ -0,0 +1,10 @@
+package interfaces
+
+import "main/pkg/domain"
+
+type NotificationRepository interface {
+	CreateNotification(notification *domain.Notification) error
+	GetNotifications(userID int, unreadOnly bool, page, limit int) ([]domain.Notification, error)
+	CountUnreadNotifications(userID int) (int64, error)
+	MarkNotificationsRead(userID int, notificationIDs []uint) error
+}
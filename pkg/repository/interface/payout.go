@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"main/pkg/domain"
+	"main/pkg/utils/models"
+	"time"
+)
+
+type PayoutRepository interface {
+	GetPayoutAccount(creatorID int) (domain.PayoutAccount, bool, error)
+	SavePayoutAccount(account *domain.PayoutAccount) error
+	GetCreatorBalances(creatorID int, cutoff time.Time) ([]models.CreatorBalance, error)
+	GetPayableCurrencies() ([]string, error)
+	CreatePayoutBatch(period string, cutoff time.Time, minAmount int64, currency string) (domain.PayoutBatch, bool, error)
+	ListPayoutBatches(page, limit int) ([]domain.PayoutBatch, error)
+	GetPayoutBatch(batchID uint) (domain.PayoutBatch, error)
+	ApprovePayoutBatch(batchID uint) (bool, error)
+	GetPayout(payoutID uint) (domain.Payout, error)
+	MarkPayoutPaid(payoutID uint, reference string) (bool, error)
+	ListCreatorPayouts(creatorID int, page, limit int) ([]domain.Payout, error)
+}
@@ -0,0 +1,25 @@
+package interfaces
+
+import (
+	"main/pkg/domain"
+	"main/pkg/utils/models"
+	"time"
+)
+
+type SearchRepository interface {
+	SearchVideos(filter models.SearchFilter) ([]models.VideoSearchResult, int64, error)
+	SearchCreators(searchTerm string, page, limit int) ([]models.CreatorSearchResult, int64, error)
+	SearchTags(searchTerm string, page, limit int) ([]models.TagSearchResult, int64, error)
+	SearchCategories(searchTerm string, page, limit int) ([]models.CategorySearchResult, int64, error)
+	VideoFacets(filter models.SearchFilter) (models.SearchFacets, error)
+	SuggestTitles(prefix string, limit int) ([]models.Suggestion, error)
+	SuggestCreators(prefix string, limit int) ([]models.Suggestion, error)
+	SuggestTags(prefix string, limit int) ([]models.Suggestion, error)
+	SimilarTerms(term string, limit int) ([]string, error)
+	GetSearchVocabulary(limit int) ([]string, error)
+	LogSearchQuery(query *domain.SearchQuery) error
+	RecordSearchClick(searchID uint, userHash, clickedType string, clickedID int) error
+	GetTopSearchQueries(from, to time.Time, limit int) ([]models.SearchQueryStat, error)
+	GetZeroResultQueries(from, to time.Time, limit int) ([]models.SearchQueryStat, error)
+	GetSearchTrends(interval string, from, to time.Time, query string) ([]models.SearchTrendPoint, error)
+}
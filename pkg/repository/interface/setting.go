@@ -0,0 +1,6 @@
+package interfaces
+
+type SettingRepository interface {
+	GetSettings(keys ...string) (map[string]string, error)
+	SetSettings(values map[string]string) error
+}
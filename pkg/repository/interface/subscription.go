@@ -1,15 +1,49 @@
 package interfaces
 
-import "main/pkg/domain"
+import (
+	"main/pkg/domain"
+	"main/pkg/money"
+	"main/pkg/utils/models"
+	"time"
+)
 
 type SubscriptionRepository interface {
-	GetSubscriptionListIDByPlanID(planID, creatorID, userID int) (int, error)
+	GetSubscriptionListIDByPlanID(planID, creatorID, userID int, kind string) (int, error)
+	GetPendingSubscription(creatorID, userID int) (*domain.SubscriptionList, error)
+	UpdatePendingSubscription(subscriptionListID, planID int, kind string) error
+	ExpireStalePendingSubscriptions(before time.Time) (int64, error)
+	GetPlan(planID int) (domain.SubscriptionPlan, error)
+	SetSubscriptionPricing(subscriptionListID int, couponID *uint, discount int64, tax models.TaxQuote) error
+	GetRegionalPrice(planID int, region string) (money.Money, bool, error)
+	HasSubscribedBefore(userID, creatorID int) (bool, error)
+	StartTrial(subscriptionListID int, periodStart, periodEnd time.Time) error
 	FindUsername(user_id int) (string, error)
-	FindPrice(orderID int) (float64, error)
-	UpdatePaymentDetails(orderID, paymentID, razorID string) error
+	FindBillingCountry(userID int) (string, error)
+	ActivateSubscription(subscriptionListID int, paymentID string, activation models.SubscriptionActivation) (bool, error)
 	GetActiveSubscription(creatorID, userID int) (*domain.SubscriptionList, error)
-	GetRevenue(creatorID int, startDate string, endDate string) (float64, error)
 	GetSubscribersCount(creatorID int, startDate string, endDate string) (int, error)
+	SetGatewayOrder(subscriptionListID int, razorOrderID string, amount int64, currency string) error
+	GetSubscriptionByID(subscriptionListID int) (domain.SubscriptionList, error)
+	GetSubscriptionByRazorOrderID(razorOrderID string) (domain.SubscriptionList, error)
+	MarkPaymentFailed(razorOrderID string) error
+	GetSubscriptionByPaymentID(paymentID string) (domain.SubscriptionList, error)
+	RecordRefund(refund *domain.PaymentRefund) (bool, error)
+	RevokeSubscription(subscriptionListID int) error
+	SuspendSubscription(subscriptionListID int) (bool, error)
+	SaveDispute(dispute *domain.PaymentDispute) (bool, error)
+	GetDispute(disputeID uint) (domain.PaymentDispute, error)
+	ListDisputes(needsReview bool, page, limit int) ([]domain.PaymentDispute, error)
+	MarkDisputeReviewed(disputeID uint, note string) error
+	StorePaymentEvent(event *domain.PaymentEvent) (bool, error)
+	GetPaymentEvent(eventID uint) (domain.PaymentEvent, error)
+	UpdatePaymentEventStatus(eventID uint, status, errMsg string) error
+	ListPaymentEvents(status string, page, limit int) ([]domain.PaymentEvent, error)
+	GetSubscriptionsForRenewalReminder(before time.Time) ([]domain.SubscriptionList, error)
+	MarkRenewalReminderSent(subscriptionListID int) error
+	GetSubscriptionsEndedBefore(status string, before time.Time) ([]domain.SubscriptionList, error)
+	SetSubscriptionStatus(subscriptionListID int, from, to string) (bool, error)
+	SetCancelAtPeriodEnd(subscriptionListID int, cancel bool) error
+	ListUserSubscriptions(userID int) ([]models.UserSubscription, error)
 
 	// FetchActiveSubscriptions() ([]domain.SubscriptionList, error)
 	// UpdateSubscriptionInDatabase(subscription domain.SubscriptionList) error
@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"main/pkg/domain"
+	"main/pkg/utils/models"
+)
+
+type TagRepository interface {
+	GetTagByID(tagID uint) (domain.Tag, error)
+	FindTagByName(name string) (domain.Tag, bool, error)
+	ResolveTag(name string) (domain.Tag, bool, error)
+	AddAlias(tagID uint, alias string) (domain.TagAlias, error)
+	DeleteAlias(aliasID uint) error
+	GetAliases(tagID uint) ([]domain.TagAlias, error)
+	MergeTags(sourceID, targetID uint) (models.TagMergeResult, error)
+	IsTagBanned(name string) (bool, error)
+	BanTag(name, reason string) (domain.BannedTag, error)
+	UnbanTag(bannedID uint) error
+	GetBannedTags(page, limit int) ([]domain.BannedTag, error)
+	GetTagStats(page, limit int) ([]models.TagStat, error)
+}
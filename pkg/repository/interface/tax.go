@@ -0,0 +1,10 @@
+package interfaces
+
+import "main/pkg/domain"
+
+type TaxRepository interface {
+	ListTaxRules() ([]domain.TaxRule, error)
+	SaveTaxRule(rule *domain.TaxRule) error
+	DeleteTaxRule(region string) (bool, error)
+	GetTaxRule(region string) (domain.TaxRule, bool, error)
+}
@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"main/pkg/domain"
+	"main/pkg/utils/models"
+)
+
+type TierRepository interface {
+	CreateTier(tier *domain.SubscriptionPlan) error
+	GetTier(tierID int) (domain.SubscriptionPlan, error)
+	UpdateTier(tier domain.SubscriptionPlan) error
+	DeleteTier(tierID int) (bool, error)
+	ListCreatorTiers(creatorID int) ([]models.Tier, error)
+	GetTierVideoIDs(tierID int) ([]uint, error)
+	SetTierVideos(tierID int, videoIDs []uint) error
+	GetTierPrices(tierID int) ([]domain.PlanPrice, error)
+	SetTierPrices(tierID int, prices []domain.PlanPrice) error
+	GetCreatorVideos(creatorID int, videoIDs []uint) ([]domain.Video, error)
+}
@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"main/pkg/domain"
+	"main/pkg/utils/models"
+)
+
+type TipRepository interface {
+	CreateTip(tip *domain.Tip) error
+	SetTipOrder(tipID uint, razorOrderID string) error
+	GetTip(tipID uint) (domain.Tip, error)
+	FindTipByRazorOrderID(razorOrderID string) (domain.Tip, bool, error)
+	FindTipByPaymentID(paymentID string) (domain.Tip, bool, error)
+	MarkTipPaid(tipID uint, paymentID string) (bool, error)
+	AddTipRefund(tipID uint, amount int64) error
+	FindUsername(userID int) (string, error)
+	GetVideoOwner(videoID uint) (int, error)
+	GetComment(commentID uint) (domain.Comment, error)
+	ListTips(filter models.TipFilter, page, limit int) ([]models.TipDetails, error)
+	GetTipTotals(creatorID int, startDate, endDate string) (map[string]int64, error)
+}
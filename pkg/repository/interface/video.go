@@ -3,20 +3,24 @@ package interfaces
 import (
 	"main/pkg/domain"
 	"main/pkg/utils/models"
+	"time"
 )
 
 type VideoRepository interface {
 	UploadVideo(userID int, categoryID int, title, description, url string, tags []string, exclusive bool) (uint, error)
 	ListVideos(userID, page, limit int) ([]models.Video, error)
 	EditVideoDetails(videoID int, title, description string) error
+	SetVideoTags(videoID int, tags []string) ([]string, error)
 	DeleteVideo(videoID int) error
 	IncrementVideoViews(videoID int) error
+	RecordVideoView(view *domain.VideoView) error
+	UpdateWatchProgress(userID, videoID, position int, since time.Time) (bool, error)
 	IsLikedByUser(userID uint, videoID uint) bool
 	UnlikeVideo(userID uint, videoID uint) error
 	LikeVideo(userID uint, videoID uint) error
 	CreateComment(comment *domain.Comment) error
 	GetCommentsByVideoID(videoID uint) ([]domain.Comment, error)
-	AddTags(tags []string) error
+	AddTags(tags []string) ([]string, error)
 	DeleteTagByID(tagID uint) error
 	GetTags() ([]domain.Tag, error)
 	StoreUserTags(userTags []domain.UserTags) error
@@ -25,7 +29,11 @@ type VideoRepository interface {
 	GetUserTags(userID int) ([]string, error)
 	UpdateVideoLikesCount(videoID uint) error
 	GetVideoTagsByVideoID(videoID uint) ([]string, error)
-	IsUserSubscribed(userID int, creatorID int) (bool, error)
+	GetVideoByID(videoID int) (domain.Video, error)
+	IsUserSubscribed(userID int, creatorID int, videoID int) (bool, error)
 	IsVideoExclusive(videoID int) (bool, error)
 	ListtVideos(page, limit int, sort, order, search string) ([]models.Video, error)
+	CountVideosByTitle(search string) (int64, error)
+	ListVideosWithoutDuration(limit int) ([]domain.Video, error)
+	SetVideoDuration(videoID uint, duration int) error
 }
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"main/pkg/domain"
+	interfaces "main/pkg/repository/interface"
+	"main/pkg/utils/models"
+
+	"gorm.io/gorm"
+)
+
+type invoiceRepository struct {
+	DB *gorm.DB
+}
+
+func NewInvoiceRepository(DB *gorm.DB) interfaces.InvoiceRepository {
+	return &invoiceRepository{DB}
+}
+
+// errInvoiceIssued rolls back the number taken for a subscription that already has its invoice
+var errInvoiceIssued = errors.New("invoice already issued")
+
+// IssueInvoice numbers and stores the invoice, issued is false when the subscription already had one, which is loaded instead.
+// The year's counter row is locked first, so invoices are numbered one at a time and a rolled back invoice frees its number.
+func (i *invoiceRepository) IssueInvoice(invoice *domain.Invoice) (bool, error) {
+	year := invoice.IssuedAt.Year()
+
+	err := i.DB.Transaction(func(tx *gorm.DB) error {
+		var number int64
+		err := tx.Raw(`INSERT INTO invoice_sequences (year, last_number) VALUES (?, 1)
+			ON CONFLICT (year) DO UPDATE SET last_number = invoice_sequences.last_number + 1
+			RETURNING last_number`, year).Scan(&number).Error
+		if err != nil {
+			return err
+		}
+
+		var existing []domain.Invoice
+		if err := tx.Where("subscription_list_id = ?", invoice.SubscriptionListID).Limit(1).Find(&existing).Error; err != nil {
+			return err
+		}
+		if len(existing) > 0 {
+			*invoice = existing[0]
+			return errInvoiceIssued
+		}
+
+		invoice.Year = year
+		invoice.Sequence = number
+		invoice.Number = fmt.Sprintf("INV-%d-%06d", year, number)
+		return tx.Create(invoice).Error
+	})
+	if errors.Is(err, errInvoiceIssued) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (i *invoiceRepository) GetInvoiceParties(userID, creatorID int) (models.InvoiceParties, error) {
+	var parties models.InvoiceParties
+
+	err := i.DB.Raw(`SELECT COALESCE(NULLIF(b.name, ''), b.username, '') AS buyer_name, COALESCE(b.email, '') AS buyer_email,
+			COALESCE(NULLIF(c.name, ''), c.username, '') AS creator_name
+		FROM users b LEFT JOIN users c ON c.id = ?
+		WHERE b.id = ?`, creatorID, userID).Scan(&parties).Error
+	if err != nil {
+		return models.InvoiceParties{}, err
+	}
+
+	return parties, nil
+}
+
+func (i *invoiceRepository) GetInvoice(invoiceID uint) (domain.Invoice, error) {
+	var invoice domain.Invoice
+	if err := i.DB.First(&invoice, invoiceID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Invoice{}, errors.New("invoice not found")
+		}
+		return domain.Invoice{}, err
+	}
+
+	return invoice, nil
+}
+
+func (i *invoiceRepository) ListUserInvoices(userID int, page, limit int) ([]domain.Invoice, error) {
+	var invoices []domain.Invoice
+	if err := i.DB.Where("user_id = ?", userID).Order("issued_at DESC, id DESC").Offset((page - 1) * limit).Limit(limit).Find(&invoices).Error; err != nil {
+		return nil, err
+	}
+
+	return invoices, nil
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"errors"
+	"main/pkg/domain"
+	interfaces "main/pkg/repository/interface"
+	"main/pkg/utils/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ledgerRepository struct {
+	DB *gorm.DB
+}
+
+func NewLedgerRepository(DB *gorm.DB) interfaces.LedgerRepository {
+	return &ledgerRepository{DB}
+}
+
+// PostTransaction stores the transaction with its entries, created is false when the movement was already recorded.
+// Entries that do not balance are refused, the ledger is only ever appended to.
+func (l *ledgerRepository) PostTransaction(transaction *domain.LedgerTransaction) (bool, error) {
+	var debits, credits int64
+	for _, entry := range transaction.Entries {
+		if entry.Debit < 0 || entry.Credit < 0 {
+			return false, errors.New("ledger amounts cannot be negative")
+		}
+		debits += entry.Debit
+		credits += entry.Credit
+	}
+	if len(transaction.Entries) < 2 || debits != credits {
+		return false, errors.New("ledger transaction does not balance")
+	}
+
+	entries := transaction.Entries
+	created := false
+
+	err := l.DB.Transaction(func(tx *gorm.DB) error {
+		transaction.Entries = nil
+		result := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "type"}, {Name: "reference"}},
+			DoNothing: true,
+		}).Create(transaction)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		created = true
+
+		for i := range entries {
+			entries[i].TransactionID = transaction.ID
+		}
+		return tx.Create(&entries).Error
+	})
+
+	transaction.Entries = entries
+	return created, err
+}
+
+func (l *ledgerRepository) GetTransaction(kind, reference string) (domain.LedgerTransaction, bool, error) {
+	var transactions []domain.LedgerTransaction
+	if err := l.DB.Preload("Entries").Where("type = ? AND reference = ?", kind, reference).Limit(1).Find(&transactions).Error; err != nil {
+		return domain.LedgerTransaction{}, false, err
+	}
+	if len(transactions) == 0 {
+		return domain.LedgerTransaction{}, false, nil
+	}
+
+	return transactions[0], true, nil
+}
+
+// ListTransactions returns the newest transactions first with their entries, filtered by type and creator when set.
+func (l *ledgerRepository) ListTransactions(kind string, creatorID int, page, limit int) ([]domain.LedgerTransaction, error) {
+	var transactions []domain.LedgerTransaction
+
+	query := l.DB.Preload("Entries").Order("id DESC").Offset((page - 1) * limit).Limit(limit)
+	if kind != "" {
+		query = query.Where("type = ?", kind)
+	}
+	if creatorID != 0 {
+		query = query.Where("creator_id = ?", creatorID)
+	}
+
+	if err := query.Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// GetCreatorTotals sums the movements on the creator's payable account by currency and transaction type,
+// refunds and payouts as positive amounts, within the dates when they are given.
+func (l *ledgerRepository) GetCreatorTotals(creatorID int, startDate, endDate string) (map[string]map[string]int64, error) {
+	var rows []struct {
+		Currency string
+		Type     string
+		Amount   int64
+	}
+
+	query := l.DB.Table("ledger_entries e").
+		Select("t.currency, t.type, COALESCE(SUM(e.credit - e.debit), 0) AS amount").
+		Joins("JOIN ledger_transactions t ON t.id = e.transaction_id").
+		Where("e.account = ? AND e.creator_id = ?", domain.AccountCreatorPayable, creatorID).
+		Group("t.currency, t.type")
+
+	if startDate != "" && endDate != "" {
+		query = query.Where("t.created_at BETWEEN ? AND ?", startDate, endDate)
+	}
+
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]map[string]int64)
+	for _, row := range rows {
+		if totals[row.Currency] == nil {
+			totals[row.Currency] = make(map[string]int64)
+		}
+		// refunds and payouts are debits on the payable account
+		if row.Type == domain.LedgerRefund || row.Type == domain.LedgerPayout {
+			row.Amount = -row.Amount
+		}
+		totals[row.Currency][row.Type] = row.Amount
+	}
+
+	return totals, nil
+}
+
+// GetCharges returns the charges recorded in the period, oldest first, with the refunds recorded on the same subscription.
+func (l *ledgerRepository) GetCharges(from, to time.Time, limit int) ([]models.LedgerCharge, error) {
+	var charges []models.LedgerCharge
+
+	query := `
+		SELECT t.id AS transaction_id, COALESCE(t.subscription_list_id, 0) AS subscription_list_id, t.reference AS payment_id, t.currency,
+			(SELECT COALESCE(SUM(e.debit), 0) FROM ledger_entries e WHERE e.transaction_id = t.id AND e.account = 'gateway_clearing') AS amount,
+			(SELECT COALESCE(SUM(e.credit), 0) FROM ledger_transactions r JOIN ledger_entries e ON e.transaction_id = r.id
+				WHERE r.type = 'refund' AND r.subscription_list_id = t.subscription_list_id AND e.account = 'gateway_clearing') AS refunded
+		FROM ledger_transactions t
+		WHERE t.type = 'charge' AND t.created_at >= ? AND t.created_at < ?
+		ORDER BY t.id
+		LIMIT ?`
+
+	if err := l.DB.Raw(query, from, to, limit).Scan(&charges).Error; err != nil {
+		return nil, err
+	}
+
+	return charges, nil
+}
+
+// GetPaidSubscriptionsWithoutCharge returns subscriptions paid through the gateway in the period that have no charge in the ledger.
+func (l *ledgerRepository) GetPaidSubscriptionsWithoutCharge(from, to time.Time) ([]domain.SubscriptionList, error) {
+	var subscriptions []domain.SubscriptionList
+
+	err := l.DB.Where(`payment_status IN ('PAID', 'REFUNDED') AND amount > 0 AND payment_id <> '' AND subscribed_at >= ? AND subscribed_at < ?
+		AND NOT EXISTS (SELECT 1 FROM ledger_transactions t WHERE t.type = 'charge' AND t.reference = subscription_lists.payment_id)`, from, to).
+		Order("id").Find(&subscriptions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+func (l *ledgerRepository) GetUnbalancedTransactions() ([]uint, error) {
+	ids := []uint{}
+	err := l.DB.Raw("SELECT transaction_id FROM ledger_entries GROUP BY transaction_id HAVING SUM(debit) <> SUM(credit) ORDER BY transaction_id").
+		Scan(&ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"errors"
+	"main/pkg/domain"
+	interfaces "main/pkg/repository/interface"
+	"main/pkg/utils/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type moderationRepository struct {
+	DB *gorm.DB
+}
+
+func NewModerationRepository(DB *gorm.DB) interfaces.ModerationRepository {
+	return &moderationRepository{DB}
+}
+
+// FindReportTarget returns the user a report is against: the user itself, or who posted the video or comment.
+func (m *moderationRepository) FindReportTarget(targetType string, targetID int) (int, bool, error) {
+	return findTargetUser(m.DB, targetType, targetID)
+}
+
+// HasOpenReport tells whether the reporter already reported the target for the category in a case still being handled.
+func (m *moderationRepository) HasOpenReport(reporterID int, targetType string, targetID int, category string) (bool, error) {
+	var count int64
+	err := m.DB.Model(&domain.Reports{}).
+		Joins("JOIN moderation_cases c ON c.id = reports.case_id").
+		Where("reports.reporter_id = ? AND c.target_type = ? AND c.target_id = ? AND c.category = ? AND c.status IN ?",
+			reporterID, targetType, targetID, category, []string{domain.CaseOpen, domain.CaseInReview}).
+		Count(&count).Error
+
+	return count > 0, err
+}
+
+// FileReport stores the report in the open case for its target and category, starting the case if there is none.
+func (m *moderationRepository) FileReport(report *domain.Reports) (domain.ModerationCase, error) {
+	if report.CreatedAt == nil {
+		now := time.Now()
+		report.CreatedAt = &now
+	}
+
+	var moderationCase domain.ModerationCase
+	err := m.DB.Transaction(func(tx *gorm.DB) error {
+		// the partial unique index keeps one case being handled per target and category
+		err := tx.Raw(`
+			INSERT INTO moderation_cases (target_type, target_id, target_user_id, category, status, report_count, created_at, updated_at)
+			VALUES (?, ?, ?, ?, 'open', 1, now(), now())
+			ON CONFLICT (target_type, target_id, category) WHERE status IN ('open', 'in_review')
+			DO UPDATE SET report_count = moderation_cases.report_count + 1, updated_at = now()
+			RETURNING *`, report.TargetType, report.TargetID, report.TargetUserID, report.Category).Scan(&moderationCase).Error
+		if err != nil {
+			return err
+		}
+
+		report.CaseID = &moderationCase.ID
+		return tx.Create(report).Error
+	})
+
+	return moderationCase, err
+}
+
+// ListCases returns the cases matching the filter, the most reported first and then the oldest.
+func (m *moderationRepository) ListCases(filter models.CaseFilter, page, limit int) ([]domain.ModerationCase, error) {
+	query := m.DB.Model(&domain.ModerationCase{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Category != "" {
+		query = query.Where("category = ?", filter.Category)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.AssigneeID != 0 {
+		query = query.Where("assignee_id = ?", filter.AssigneeID)
+	}
+
+	var cases []domain.ModerationCase
+	err := query.Order("report_count DESC, created_at, id").Offset((page - 1) * limit).Limit(limit).Find(&cases).Error
+
+	return cases, err
+}
+
+// GetCase returns the case with its reports and notes, oldest first.
+func (m *moderationRepository) GetCase(caseID uint) (domain.ModerationCase, error) {
+	var moderationCase domain.ModerationCase
+	err := m.DB.Preload("Reports", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Preload("Notes", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).First(&moderationCase, caseID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ModerationCase{}, errors.New("case not found")
+		}
+		return domain.ModerationCase{}, err
+	}
+
+	return moderationCase, nil
+}
+
+func (m *moderationRepository) AdminExists(adminID uint) (bool, error) {
+	var count int64
+	err := m.DB.Model(&domain.Admin{}).Where("id = ?", adminID).Count(&count).Error
+
+	return count > 0, err
+}
+
+// AssignCase hands a case still being handled to the admin and puts it in review, without an admin it goes back to the queue.
+func (m *moderationRepository) AssignCase(caseID uint, assigneeID *uint) (bool, error) {
+	status := domain.CaseOpen
+	if assigneeID != nil {
+		status = domain.CaseInReview
+	}
+
+	result := m.DB.Model(&domain.ModerationCase{}).
+		Where("id = ? AND status IN ?", caseID, []string{domain.CaseOpen, domain.CaseInReview}).
+		Updates(map[string]interface{}{"assignee_id": assigneeID, "status": status})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (m *moderationRepository) AddCaseNote(note *domain.CaseNote) error {
+	return m.DB.Create(note).Error
+}
+
+// ResolveCase closes a case still being handled and takes the action on its target in the same transaction,
+// a removal or block is recorded as an enforcement action with the reason so it can be appealed and revoked.
+// It reports whether the case was still being handled.
+func (m *moderationRepository) ResolveCase(caseID, adminID uint, action, reason string) (bool, error) {
+	status := domain.CaseResolved
+	if action == domain.ModerationDismiss {
+		status = domain.CaseDismissed
+	}
+
+	resolved := false
+	err := m.DB.Transaction(func(tx *gorm.DB) error {
+		var moderationCase domain.ModerationCase
+		result := tx.Raw(`
+			UPDATE moderation_cases SET status = ?, action = ?, resolved_by = ?, resolved_at = ?, updated_at = now()
+			WHERE id = ? AND status IN ('open', 'in_review')
+			RETURNING *`, status, action, adminID, time.Now(), caseID).Scan(&moderationCase)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		resolved = true
+
+		enforcement := domain.EnforcementAction{
+			UserID:   moderationCase.TargetUserID,
+			Reason:   reason,
+			CaseID:   &moderationCase.ID,
+			IssuedBy: adminID,
+		}
+		switch action {
+		case domain.ModerationRemoveContent:
+			enforcement.Type = domain.EnforcementTakedown
+			enforcement.TargetType = moderationCase.TargetType
+			enforcement.TargetID = moderationCase.TargetID
+			return takeDown(tx, &enforcement)
+		case domain.ModerationBlockUser:
+			enforcement.Type = domain.EnforcementBan
+			return ban(tx, &enforcement)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return resolved, nil
+}
+
+func (m *moderationRepository) ListCaseReporters(caseID uint) ([]int, error) {
+	var reporterIDs []int
+	err := m.DB.Model(&domain.Reports{}).Where("case_id = ?", caseID).Distinct().Pluck("reporter_id", &reporterIDs).Error
+
+	return reporterIDs, err
+}
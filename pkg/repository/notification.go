@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"main/pkg/domain"
+	interfaces "main/pkg/repository/interface"
+
+	"gorm.io/gorm"
+)
+
+type notificationRepository struct {
+	DB *gorm.DB
+}
+
+func NewNotificationRepository(DB *gorm.DB) interfaces.NotificationRepository {
+	return &notificationRepository{DB}
+}
+
+func (n *notificationRepository) CreateNotification(notification *domain.Notification) error {
+	return n.DB.Create(notification).Error
+}
+
+func (n *notificationRepository) GetNotifications(userID int, unreadOnly bool, page, limit int) ([]domain.Notification, error) {
+	var notifications []domain.Notification
+
+	query := n.DB.Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read = false")
+	}
+
+	if err := query.Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&notifications).Error; err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+func (n *notificationRepository) CountUnreadNotifications(userID int) (int64, error) {
+	var count int64
+	if err := n.DB.Model(&domain.Notification{}).Where("user_id = ? AND read = false", userID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// MarkNotificationsRead marks the given notifications of the user as read, all of them when no IDs are given.
+func (n *notificationRepository) MarkNotificationsRead(userID int, notificationIDs []uint) error {
+	query := n.DB.Model(&domain.Notification{}).Where("user_id = ? AND read = false", userID)
+	if len(notificationIDs) > 0 {
+		query = query.Where("id IN ?", notificationIDs)
+	}
+
+	return query.Update("read", true).Error
+}
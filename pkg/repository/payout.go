@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"errors"
+	"main/pkg/domain"
+	interfaces "main/pkg/repository/interface"
+	"main/pkg/utils/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type payoutRepository struct {
+	DB *gorm.DB
+}
+
+func NewPayoutRepository(DB *gorm.DB) interfaces.PayoutRepository {
+	return &payoutRepository{DB}
+}
+
+// payableBalances sums each creator's payable account per currency. Charges count once they are older than the cutoff,
+// refunds and payouts count right away so money that was given back is never paid out.
+const payableBalances = `SELECT e.creator_id, t.currency,
+		COALESCE(SUM(e.credit - e.debit), 0) AS total,
+		COALESCE(SUM(CASE WHEN t.type <> 'charge' OR t.created_at < ? THEN e.credit - e.debit ELSE 0 END), 0) AS eligible
+	FROM ledger_entries e
+	JOIN ledger_transactions t ON t.id = e.transaction_id
+	WHERE e.account = 'creator_payable'`
+
+// reservedPayouts sums what each creator has in batches that are not paid yet, per currency
+const reservedPayouts = `SELECT creator_id, currency, SUM(amount) AS amount FROM payouts WHERE status IN ('pending', 'approved') GROUP BY creator_id, currency`
+
+func (p *payoutRepository) GetPayoutAccount(creatorID int) (domain.PayoutAccount, bool, error) {
+	var accounts []domain.PayoutAccount
+	if err := p.DB.Where("creator_id = ?", creatorID).Limit(1).Find(&accounts).Error; err != nil {
+		return domain.PayoutAccount{}, false, err
+	}
+	if len(accounts) == 0 {
+		return domain.PayoutAccount{}, false, nil
+	}
+
+	return accounts[0], true, nil
+}
+
+// SavePayoutAccount creates the creator's account or replaces its details.
+func (p *payoutRepository) SavePayoutAccount(account *domain.PayoutAccount) error {
+	return p.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "creator_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"method", "account_holder", "account_number", "ifsc", "upi_id", "updated_at"}),
+	}).Create(account).Error
+}
+
+// GetCreatorBalances returns the creator's balance in each currency the creator earned in.
+func (p *payoutRepository) GetCreatorBalances(creatorID int, cutoff time.Time) ([]models.CreatorBalance, error) {
+	var balances []models.CreatorBalance
+
+	err := p.DB.Raw(`SELECT b.currency, b.total, b.eligible, COALESCE(r.amount, 0) AS reserved
+		FROM (`+payableBalances+` AND e.creator_id = ? GROUP BY e.creator_id, t.currency) b
+		LEFT JOIN (`+reservedPayouts+`) r ON r.creator_id = b.creator_id AND r.currency = b.currency
+		ORDER BY b.currency`, cutoff, creatorID).Scan(&balances).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return balances, nil
+}
+
+// GetPayableCurrencies returns the currencies creators have earned in.
+func (p *payoutRepository) GetPayableCurrencies() ([]string, error) {
+	currencies := []string{}
+	err := p.DB.Raw(`SELECT DISTINCT t.currency FROM ledger_transactions t
+		WHERE EXISTS (SELECT 1 FROM ledger_entries e WHERE e.transaction_id = t.id AND e.account = 'creator_payable')
+		ORDER BY t.currency`).Scan(&currencies).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return currencies, nil
+}
+
+// CreatePayoutBatch makes the batch of the period in the currency with a payout for every creator who has a payout account
+// and at least the minimum to be paid in it. Created is false when the period already has its batch in the currency.
+func (p *payoutRepository) CreatePayoutBatch(period string, cutoff time.Time, minAmount int64, currency string) (domain.PayoutBatch, bool, error) {
+	batch := domain.PayoutBatch{Period: period, Cutoff: cutoff, Status: domain.PayoutPending, Currency: currency}
+	created := false
+
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		// Two batches made at once would both see the other's payouts as unreserved
+		if err := tx.Exec("LOCK TABLE payouts IN SHARE ROW EXCLUSIVE MODE").Error; err != nil {
+			return err
+		}
+
+		result := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "period"}, {Name: "currency"}}, DoNothing: true}).Create(&batch)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return tx.Where("period = ? AND currency = ?", period, currency).First(&batch).Error
+		}
+		created = true
+
+		err := tx.Exec(`INSERT INTO payouts (batch_id, creator_id, amount, currency, status, account_id, reference, created_at)
+			SELECT ?, b.creator_id, b.eligible - COALESCE(r.amount, 0), ?, 'pending', a.id, '', now()
+			FROM (`+payableBalances+` AND t.currency = ? GROUP BY e.creator_id, t.currency) b
+			JOIN payout_accounts a ON a.creator_id = b.creator_id
+			LEFT JOIN (`+reservedPayouts+`) r ON r.creator_id = b.creator_id AND r.currency = b.currency
+			WHERE b.eligible - COALESCE(r.amount, 0) >= ?`,
+			batch.ID, currency, cutoff, currency, minAmount).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Raw("UPDATE payout_batches SET total = (SELECT COALESCE(SUM(amount), 0) FROM payouts WHERE batch_id = ?) WHERE id = ? RETURNING total", batch.ID, batch.ID).Scan(&batch.Total).Error
+	})
+	if err != nil {
+		return domain.PayoutBatch{}, false, err
+	}
+
+	return batch, created, nil
+}
+
+func (p *payoutRepository) ListPayoutBatches(page, limit int) ([]domain.PayoutBatch, error) {
+	var batches []domain.PayoutBatch
+	if err := p.DB.Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&batches).Error; err != nil {
+		return nil, err
+	}
+
+	return batches, nil
+}
+
+func (p *payoutRepository) GetPayoutBatch(batchID uint) (domain.PayoutBatch, error) {
+	var batch domain.PayoutBatch
+	err := p.DB.Preload("Payouts", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).Preload("Payouts.Account").First(&batch, batchID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.PayoutBatch{}, errors.New("payout batch not found")
+		}
+		return domain.PayoutBatch{}, err
+	}
+
+	return batch, nil
+}
+
+// ApprovePayoutBatch approves a pending batch with all its payouts, approved is false when it was not pending.
+func (p *payoutRepository) ApprovePayoutBatch(batchID uint) (bool, error) {
+	approved := false
+
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.PayoutBatch{}).Where("id = ? AND status = ?", batchID, domain.PayoutPending).
+			Updates(map[string]interface{}{"status": domain.PayoutApproved, "approved_at": time.Now()})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		approved = true
+
+		return tx.Model(&domain.Payout{}).Where("batch_id = ? AND status = ?", batchID, domain.PayoutPending).Update("status", domain.PayoutApproved).Error
+	})
+
+	return approved, err
+}
+
+func (p *payoutRepository) GetPayout(payoutID uint) (domain.Payout, error) {
+	var payout domain.Payout
+	if err := p.DB.Preload("Account").First(&payout, payoutID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Payout{}, errors.New("payout not found")
+		}
+		return domain.Payout{}, err
+	}
+
+	return payout, nil
+}
+
+// MarkPayoutPaid records the transfer of an approved payout, the batch is paid once all its payouts are.
+// Paid is false when the payout was not approved.
+func (p *payoutRepository) MarkPayoutPaid(payoutID uint, reference string) (bool, error) {
+	paid := false
+
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		var payout domain.Payout
+		result := tx.Model(&payout).Clauses(clause.Returning{Columns: []clause.Column{{Name: "batch_id"}}}).
+			Where("id = ? AND status = ?", payoutID, domain.PayoutApproved).
+			Updates(map[string]interface{}{"status": domain.PayoutPaid, "reference": reference, "paid_at": now})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		paid = true
+
+		return tx.Exec(`UPDATE payout_batches SET status = ?, paid_at = ? WHERE id = ?
+			AND NOT EXISTS (SELECT 1 FROM payouts WHERE batch_id = ? AND status <> ?)`,
+			domain.PayoutPaid, now, payout.BatchID, payout.BatchID, domain.PayoutPaid).Error
+	})
+
+	return paid, err
+}
+
+func (p *payoutRepository) ListCreatorPayouts(creatorID int, page, limit int) ([]domain.Payout, error) {
+	var payouts []domain.Payout
+	if err := p.DB.Where("creator_id = ?", creatorID).Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&payouts).Error; err != nil {
+		return nil, err
+	}
+
+	return payouts, nil
+}
@@ -0,0 +1,471 @@
+package repository
+
+import (
+	"errors"
+	"main/pkg/domain"
+	interfaces "main/pkg/repository/interface"
+	"main/pkg/utils/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type searchRepository struct {
+	DB *gorm.DB
+}
+
+func NewSearchRepository(DB *gorm.DB) interfaces.SearchRepository {
+	return &searchRepository{DB}
+}
+
+// uploadDateBuckets maps the upload date filter values to how far back they reach
+var uploadDateBuckets = []struct {
+	Value  string
+	Label  string
+	Within time.Duration
+}{
+	{"hour", "Last hour", time.Hour},
+	{"today", "Today", 24 * time.Hour},
+	{"week", "This week", 7 * 24 * time.Hour},
+	{"month", "This month", 30 * 24 * time.Hour},
+	{"year", "This year", 365 * 24 * time.Hour},
+}
+
+// durationBuckets maps the duration filter values to a range of seconds, a zero Max means no upper bound.
+// Videos whose duration is not known yet are in no bucket.
+var durationBuckets = []struct {
+	Value string
+	Label string
+	Min   int
+	Max   int
+}{
+	{"short", "Under 4 minutes", 1, 240},
+	{"medium", "4-20 minutes", 240, 1200},
+	{"long", "Over 20 minutes", 1200, 0},
+}
+
+// videoSearchQuery builds the filtered video query, the filter named in skip is left out so facets can count across it
+func (sr *searchRepository) videoSearchQuery(filter models.SearchFilter, skip string) (*gorm.DB, error) {
+	query := sr.DB.Table("videos").
+		Joins("LEFT JOIN users ON users.id = videos.user_id").
+		Joins("LEFT JOIN categories ON categories.id = videos.category_id").
+		Where("videos.taken_down_at IS NULL")
+
+	if filter.Query != "" {
+		pattern := "%" + escapeLike(filter.Query) + "%"
+		query = query.Where("(videos.title ILIKE ? OR videos.description ILIKE ? OR EXISTS (SELECT 1 FROM video_tags WHERE video_tags.video_id = videos.id AND video_tags.tag ILIKE ?))", pattern, pattern, pattern)
+	}
+
+	if filter.UploadDate != "" && skip != "upload_date" {
+		found := false
+		for _, bucket := range uploadDateBuckets {
+			if bucket.Value == filter.UploadDate {
+				query = query.Where("videos.created_at >= ?", time.Now().Add(-bucket.Within))
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.New("invalid upload date filter")
+		}
+	}
+
+	if filter.Duration != "" && skip != "duration" {
+		found := false
+		for _, bucket := range durationBuckets {
+			if bucket.Value == filter.Duration {
+				query = query.Where("videos.duration >= ?", bucket.Min)
+				if bucket.Max > 0 {
+					query = query.Where("videos.duration < ?", bucket.Max)
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.New("invalid duration filter")
+		}
+	}
+
+	if filter.CategoryID > 0 && skip != "category" {
+		query = query.Where("videos.category_id = ?", filter.CategoryID)
+	}
+
+	if filter.Access != "" && skip != "access" {
+		switch filter.Access {
+		case "exclusive":
+			query = query.Where("videos.exclusive = true")
+		case "free":
+			query = query.Where("videos.exclusive = false")
+		default:
+			return nil, errors.New("invalid access filter")
+		}
+	}
+
+	if filter.CreatorID > 0 && skip != "creator" {
+		query = query.Where("videos.user_id = ?", filter.CreatorID)
+	}
+
+	return query, nil
+}
+
+func (sr *searchRepository) SearchVideos(filter models.SearchFilter) ([]models.VideoSearchResult, int64, error) {
+	query, err := sr.videoSearchQuery(filter, "")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var count int64
+	if err := query.Session(&gorm.Session{}).Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var videos []models.VideoSearchResult
+	offset := (filter.Page - 1) * filter.Limit
+	err = query.Select("videos.id, videos.user_id, COALESCE(users.username, '') AS creator, videos.title, videos.description, videos.url, videos.category_id, COALESCE(categories.category, '') AS category, videos.duration, videos.views, videos.likes, videos.exclusive, videos.created_at").
+		Order("videos.views DESC, videos.created_at DESC").
+		Offset(offset).
+		Limit(filter.Limit).
+		Scan(&videos).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return videos, count, nil
+}
+
+func (sr *searchRepository) SearchCreators(searchTerm string, page, limit int) ([]models.CreatorSearchResult, int64, error) {
+	pattern := "%" + escapeLike(searchTerm) + "%"
+
+	var count int64
+	if err := sr.DB.Raw("SELECT COUNT(*) FROM users WHERE username ILIKE ? OR name ILIKE ?", pattern, pattern).Scan(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var creators []models.CreatorSearchResult
+	err := sr.DB.Raw(`
+		SELECT u.id, u.username, u.name, u.bio, u.url,
+			(SELECT COUNT(*) FROM follows f WHERE f.following_id = u.id) AS followers
+		FROM users u
+		WHERE u.username ILIKE ? OR u.name ILIKE ?
+		ORDER BY followers DESC, u.username
+		LIMIT ? OFFSET ?`, pattern, pattern, limit, (page-1)*limit).Scan(&creators).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return creators, count, nil
+}
+
+func (sr *searchRepository) SearchTags(searchTerm string, page, limit int) ([]models.TagSearchResult, int64, error) {
+	pattern := "%" + escapeLike(searchTerm) + "%"
+
+	var count int64
+	if err := sr.DB.Raw("SELECT COUNT(*) FROM tags WHERE tag ILIKE ?", pattern).Scan(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var tags []models.TagSearchResult
+	err := sr.DB.Raw(`
+		SELECT t.id, t.tag,
+			(SELECT COUNT(*) FROM video_tags vt WHERE vt.tag_id = t.id) AS usage
+		FROM tags t
+		WHERE t.tag ILIKE ?
+		ORDER BY usage DESC, t.tag
+		LIMIT ? OFFSET ?`, pattern, limit, (page-1)*limit).Scan(&tags).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return tags, count, nil
+}
+
+func (sr *searchRepository) SearchCategories(searchTerm string, page, limit int) ([]models.CategorySearchResult, int64, error) {
+	pattern := "%" + escapeLike(searchTerm) + "%"
+
+	var count int64
+	if err := sr.DB.Raw("SELECT COUNT(*) FROM categories WHERE category ILIKE ?", pattern).Scan(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var categories []models.CategorySearchResult
+	err := sr.DB.Raw(`
+		SELECT c.id, c.category,
+			(SELECT COUNT(*) FROM videos v WHERE v.category_id = c.id AND v.taken_down_at IS NULL) AS videos
+		FROM categories c
+		WHERE c.category ILIKE ?
+		ORDER BY videos DESC, c.category
+		LIMIT ? OFFSET ?`, pattern, limit, (page-1)*limit).Scan(&categories).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return categories, count, nil
+}
+
+// VideoFacets counts the matching videos for every value of every filter
+func (sr *searchRepository) VideoFacets(filter models.SearchFilter) (models.SearchFacets, error) {
+	var facets models.SearchFacets
+
+	// Upload date buckets overlap, so each one is counted separately
+	for _, bucket := range uploadDateBuckets {
+		query, err := sr.videoSearchQuery(filter, "upload_date")
+		if err != nil {
+			return models.SearchFacets{}, err
+		}
+		var count int64
+		if err := query.Where("videos.created_at >= ?", time.Now().Add(-bucket.Within)).Count(&count).Error; err != nil {
+			return models.SearchFacets{}, err
+		}
+		facets.UploadDate = append(facets.UploadDate, models.FacetCount{Value: bucket.Value, Label: bucket.Label, Count: count})
+	}
+
+	for _, bucket := range durationBuckets {
+		query, err := sr.videoSearchQuery(filter, "duration")
+		if err != nil {
+			return models.SearchFacets{}, err
+		}
+		query = query.Where("videos.duration >= ?", bucket.Min)
+		if bucket.Max > 0 {
+			query = query.Where("videos.duration < ?", bucket.Max)
+		}
+		var count int64
+		if err := query.Count(&count).Error; err != nil {
+			return models.SearchFacets{}, err
+		}
+		facets.Duration = append(facets.Duration, models.FacetCount{Value: bucket.Value, Label: bucket.Label, Count: count})
+	}
+
+	var grouped []struct {
+		ID    int
+		Label string
+		Count int64
+	}
+
+	query, err := sr.videoSearchQuery(filter, "category")
+	if err != nil {
+		return models.SearchFacets{}, err
+	}
+	if err := query.Select("COALESCE(categories.id, 0) AS id, COALESCE(categories.category, '') AS label, COUNT(*) AS count").
+		Group("categories.id, categories.category").
+		Order("count DESC").
+		Scan(&grouped).Error; err != nil {
+		return models.SearchFacets{}, err
+	}
+	for _, row := range grouped {
+		facets.Category = append(facets.Category, models.FacetCount{Value: strconv.Itoa(row.ID), Label: row.Label, Count: row.Count})
+	}
+
+	var access []struct {
+		Exclusive bool
+		Count     int64
+	}
+	query, err = sr.videoSearchQuery(filter, "access")
+	if err != nil {
+		return models.SearchFacets{}, err
+	}
+	if err := query.Select("videos.exclusive, COUNT(*) AS count").Group("videos.exclusive").Scan(&access).Error; err != nil {
+		return models.SearchFacets{}, err
+	}
+	facets.Access = []models.FacetCount{{Value: "free", Label: "Free"}, {Value: "exclusive", Label: "Exclusive"}}
+	for _, row := range access {
+		if row.Exclusive {
+			facets.Access[1].Count = row.Count
+		} else {
+			facets.Access[0].Count = row.Count
+		}
+	}
+
+	grouped = nil
+	query, err = sr.videoSearchQuery(filter, "creator")
+	if err != nil {
+		return models.SearchFacets{}, err
+	}
+	if err := query.Select("COALESCE(users.id, 0) AS id, COALESCE(users.username, '') AS label, COUNT(*) AS count").
+		Group("users.id, users.username").
+		Order("count DESC").
+		Limit(10).
+		Scan(&grouped).Error; err != nil {
+		return models.SearchFacets{}, err
+	}
+	for _, row := range grouped {
+		facets.Creator = append(facets.Creator, models.FacetCount{Value: strconv.Itoa(row.ID), Label: row.Label, Count: row.Count})
+	}
+
+	return facets, nil
+}
+
+// escapeLike escapes the LIKE wildcards in user input so it is matched literally
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(term)
+}
+
+func (sr *searchRepository) SuggestTitles(prefix string, limit int) ([]models.Suggestion, error) {
+	var suggestions []models.Suggestion
+	err := sr.DB.Raw(`
+		SELECT 'video' AS type, id, title AS text, views AS popularity
+		FROM videos
+		WHERE title ILIKE ? AND taken_down_at IS NULL
+		ORDER BY views DESC, title
+		LIMIT ?`, escapeLike(prefix)+"%", limit).Scan(&suggestions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
+
+func (sr *searchRepository) SuggestCreators(prefix string, limit int) ([]models.Suggestion, error) {
+	var suggestions []models.Suggestion
+	err := sr.DB.Raw(`
+		SELECT 'creator' AS type, u.id, u.username AS text,
+			(SELECT COUNT(*) FROM follows f WHERE f.following_id = u.id) AS popularity
+		FROM users u
+		WHERE u.username ILIKE ?
+		ORDER BY popularity DESC, u.username
+		LIMIT ?`, escapeLike(prefix)+"%", limit).Scan(&suggestions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
+
+func (sr *searchRepository) SuggestTags(prefix string, limit int) ([]models.Suggestion, error) {
+	var suggestions []models.Suggestion
+	err := sr.DB.Raw(`
+		SELECT 'tag' AS type, t.id, t.tag AS text,
+			(SELECT COUNT(*) FROM video_tags vt WHERE vt.tag_id = t.id) AS popularity
+		FROM tags t
+		WHERE t.tag ILIKE ?
+		ORDER BY popularity DESC, t.tag
+		LIMIT ?`, escapeLike(prefix)+"%", limit).Scan(&suggestions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
+
+// SimilarTerms returns the titles, usernames and tags closest to the term using pg_trgm similarity
+func (sr *searchRepository) SimilarTerms(term string, limit int) ([]string, error) {
+	var terms []string
+	err := sr.DB.Raw(`
+		SELECT text FROM (
+			SELECT title AS text, similarity(title, ?) AS score FROM videos WHERE title % ? AND taken_down_at IS NULL
+			UNION
+			SELECT username AS text, similarity(username, ?) AS score FROM users WHERE username % ?
+			UNION
+			SELECT tag AS text, similarity(tag, ?) AS score FROM tags WHERE tag % ?
+		) matches
+		GROUP BY text
+		ORDER BY MAX(score) DESC
+		LIMIT ?`, term, term, term, term, term, term, limit).Pluck("text", &terms).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return terms, nil
+}
+
+// GetSearchVocabulary returns the tags and usernames used for the Levenshtein fallback
+func (sr *searchRepository) GetSearchVocabulary(limit int) ([]string, error) {
+	var terms []string
+	err := sr.DB.Raw(`
+		SELECT tag AS text FROM tags
+		UNION
+		SELECT username AS text FROM users WHERE username <> ''
+		LIMIT ?`, limit).Pluck("text", &terms).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return terms, nil
+}
+
+func (sr *searchRepository) LogSearchQuery(query *domain.SearchQuery) error {
+	return sr.DB.Create(query).Error
+}
+
+func (sr *searchRepository) RecordSearchClick(searchID uint, userHash, clickedType string, clickedID int) error {
+	result := sr.DB.Exec("UPDATE search_queries SET clicked_type = ?, clicked_id = ?, clicked_at = ? WHERE id = ? AND user_hash = ?", clickedType, clickedID, time.Now(), searchID, userHash)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected < 1 {
+		return errors.New("no search with that ID exist")
+	}
+
+	return nil
+}
+
+const searchQueryStatSelect = `
+	SELECT normalized_query AS query,
+		COUNT(*) AS searches,
+		COUNT(DISTINCT user_hash) AS users,
+		AVG(result_count) AS avg_results,
+		COUNT(clicked_at) AS clicks,
+		COUNT(clicked_at)::float / COUNT(*) AS click_through_rate
+	FROM search_queries`
+
+func (sr *searchRepository) GetTopSearchQueries(from, to time.Time, limit int) ([]models.SearchQueryStat, error) {
+	var stats []models.SearchQueryStat
+	err := sr.DB.Raw(searchQueryStatSelect+`
+		WHERE created_at BETWEEN ? AND ?
+		GROUP BY normalized_query
+		ORDER BY searches DESC, users DESC
+		LIMIT ?`, from, to, limit).Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+func (sr *searchRepository) GetZeroResultQueries(from, to time.Time, limit int) ([]models.SearchQueryStat, error) {
+	var stats []models.SearchQueryStat
+	err := sr.DB.Raw(searchQueryStatSelect+`
+		WHERE created_at BETWEEN ? AND ? AND result_count = 0
+		GROUP BY normalized_query
+		ORDER BY searches DESC, users DESC
+		LIMIT ?`, from, to, limit).Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+func (sr *searchRepository) GetSearchTrends(interval string, from, to time.Time, query string) ([]models.SearchTrendPoint, error) {
+	switch interval {
+	case "hour", "day", "week", "month":
+	default:
+		return nil, errors.New("invalid interval")
+	}
+
+	sqlQuery := `
+		SELECT date_trunc(?, created_at) AS period,
+			COUNT(*) AS searches,
+			COUNT(DISTINCT user_hash) AS users,
+			COUNT(*) FILTER (WHERE result_count = 0) AS zero_results,
+			COUNT(clicked_at) AS clicks
+		FROM search_queries
+		WHERE created_at BETWEEN ? AND ?`
+	args := []interface{}{interval, from, to}
+
+	if query != "" {
+		sqlQuery += " AND normalized_query = ?"
+		args = append(args, query)
+	}
+	sqlQuery += " GROUP BY period ORDER BY period"
+
+	var trends []models.SearchTrendPoint
+	if err := sr.DB.Raw(sqlQuery, args...).Scan(&trends).Error; err != nil {
+		return nil, err
+	}
+
+	return trends, nil
+}
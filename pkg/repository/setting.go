@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"main/pkg/domain"
+	interfaces "main/pkg/repository/interface"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type settingRepository struct {
+	DB *gorm.DB
+}
+
+func NewSettingRepository(DB *gorm.DB) interfaces.SettingRepository {
+	return &settingRepository{DB}
+}
+
+// GetSettings returns the stored values of the keys, keys that were never set are left out.
+func (s *settingRepository) GetSettings(keys ...string) (map[string]string, error) {
+	var settings []domain.Setting
+	if err := s.DB.Where("key IN ?", keys).Find(&settings).Error; err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(settings))
+	for _, setting := range settings {
+		values[setting.Key] = setting.Value
+	}
+
+	return values, nil
+}
+
+// SetSettings stores all the values or none of them.
+func (s *settingRepository) SetSettings(values map[string]string) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		for key, value := range values {
+			setting := domain.Setting{Key: key, Value: value}
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "key"}},
+				DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+			}).Create(&setting).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -1,11 +1,15 @@
 package repository
 
 import (
+	"errors"
 	"main/pkg/domain"
+	"main/pkg/money"
 	interfaces "main/pkg/repository/interface"
+	"main/pkg/utils/models"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type SubscriptionRepository struct {
@@ -16,23 +20,14 @@ func NewsubscriptionRepository(DB *gorm.DB) interfaces.SubscriptionRepository {
 	return &SubscriptionRepository{DB}
 }
 
-func (r *SubscriptionRepository) GetSubscriptionListIDByPlanID(planID, creatorID, userID int) (int, error) {
-	// Check if the user already has an active subscription
-	// existingSubscription, err := r.GetActiveSubscription(planID, creatorID, userID)
-	// if err != nil {
-	// 	return 0, err
-	// }
-
-	// if existingSubscription != nil {
-	// 	return 0, errors.New("user already subscribed")
-	// }
-
-	// Create a new subscription
+// GetSubscriptionListIDByPlanID opens a checkout for the plan, the unique index on pending rows keeps it to one per creator.
+func (r *SubscriptionRepository) GetSubscriptionListIDByPlanID(planID, creatorID, userID int, kind string) (int, error) {
 	subscriptionList := domain.SubscriptionList{
 		CreatorID: creatorID,
 		UserID:    userID,
 		PlanID:    planID,
-		//IsActive:  true, // Assuming a new subscription is active by default
+		Status:    domain.SubscriptionPending,
+		Kind:      kind,
 	}
 
 	result := r.DB.Create(&subscriptionList)
@@ -44,11 +39,95 @@ func (r *SubscriptionRepository) GetSubscriptionListIDByPlanID(planID, creatorID
 	return subscriptionList.ID, nil
 }
 
+func (r *SubscriptionRepository) GetPendingSubscription(creatorID, userID int) (*domain.SubscriptionList, error) {
+	var subscription domain.SubscriptionList
+	err := r.DB.Where("creator_id = ? AND user_id = ? AND status = ?", creatorID, userID, domain.SubscriptionPending).First(&subscription).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+// UpdatePendingSubscription points an open checkout at another plan, the old gateway order is dropped since its amount no longer applies.
+func (r *SubscriptionRepository) UpdatePendingSubscription(subscriptionListID, planID int, kind string) error {
+	return r.DB.Exec(`UPDATE subscription_lists SET plan_id = ?, kind = ?, razor_order_id = '', amount = 0, currency = '', payment_status = 'Pending', created_at = ?
+		WHERE id = ? AND status = 'pending'`, planID, kind, time.Now(), subscriptionListID).Error
+}
+
+// ExpireStalePendingSubscriptions expires checkouts opened before the given time that were never paid.
+func (r *SubscriptionRepository) ExpireStalePendingSubscriptions(before time.Time) (int64, error) {
+	result := r.DB.Exec("UPDATE subscription_lists SET status = 'expired' WHERE status = 'pending' AND payment_status <> 'PAID' AND created_at < ?", before)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (r *SubscriptionRepository) GetPlan(planID int) (domain.SubscriptionPlan, error) {
+	var plan domain.SubscriptionPlan
+	if err := r.DB.First(&plan, planID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.SubscriptionPlan{}, errors.New("plan not found")
+		}
+		return domain.SubscriptionPlan{}, err
+	}
+
+	return plan, nil
+}
+
+// SetSubscriptionPricing keeps the coupon and tax of an open checkout, the discount is in the currency's minor unit.
+func (r *SubscriptionRepository) SetSubscriptionPricing(subscriptionListID int, couponID *uint, discount int64, tax models.TaxQuote) error {
+	return r.DB.Exec(`UPDATE subscription_lists SET coupon_id = ?, discount = ?, tax_name = ?, tax_rate = ?, tax_inclusive = ?, tax = ?
+		WHERE id = ? AND status = 'pending'`, couponID, discount, tax.Name, tax.Rate, tax.Inclusive, tax.Amount, subscriptionListID).Error
+}
+
+// GetRegionalPrice returns what buyers in the region pay for the plan, found is false when the plan has no price for it.
+func (r *SubscriptionRepository) GetRegionalPrice(planID int, region string) (money.Money, bool, error) {
+	var prices []domain.PlanPrice
+	if err := r.DB.Where("plan_id = ? AND region = ?", planID, region).Limit(1).Find(&prices).Error; err != nil {
+		return money.Money{}, false, err
+	}
+	if len(prices) == 0 {
+		return money.Money{}, false, nil
+	}
+
+	return prices[0].Price, true, nil
+}
+
+// HasSubscribedBefore tells if the user ever had a paid or trial subscription to the creator, to any creator when creatorID is 0.
+func (r *SubscriptionRepository) HasSubscribedBefore(userID, creatorID int) (bool, error) {
+	query := r.DB.Model(&domain.SubscriptionList{}).Where("user_id = ? AND payment_status IN ('PAID', 'TRIAL', 'GIFT', 'REFUNDED')", userID)
+	if creatorID != 0 {
+		query = query.Where("creator_id = ?", creatorID)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// StartTrial turns an open checkout into a trial subscription, it has no payment and ends like any other period.
+func (r *SubscriptionRepository) StartTrial(subscriptionListID int, periodStart, periodEnd time.Time) error {
+	return r.DB.Exec(`
+		UPDATE subscription_lists
+		SET payment_status = 'TRIAL', subscribed_at = ?, is_active = true, status = 'active', kind = 'trial', coupon_id = NULL, discount = 0,
+			current_period_start = ?, current_period_end = ?, cancel_at_period_end = false, renewal_reminder_sent_at = NULL
+		WHERE id = ? AND status = 'pending'`, time.Now(), periodStart, periodEnd, subscriptionListID).Error
+}
+
 func (r *SubscriptionRepository) GetActiveSubscription(creatorID, userID int) (*domain.SubscriptionList, error) {
 	var subscription domain.SubscriptionList
 
 	// Specify the fields you need in the SELECT statement
-	rows, err := r.DB.Raw("SELECT creator_id, user_id, plan_id, is_active FROM subscription_lists WHERE creator_id = ? AND user_id = ? AND is_active = true ORDER BY subscribed_at DESC LIMIT 1", creatorID, userID).Rows()
+	rows, err := r.DB.Raw("SELECT id, creator_id, user_id, plan_id, is_active, status, payment_status, current_period_start, current_period_end, cancel_at_period_end FROM subscription_lists WHERE creator_id = ? AND user_id = ? AND status IN ('active', 'past_due') ORDER BY subscribed_at DESC LIMIT 1", creatorID, userID).Rows()
 	if err != nil {
 		return nil, err
 	}
@@ -67,22 +146,6 @@ func (r *SubscriptionRepository) GetActiveSubscription(creatorID, userID int) (*
 	return &subscription, nil
 }
 
-// func (r *SubscriptionRepository) GetSubscriptionListIDByPlanID(planID, creatorID, userID int) (int, error) {
-// 	subscriptionList := domain.SubscriptionList{
-// 		CreatorID: creatorID,
-// 		UserID:    userID,
-// 		PlanID:    planID,
-// 	}
-
-// 	result := r.DB.Create(&subscriptionList)
-
-// 	if result.Error != nil {
-// 		return 0, result.Error
-// 	}
-
-// 	return subscriptionList.ID, nil
-// }
-
 func (p *SubscriptionRepository) FindUsername(user_id int) (string, error) {
 	var username string
 	if err := p.DB.Raw("SELECT username FROM users WHERE id=?", user_id).Scan(&username).Error; err != nil {
@@ -91,47 +154,316 @@ func (p *SubscriptionRepository) FindUsername(user_id int) (string, error) {
 
 	return username, nil
 }
-func (p *SubscriptionRepository) FindPrice(orderID int) (float64, error) {
-	// Step 1: Find the planID using the orderID
-	var planID int
-	if err := p.DB.Raw("SELECT plan_id FROM subscription_lists WHERE id=?", orderID).Scan(&planID).Error; err != nil {
-		return 0, err
+
+func (p *SubscriptionRepository) FindBillingCountry(userID int) (string, error) {
+	var country string
+	if err := p.DB.Raw("SELECT COALESCE(country, '') FROM users WHERE id = ?", userID).Scan(&country).Error; err != nil {
+		return "", err
+	}
+
+	return country, nil
+}
+
+// ActivateSubscription marks the subscription paid and starts its period, the subscription it renews or replaces is closed in the same transaction.
+// activated is false when the subscription was already paid, e.g. by the webhook racing the checkout, or refunded since.
+func (p *SubscriptionRepository) ActivateSubscription(subscriptionListID int, paymentID string, activation models.SubscriptionActivation) (bool, error) {
+	activated := false
+
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(`
+			UPDATE subscription_lists
+			SET payment_status = 'PAID', payment_id = ?, subscribed_at = ?, is_active = true, status = 'active', kind = ?, previous_subscription_id = ?,
+				current_period_start = ?, current_period_end = ?, cancel_at_period_end = false, renewal_reminder_sent_at = NULL
+			WHERE id = ? AND payment_status NOT IN ('PAID', 'REFUNDED')`,
+			paymentID, time.Now(), activation.Kind, activation.PreviousSubscriptionID, activation.PeriodStart, activation.PeriodEnd, subscriptionListID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		activated = true
+
+		// The coupon counts as redeemed once the subscription is paid, the limits were checked at checkout
+		redemption := tx.Exec(`
+			INSERT INTO coupon_redemptions (coupon_id, user_id, subscription_list_id, discount, created_at)
+			SELECT coupon_id, user_id, id, discount, ? FROM subscription_lists WHERE id = ? AND coupon_id IS NOT NULL
+			ON CONFLICT DO NOTHING`, time.Now(), subscriptionListID)
+		if redemption.Error != nil {
+			return redemption.Error
+		}
+		if redemption.RowsAffected > 0 {
+			if err := tx.Exec("UPDATE coupons SET redemption_count = redemption_count + 1 WHERE id = (SELECT coupon_id FROM subscription_lists WHERE id = ?)", subscriptionListID).Error; err != nil {
+				return err
+			}
+		}
+
+		if activation.PreviousSubscriptionID == nil {
+			return nil
+		}
+
+		return tx.Exec("UPDATE subscription_lists SET status = 'replaced', is_active = false WHERE id = ? AND status IN ('active', 'past_due')", *activation.PreviousSubscriptionID).Error
+	})
+
+	return activated, err
+}
+
+func (p *SubscriptionRepository) SetGatewayOrder(subscriptionListID int, razorOrderID string, amount int64, currency string) error {
+	return p.DB.Exec("UPDATE subscription_lists SET razor_order_id = ?, amount = ?, currency = ? WHERE id = ?", razorOrderID, amount, currency, subscriptionListID).Error
+}
+
+func (p *SubscriptionRepository) GetSubscriptionByID(subscriptionListID int) (domain.SubscriptionList, error) {
+	var subscription domain.SubscriptionList
+	if err := p.DB.First(&subscription, subscriptionListID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.SubscriptionList{}, errors.New("subscription not found")
+		}
+		return domain.SubscriptionList{}, err
+	}
+
+	return subscription, nil
+}
+
+func (p *SubscriptionRepository) GetSubscriptionByRazorOrderID(razorOrderID string) (domain.SubscriptionList, error) {
+	var subscription domain.SubscriptionList
+	if err := p.DB.Where("razor_order_id = ?", razorOrderID).First(&subscription).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.SubscriptionList{}, errors.New("no subscription for that order")
+		}
+		return domain.SubscriptionList{}, err
 	}
 
-	// Step 2: Find the price using the obtained planID
-	var price float64
-	if err := p.DB.Raw("SELECT price FROM subscription_plans WHERE id=?", planID).Scan(&price).Error; err != nil {
-		return 0, err
+	return subscription, nil
+}
+
+// MarkPaymentFailed records a failed payment unless the order has already been paid by another attempt.
+func (p *SubscriptionRepository) MarkPaymentFailed(razorOrderID string) error {
+	return p.DB.Exec("UPDATE subscription_lists SET payment_status = 'FAILED' WHERE razor_order_id = ? AND payment_status <> 'PAID'", razorOrderID).Error
+}
+
+func (p *SubscriptionRepository) GetSubscriptionByPaymentID(paymentID string) (domain.SubscriptionList, error) {
+	var subscription domain.SubscriptionList
+	if err := p.DB.Where("payment_id = ?", paymentID).First(&subscription).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.SubscriptionList{}, errors.New("no subscription for that payment")
+		}
+		return domain.SubscriptionList{}, err
 	}
 
-	return price, nil
+	return subscription, nil
 }
 
-// func (p *SubscriptionRepository) UpdatePaymentDetails(orderID, paymentID, razorID string) error {
-// 	status := "PAID"
-// 	subscribedAt := time.Now() // Update the SubscribedAt field to the current time
+// RecordRefund stores the refund and adds it to the subscription's refunded amount, created is false when it was already recorded.
+// A payment refunded in full is marked refunded and the subscription is not renewed.
+func (p *SubscriptionRepository) RecordRefund(refund *domain.PaymentRefund) (bool, error) {
+	created := false
 
-// 	if err := p.DB.Exec(`
-// 		UPDATE subscription_lists
-// 		SET payment_status = $1, payment_id = $3, subscribed_at = $4
-// 		WHERE id = $2`, status, orderID, paymentID, subscribedAt).Error; err != nil {
-// 		return err
-// 	}
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "refund_id"}}, DoNothing: true}).Create(refund)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		created = true
 
-//		return nil
-//	}
-func (p *SubscriptionRepository) UpdatePaymentDetails(orderID, paymentID, razorID string) error {
-	status := "PAID"
-	subscribedAt := time.Now() // Update the SubscribedAt field to the current time
+		return tx.Exec(`UPDATE subscription_lists SET amount_refunded = amount_refunded + ?,
+				payment_status = CASE WHEN amount_refunded + ? >= amount THEN 'REFUNDED' ELSE payment_status END,
+				cancel_at_period_end = cancel_at_period_end OR amount_refunded + ? >= amount
+			WHERE id = ?`, refund.Amount, refund.Amount, refund.Amount, refund.SubscriptionListID).Error
+	})
+
+	return created, err
+}
+
+// RevokeSubscription ends the subscriber's access right away.
+func (p *SubscriptionRepository) RevokeSubscription(subscriptionListID int) error {
+	return p.DB.Exec("UPDATE subscription_lists SET status = 'cancelled', is_active = false, cancelled_at = ? WHERE id = ? AND status IN ('active', 'past_due', 'suspended')",
+		time.Now(), subscriptionListID).Error
+}
+
+// SuspendSubscription takes access away while the payment is disputed, suspended is false when the subscription had no access.
+func (p *SubscriptionRepository) SuspendSubscription(subscriptionListID int) (bool, error) {
+	result := p.DB.Exec("UPDATE subscription_lists SET status = 'suspended', is_active = false WHERE id = ? AND status IN ('active', 'past_due')", subscriptionListID)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// SaveDispute stores the dispute or updates its status and amount, created is false when it was already stored.
+func (p *SubscriptionRepository) SaveDispute(dispute *domain.PaymentDispute) (bool, error) {
+	result := p.DB.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "dispute_id"}}, DoNothing: true}).Create(dispute)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return true, nil
+	}
+
+	err := p.DB.Model(&domain.PaymentDispute{}).Where("dispute_id = ?", dispute.DisputeID).
+		Updates(map[string]interface{}{"status": dispute.Status, "amount": dispute.Amount, "reason_code": dispute.ReasonCode}).Error
+	if err != nil {
+		return false, err
+	}
+
+	return false, p.DB.Where("dispute_id = ?", dispute.DisputeID).First(dispute).Error
+}
+
+func (p *SubscriptionRepository) GetDispute(disputeID uint) (domain.PaymentDispute, error) {
+	var dispute domain.PaymentDispute
+	if err := p.DB.First(&dispute, disputeID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.PaymentDispute{}, errors.New("dispute not found")
+		}
+		return domain.PaymentDispute{}, err
+	}
+
+	return dispute, nil
+}
+
+// ListDisputes returns the newest disputes first, only those waiting for a review when needsReview is set.
+func (p *SubscriptionRepository) ListDisputes(needsReview bool, page, limit int) ([]domain.PaymentDispute, error) {
+	var disputes []domain.PaymentDispute
+
+	query := p.DB.Order("id DESC").Offset((page - 1) * limit).Limit(limit)
+	if needsReview {
+		query = query.Where("needs_review = ?", true)
+	}
+
+	if err := query.Find(&disputes).Error; err != nil {
+		return nil, err
+	}
+
+	return disputes, nil
+}
+
+func (p *SubscriptionRepository) MarkDisputeReviewed(disputeID uint, note string) error {
+	return p.DB.Model(&domain.PaymentDispute{}).Where("id = ?", disputeID).
+		Updates(map[string]interface{}{"needs_review": false, "review_note": note, "reviewed_at": time.Now()}).Error
+}
+
+// StorePaymentEvent saves a webhook, created is false when an event with the same ID was already stored.
+func (p *SubscriptionRepository) StorePaymentEvent(event *domain.PaymentEvent) (bool, error) {
+	result := p.DB.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "event_id"}}, DoNothing: true}).Create(event)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		if err := p.DB.Where("event_id = ?", event.EventID).First(event).Error; err != nil {
+			return false, err
+		}
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (p *SubscriptionRepository) GetPaymentEvent(eventID uint) (domain.PaymentEvent, error) {
+	var event domain.PaymentEvent
+	if err := p.DB.First(&event, eventID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.PaymentEvent{}, errors.New("payment event not found")
+		}
+		return domain.PaymentEvent{}, err
+	}
+
+	return event, nil
+}
+
+func (p *SubscriptionRepository) UpdatePaymentEventStatus(eventID uint, status, errMsg string) error {
+	now := time.Now()
+	return p.DB.Model(&domain.PaymentEvent{}).Where("id = ?", eventID).Updates(map[string]interface{}{
+		"status":       status,
+		"error":        errMsg,
+		"attempts":     gorm.Expr("attempts + 1"),
+		"processed_at": &now,
+	}).Error
+}
+
+func (p *SubscriptionRepository) ListPaymentEvents(status string, page, limit int) ([]domain.PaymentEvent, error) {
+	var events []domain.PaymentEvent
 
-	if err := p.DB.Exec(`
-		UPDATE subscription_lists 
-		SET payment_status = $1, payment_id = $3, subscribed_at = $4, is_active = true
-		WHERE id = $2`, status, orderID, paymentID, subscribedAt).Error; err != nil {
-		return err
+	query := p.DB.Order("id DESC").Offset((page - 1) * limit).Limit(limit)
+	if status != "" {
+		query = query.Where("status = ?", status)
 	}
 
-	return nil
+	if err := query.Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// GetSubscriptionsForRenewalReminder returns the active subscriptions ending before the given time that have not been reminded yet.
+func (p *SubscriptionRepository) GetSubscriptionsForRenewalReminder(before time.Time) ([]domain.SubscriptionList, error) {
+	var subscriptions []domain.SubscriptionList
+	err := p.DB.Where("status = ? AND cancel_at_period_end = false AND renewal_reminder_sent_at IS NULL AND current_period_end > ? AND current_period_end <= ?", domain.SubscriptionActive, time.Now(), before).
+		Find(&subscriptions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+func (p *SubscriptionRepository) MarkRenewalReminderSent(subscriptionListID int) error {
+	return p.DB.Exec("UPDATE subscription_lists SET renewal_reminder_sent_at = ? WHERE id = ?", time.Now(), subscriptionListID).Error
+}
+
+// GetSubscriptionsEndedBefore returns the subscriptions in the status whose current period ended before the given time.
+func (p *SubscriptionRepository) GetSubscriptionsEndedBefore(status string, before time.Time) ([]domain.SubscriptionList, error) {
+	var subscriptions []domain.SubscriptionList
+	if err := p.DB.Where("status = ? AND current_period_end <= ?", status, before).Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+// SetSubscriptionStatus moves the subscription to the status, the subscriber keeps access while it is active or past due.
+// Only a subscription still in the expected status is changed, so a payment arriving meanwhile is not overwritten.
+func (p *SubscriptionRepository) SetSubscriptionStatus(subscriptionListID int, from, to string) (bool, error) {
+	isActive := to == domain.SubscriptionActive || to == domain.SubscriptionPastDue
+
+	updates := map[string]interface{}{
+		"status":    to,
+		"is_active": isActive,
+	}
+	if to == domain.SubscriptionCancelled {
+		updates["cancelled_at"] = time.Now()
+	}
+
+	result := p.DB.Model(&domain.SubscriptionList{}).Where("id = ? AND status = ?", subscriptionListID, from).Updates(updates)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (p *SubscriptionRepository) SetCancelAtPeriodEnd(subscriptionListID int, cancel bool) error {
+	return p.DB.Exec("UPDATE subscription_lists SET cancel_at_period_end = ? WHERE id = ?", cancel, subscriptionListID).Error
+}
+
+func (p *SubscriptionRepository) ListUserSubscriptions(userID int) ([]models.UserSubscription, error) {
+	var subscriptions []models.UserSubscription
+
+	query := `
+		SELECT s.id, s.creator_id, COALESCE(u.username, '') AS creator, s.plan_id, COALESCE(sp.name, '') AS plan,
+			s.status, s.kind, s.payment_status, s.subscribed_at, s.current_period_start, s.current_period_end, s.cancel_at_period_end
+		FROM subscription_lists s
+		LEFT JOIN users u ON u.id = s.creator_id
+		LEFT JOIN subscription_plans sp ON sp.id = s.plan_id
+		WHERE s.user_id = ? AND s.status <> 'pending'
+		ORDER BY s.id DESC`
+
+	if err := p.DB.Raw(query, userID).Scan(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
 }
 
 // func (s *SubscriptionRepository) FetchActiveSubscriptions() ([]domain.SubscriptionList, error) {
@@ -170,30 +502,10 @@ func (p *SubscriptionRepository) UpdatePaymentDetails(orderID, paymentID, razorI
 
 //		return nil
 //	}
-func (r *SubscriptionRepository) GetRevenue(creatorID int, startDate string, endDate string) (float64, error) {
-	var revenue float64
-
-	query := r.DB.
-		Model(&domain.SubscriptionList{}).
-		Select("COALESCE(SUM(subscription_plans.price), 0)").
-		Joins("JOIN subscription_plans ON subscription_lists.plan_id = subscription_plans.id").
-		Where("subscription_lists.creator_id = ?", creatorID)
-
-	if startDate != "" && endDate != "" {
-		query = query.Where("subscription_lists.subscribed_at BETWEEN ? AND ?", startDate, endDate)
-	}
-
-	result := query.Scan(&revenue)
-	if result.Error != nil {
-		return 0, result.Error
-	}
-
-	return revenue, nil
-}
 func (r *SubscriptionRepository) GetSubscribersCount(creatorID int, startDate string, endDate string) (int, error) {
 	var count int
 
-	query := "SELECT COUNT(id) FROM subscription_lists WHERE creator_id = ?"
+	query := "SELECT COUNT(id) FROM subscription_lists WHERE creator_id = ? AND payment_status = 'PAID'"
 	args := []interface{}{creatorID}
 
 	// Check if start and end date are provided
@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"errors"
+	"main/pkg/domain"
+	interfaces "main/pkg/repository/interface"
+	"main/pkg/utils/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type tagRepository struct {
+	DB *gorm.DB
+}
+
+func NewTagRepository(DB *gorm.DB) interfaces.TagRepository {
+	return &tagRepository{DB}
+}
+
+// normalizeTagName lowercases the tag and collapses the whitespace, so "Battle  Royale" and "battle royale" are the same tag
+func normalizeTagName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
+
+// findTag looks up a canonical tag by name, ignoring case
+func findTag(db *gorm.DB, name string) (domain.Tag, bool, error) {
+	var tags []domain.Tag
+	if err := db.Where("LOWER(tag) = ?", normalizeTagName(name)).Order("id").Limit(1).Find(&tags).Error; err != nil {
+		return domain.Tag{}, false, err
+	}
+	if len(tags) == 0 {
+		return domain.Tag{}, false, nil
+	}
+
+	return tags[0], true, nil
+}
+
+// resolveTag returns the canonical tag for a name, following aliases
+func resolveTag(db *gorm.DB, name string) (domain.Tag, bool, error) {
+	tag, found, err := findTag(db, name)
+	if err != nil || found {
+		return tag, found, err
+	}
+
+	var tags []domain.Tag
+	err = db.Raw("SELECT t.* FROM tags t JOIN tag_aliases a ON a.tag_id = t.id WHERE a.alias = ? LIMIT 1", normalizeTagName(name)).Scan(&tags).Error
+	if err != nil {
+		return domain.Tag{}, false, err
+	}
+	if len(tags) == 0 {
+		return domain.Tag{}, false, nil
+	}
+
+	return tags[0], true, nil
+}
+
+func isTagBanned(db *gorm.DB, name string) (bool, error) {
+	var count int64
+	if err := db.Model(&domain.BannedTag{}).Where("tag = ?", normalizeTagName(name)).Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (tr *tagRepository) GetTagByID(tagID uint) (domain.Tag, error) {
+	var tag domain.Tag
+	if err := tr.DB.First(&tag, tagID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Tag{}, errors.New("tag not found")
+		}
+		return domain.Tag{}, err
+	}
+
+	return tag, nil
+}
+
+func (tr *tagRepository) FindTagByName(name string) (domain.Tag, bool, error) {
+	return findTag(tr.DB, name)
+}
+
+func (tr *tagRepository) ResolveTag(name string) (domain.Tag, bool, error) {
+	return resolveTag(tr.DB, name)
+}
+
+func (tr *tagRepository) AddAlias(tagID uint, alias string) (domain.TagAlias, error) {
+	tagAlias := domain.TagAlias{
+		Alias: normalizeTagName(alias),
+		TagID: tagID,
+	}
+
+	if err := tr.DB.Create(&tagAlias).Error; err != nil {
+		return domain.TagAlias{}, err
+	}
+
+	return tagAlias, nil
+}
+
+func (tr *tagRepository) DeleteAlias(aliasID uint) error {
+	result := tr.DB.Where("id = ?", aliasID).Delete(&domain.TagAlias{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected < 1 {
+		return errors.New("no alias with that ID exist")
+	}
+
+	return nil
+}
+
+func (tr *tagRepository) GetAliases(tagID uint) ([]domain.TagAlias, error) {
+	var aliases []domain.TagAlias
+	if err := tr.DB.Where("tag_id = ?", tagID).Order("alias").Find(&aliases).Error; err != nil {
+		return nil, err
+	}
+
+	return aliases, nil
+}
+
+// MergeTags moves the videos, followers and aliases of the source tag to the target tag and deletes the source,
+// its name is kept as an alias of the target so creators using it still land on the target tag.
+func (tr *tagRepository) MergeTags(sourceID, targetID uint) (models.TagMergeResult, error) {
+	result := models.TagMergeResult{Target: targetID}
+
+	err := tr.DB.Transaction(func(tx *gorm.DB) error {
+		var source, target domain.Tag
+		if err := tx.First(&source, sourceID).Error; err != nil {
+			return errors.New("tag to merge not found")
+		}
+		if err := tx.First(&target, targetID).Error; err != nil {
+			return errors.New("target tag not found")
+		}
+
+		// Video tags, including the ones stored before they were linked to a tag ID
+		videoTags := tx.Exec(`UPDATE video_tags SET tag_id = ?, tag = ?
+			WHERE tag_id = ? OR (tag_id IS NULL AND LOWER(tag) = LOWER(?))`, target.ID, target.Tag, source.ID, source.Tag)
+		if videoTags.Error != nil {
+			return videoTags.Error
+		}
+		result.VideoTags = videoTags.RowsAffected
+
+		// A video tagged with both ends up with the target tag twice
+		if err := tx.Exec(`DELETE FROM video_tags a USING video_tags b
+			WHERE a.tag_id = ? AND b.tag_id = a.tag_id AND b.video_id = a.video_id AND a.id > b.id`, target.ID).Error; err != nil {
+			return err
+		}
+
+		// Users following both keep a single follow of the target tag
+		if err := tx.Exec(`DELETE FROM user_tags s WHERE s.tag_id = ?
+			AND EXISTS (SELECT 1 FROM user_tags t WHERE t.user_id = s.user_id AND t.tag_id = ?)`, source.ID, target.ID).Error; err != nil {
+			return err
+		}
+		userTags := tx.Exec("UPDATE user_tags SET tag_id = ? WHERE tag_id = ?", target.ID, source.ID)
+		if userTags.Error != nil {
+			return userTags.Error
+		}
+		result.UserTags = userTags.RowsAffected
+
+		aliases := tx.Exec("UPDATE tag_aliases SET tag_id = ? WHERE tag_id = ?", target.ID, source.ID)
+		if aliases.Error != nil {
+			return aliases.Error
+		}
+		result.Aliases = aliases.RowsAffected
+
+		if name := normalizeTagName(source.Tag); name != normalizeTagName(target.Tag) {
+			alias := tx.Exec("INSERT INTO tag_aliases (alias, tag_id) VALUES (?, ?) ON CONFLICT (alias) DO UPDATE SET tag_id = EXCLUDED.tag_id", name, target.ID)
+			if alias.Error != nil {
+				return alias.Error
+			}
+			result.Aliases += alias.RowsAffected
+		}
+
+		return tx.Delete(&domain.Tag{}, source.ID).Error
+	})
+	if err != nil {
+		return models.TagMergeResult{}, err
+	}
+
+	return result, nil
+}
+
+func (tr *tagRepository) IsTagBanned(name string) (bool, error) {
+	return isTagBanned(tr.DB, name)
+}
+
+// BanTag adds the tag to the banned list and removes the canonical tag of that name from every video and user.
+func (tr *tagRepository) BanTag(name, reason string) (domain.BannedTag, error) {
+	banned := domain.BannedTag{
+		Tag:    normalizeTagName(name),
+		Reason: reason,
+	}
+
+	err := tr.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&banned).Error; err != nil {
+			return err
+		}
+
+		tag, found, err := findTag(tx, banned.Tag)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Exec("DELETE FROM video_tags WHERE LOWER(tag) = ? OR tag_id = ?", banned.Tag, tag.ID).Error; err != nil {
+			return err
+		}
+
+		if !found {
+			return tx.Where("alias = ?", banned.Tag).Delete(&domain.TagAlias{}).Error
+		}
+
+		if err := tx.Where("tag_id = ?", tag.ID).Delete(&domain.UserTags{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&domain.Tag{}, tag.ID).Error
+	})
+	if err != nil {
+		return domain.BannedTag{}, err
+	}
+
+	return banned, nil
+}
+
+func (tr *tagRepository) UnbanTag(bannedID uint) error {
+	result := tr.DB.Where("id = ?", bannedID).Delete(&domain.BannedTag{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected < 1 {
+		return errors.New("no banned tag with that ID exist")
+	}
+
+	return nil
+}
+
+func (tr *tagRepository) GetBannedTags(page, limit int) ([]domain.BannedTag, error) {
+	var banned []domain.BannedTag
+	if err := tr.DB.Order("tag").Offset((page - 1) * limit).Limit(limit).Find(&banned).Error; err != nil {
+		return nil, err
+	}
+
+	return banned, nil
+}
+
+// GetTagStats returns the canonical tags ordered by the number of videos using them.
+func (tr *tagRepository) GetTagStats(page, limit int) ([]models.TagStat, error) {
+	var stats []models.TagStat
+
+	query := `
+		SELECT t.id, t.tag,
+			(SELECT COUNT(DISTINCT vt.video_id) FROM video_tags vt WHERE vt.tag_id = t.id) AS videos,
+			(SELECT COUNT(*) FROM user_tags ut WHERE ut.tag_id = t.id) AS followers,
+			(SELECT COUNT(*) FROM tag_aliases a WHERE a.tag_id = t.id) AS aliases
+		FROM tags t
+		ORDER BY videos DESC, followers DESC, t.tag
+		LIMIT ? OFFSET ?`
+
+	if err := tr.DB.Raw(query, limit, (page-1)*limit).Scan(&stats).Error; err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
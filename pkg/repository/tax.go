@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"main/pkg/domain"
+	interfaces "main/pkg/repository/interface"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type taxRepository struct {
+	DB *gorm.DB
+}
+
+func NewTaxRepository(DB *gorm.DB) interfaces.TaxRepository {
+	return &taxRepository{DB}
+}
+
+func (t *taxRepository) ListTaxRules() ([]domain.TaxRule, error) {
+	var rules []domain.TaxRule
+	if err := t.DB.Order("region").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// SaveTaxRule creates the rule of the region or replaces it.
+func (t *taxRepository) SaveTaxRule(rule *domain.TaxRule) error {
+	return t.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "region"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "rate", "inclusive", "updated_at"}),
+	}).Create(rule).Error
+}
+
+// DeleteTaxRule removes the rule of the region, deleted is false when it had none.
+func (t *taxRepository) DeleteTaxRule(region string) (bool, error) {
+	result := t.DB.Where("region = ?", region).Delete(&domain.TaxRule{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetTaxRule returns the rule of the region, or the default rule when the region has none. Found is false when neither exists.
+func (t *taxRepository) GetTaxRule(region string) (domain.TaxRule, bool, error) {
+	var rules []domain.TaxRule
+	// false sorts first, so the region's own rule wins over the default one
+	err := t.DB.Where("region IN ?", []string{region, domain.TaxRegionDefault}).
+		Order("region = '" + domain.TaxRegionDefault + "'").Limit(1).Find(&rules).Error
+	if err != nil {
+		return domain.TaxRule{}, false, err
+	}
+	if len(rules) == 0 {
+		return domain.TaxRule{}, false, nil
+	}
+
+	return rules[0], true, nil
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"errors"
+	"main/pkg/domain"
+	interfaces "main/pkg/repository/interface"
+	"main/pkg/utils/models"
+
+	"gorm.io/gorm"
+)
+
+type tierRepository struct {
+	DB *gorm.DB
+}
+
+func NewTierRepository(DB *gorm.DB) interfaces.TierRepository {
+	return &tierRepository{DB}
+}
+
+func (t *tierRepository) CreateTier(tier *domain.SubscriptionPlan) error {
+	return t.DB.Create(tier).Error
+}
+
+func (t *tierRepository) GetTier(tierID int) (domain.SubscriptionPlan, error) {
+	var tier domain.SubscriptionPlan
+	if err := t.DB.Where("id = ? AND archived = false", tierID).First(&tier).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.SubscriptionPlan{}, errors.New("tier not found")
+		}
+		return domain.SubscriptionPlan{}, err
+	}
+
+	return tier, nil
+}
+
+func (t *tierRepository) UpdateTier(tier domain.SubscriptionPlan) error {
+	return t.DB.Model(&domain.SubscriptionPlan{}).Where("id = ?", tier.ID).Updates(map[string]interface{}{
+		"name":           tier.Name,
+		"duration":       tier.Duration,
+		"price_amount":   tier.Price.Amount,
+		"price_currency": tier.Price.Currency,
+		"perks":          tier.Perks,
+		"trial_days":     tier.TrialDays,
+	}).Error
+}
+
+// DeleteTier removes the tier, a tier that subscriptions refer to is archived instead so their history stays intact.
+// archived tells which of the two happened.
+func (t *tierRepository) DeleteTier(tierID int) (bool, error) {
+	archived := false
+
+	err := t.DB.Transaction(func(tx *gorm.DB) error {
+		var subscriptions int64
+		if err := tx.Model(&domain.SubscriptionList{}).Where("plan_id = ?", tierID).Count(&subscriptions).Error; err != nil {
+			return err
+		}
+
+		if subscriptions > 0 {
+			archived = true
+			if err := tx.Where("plan_id = ?", tierID).Delete(&domain.TierVideo{}).Error; err != nil {
+				return err
+			}
+			return tx.Model(&domain.SubscriptionPlan{}).Where("id = ?", tierID).Update("archived", true).Error
+		}
+
+		return tx.Delete(&domain.SubscriptionPlan{}, tierID).Error
+	})
+
+	return archived, err
+}
+
+// ListCreatorTiers returns the creator's tiers cheapest first, with the number of current subscribers of each.
+func (t *tierRepository) ListCreatorTiers(creatorID int) ([]models.Tier, error) {
+	var tiers []models.Tier
+
+	query := `
+		SELECT p.id, p.creator_id, p.name, p.duration, p.price_amount, p.price_currency, p.perks, p.trial_days,
+			(SELECT COUNT(*) FROM subscription_lists s WHERE s.plan_id = p.id AND s.status IN ('active', 'past_due')) AS subscribers
+		FROM subscription_plans p
+		WHERE p.creator_id = ? AND p.archived = false
+		ORDER BY p.price_amount, p.id`
+
+	if err := t.DB.Raw(query, creatorID).Scan(&tiers).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range tiers {
+		videoIDs, err := t.GetTierVideoIDs(tiers[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		tiers[i].VideoIDs = videoIDs
+	}
+
+	return tiers, nil
+}
+
+func (t *tierRepository) GetTierVideoIDs(tierID int) ([]uint, error) {
+	videoIDs := []uint{}
+	if err := t.DB.Model(&domain.TierVideo{}).Where("plan_id = ?", tierID).Order("video_id").Pluck("video_id", &videoIDs).Error; err != nil {
+		return nil, err
+	}
+
+	return videoIDs, nil
+}
+
+// SetTierVideos replaces the videos the tier unlocks.
+func (t *tierRepository) SetTierVideos(tierID int, videoIDs []uint) error {
+	return t.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("plan_id = ?", tierID).Delete(&domain.TierVideo{}).Error; err != nil {
+			return err
+		}
+
+		for _, videoID := range videoIDs {
+			if err := tx.Create(&domain.TierVideo{PlanID: tierID, VideoID: videoID}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (t *tierRepository) GetCreatorVideos(creatorID int, videoIDs []uint) ([]domain.Video, error) {
+	var videos []domain.Video
+	if err := t.DB.Where("user_id = ? AND id IN ?", creatorID, videoIDs).Find(&videos).Error; err != nil {
+		return nil, err
+	}
+
+	return videos, nil
+}
+
+func (t *tierRepository) GetTierPrices(tierID int) ([]domain.PlanPrice, error) {
+	var prices []domain.PlanPrice
+	if err := t.DB.Where("plan_id = ?", tierID).Order("region").Find(&prices).Error; err != nil {
+		return nil, err
+	}
+
+	return prices, nil
+}
+
+// SetTierPrices replaces the regional prices of the tier.
+func (t *tierRepository) SetTierPrices(tierID int, prices []domain.PlanPrice) error {
+	return t.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("plan_id = ?", tierID).Delete(&domain.PlanPrice{}).Error; err != nil {
+			return err
+		}
+		if len(prices) == 0 {
+			return nil
+		}
+
+		for i := range prices {
+			prices[i].PlanID = tierID
+		}
+		return tx.Create(&prices).Error
+	})
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"errors"
+	"main/pkg/domain"
+	"main/pkg/money"
+	interfaces "main/pkg/repository/interface"
+	"main/pkg/utils/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type tipRepository struct {
+	DB *gorm.DB
+}
+
+func NewTipRepository(DB *gorm.DB) interfaces.TipRepository {
+	return &tipRepository{DB}
+}
+
+func (t *tipRepository) CreateTip(tip *domain.Tip) error {
+	return t.DB.Create(tip).Error
+}
+
+// SetTipOrder keeps the gateway order of the tip so the checkout and webhooks can be matched back to it.
+func (t *tipRepository) SetTipOrder(tipID uint, razorOrderID string) error {
+	return t.DB.Model(&domain.Tip{}).Where("id = ?", tipID).Update("razor_order_id", razorOrderID).Error
+}
+
+func (t *tipRepository) GetTip(tipID uint) (domain.Tip, error) {
+	var tip domain.Tip
+	if err := t.DB.First(&tip, tipID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Tip{}, errors.New("tip not found")
+		}
+		return domain.Tip{}, err
+	}
+
+	return tip, nil
+}
+
+func (t *tipRepository) FindTipByRazorOrderID(razorOrderID string) (domain.Tip, bool, error) {
+	return t.findTip("razor_order_id = ?", razorOrderID)
+}
+
+func (t *tipRepository) FindTipByPaymentID(paymentID string) (domain.Tip, bool, error) {
+	return t.findTip("payment_id = ?", paymentID)
+}
+
+func (t *tipRepository) findTip(query string, value string) (domain.Tip, bool, error) {
+	if value == "" {
+		return domain.Tip{}, false, nil
+	}
+
+	var tips []domain.Tip
+	if err := t.DB.Where(query, value).Limit(1).Find(&tips).Error; err != nil {
+		return domain.Tip{}, false, err
+	}
+	if len(tips) == 0 {
+		return domain.Tip{}, false, nil
+	}
+
+	return tips[0], true, nil
+}
+
+// MarkTipPaid records the payment of the tip, paid is false when it was already paid, e.g. by the webhook racing the checkout.
+func (t *tipRepository) MarkTipPaid(tipID uint, paymentID string) (bool, error) {
+	result := t.DB.Exec("UPDATE tips SET status = 'paid', payment_id = ?, paid_at = ? WHERE id = ? AND status = 'pending'", paymentID, time.Now(), tipID)
+	return result.RowsAffected > 0, result.Error
+}
+
+// AddTipRefund adds a refund to the tip, a tip refunded in full is no longer shown.
+func (t *tipRepository) AddTipRefund(tipID uint, amount int64) error {
+	return t.DB.Exec(`UPDATE tips SET refunded = refunded + ?,
+		status = CASE WHEN refunded + ? >= amount THEN 'refunded' ELSE status END
+		WHERE id = ?`, amount, amount, tipID).Error
+}
+
+func (t *tipRepository) FindUsername(userID int) (string, error) {
+	var username string
+	if err := t.DB.Raw("SELECT username FROM users WHERE id = ?", userID).Scan(&username).Error; err != nil {
+		return "", err
+	}
+
+	return username, nil
+}
+
+// GetVideoOwner returns the user who uploaded the video.
+func (t *tipRepository) GetVideoOwner(videoID uint) (int, error) {
+	var video domain.Video
+	if err := t.DB.Select("id", "user_id").Where("taken_down_at IS NULL").First(&video, videoID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, errors.New("video not found")
+		}
+		return 0, err
+	}
+
+	return int(video.UserID), nil
+}
+
+func (t *tipRepository) GetComment(commentID uint) (domain.Comment, error) {
+	var comment domain.Comment
+	if err := t.DB.Select("id", "user_id", "video_id", "content").Where("taken_down_at IS NULL").First(&comment, commentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Comment{}, errors.New("comment not found")
+		}
+		return domain.Comment{}, err
+	}
+
+	return comment, nil
+}
+
+// ListTips returns paid tips, newest first, with what is left of them after refunds.
+func (t *tipRepository) ListTips(filter models.TipFilter, page, limit int) ([]models.TipDetails, error) {
+	var rows []struct {
+		models.TipDetails
+		AmountMinor int64
+	}
+
+	query := t.DB.Table("tips t").
+		Select(`t.id, t.user_id, u.username, t.creator_id, t.video_id, t.comment_id, t.amount - t.refunded AS amount_minor,
+			t.currency, t.message, t.anonymous, t.paid_at`).
+		Joins("LEFT JOIN users u ON u.id = t.user_id").
+		Where("t.status = ?", domain.TipPaid)
+
+	if filter.UserID != 0 {
+		query = query.Where("t.user_id = ?", filter.UserID)
+	}
+	if filter.CreatorID != 0 {
+		query = query.Where("t.creator_id = ?", filter.CreatorID)
+	}
+	if filter.VideoID != 0 {
+		query = query.Where("t.video_id = ?", filter.VideoID)
+	}
+
+	if err := query.Order("t.paid_at DESC, t.id DESC").Offset((page - 1) * limit).Limit(limit).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	tips := make([]models.TipDetails, len(rows))
+	for i, row := range rows {
+		tips[i] = row.TipDetails
+		tips[i].Amount = money.Money{Amount: row.AmountMinor, Currency: row.Currency}.Major()
+	}
+
+	return tips, nil
+}
+
+// GetTipTotals sums the creator's tips by currency, less refunds, in the currency's minor unit.
+func (t *tipRepository) GetTipTotals(creatorID int, startDate, endDate string) (map[string]int64, error) {
+	var rows []struct {
+		Currency string
+		Amount   int64
+	}
+
+	query := t.DB.Table("tips").
+		Select("currency, COALESCE(SUM(amount - refunded), 0) AS amount").
+		Where("creator_id = ? AND status IN ?", creatorID, []string{domain.TipPaid, domain.TipRefunded}).
+		Group("currency")
+
+	if startDate != "" && endDate != "" {
+		query = query.Where("paid_at BETWEEN ? AND ?", startDate, endDate)
+	}
+
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]int64)
+	for _, row := range rows {
+		totals[row.Currency] = row.Amount
+	}
+
+	return totals, nil
+}
@@ -6,6 +6,7 @@ import (
 	"main/pkg/domain"
 	interfaces "main/pkg/repository/interface"
 	"main/pkg/utils/models"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -54,7 +55,7 @@ func (c *userDatabase) FindUserByEmail(user models.UserLogin) (models.UserRespon
 func (c *userDatabase) SignUp(user models.UserDetails) (models.UserResponse, error) {
 
 	var userDetails models.UserResponse
-	err := c.DB.Raw("INSERT INTO users (name, email, password, phone, username) VALUES (?, ?, ?, ?,?) RETURNING id, name, email, phone", user.Name, user.Email, user.Password, user.Phone, user.Username).Scan(&userDetails).Error
+	err := c.DB.Raw("INSERT INTO users (name, email, password, phone, username, created_at) VALUES (?, ?, ?, ?, ?, ?) RETURNING id, name, email, phone", user.Name, user.Email, user.Password, user.Phone, user.Username, time.Now()).Scan(&userDetails).Error
 
 	if err != nil {
 		return models.UserResponse{}, err
@@ -133,20 +134,17 @@ func (i *userDatabase) GetProfileDetailsById(id int) (*domain.User, error) {
 	return user, nil
 }
 
-// for storing the reports from userReports
-func (i *userDatabase) StoreReport(reporterID, targetID int, reason string) error {
-	report := domain.Reports{
-		ReporterID: reporterID,
-		TargetID:   targetID,
-		Reason:     reason,
+func (i *userDatabase) GetBillingCountry(id int) (string, error) {
+	var country string
+	if err := i.DB.Raw("SELECT COALESCE(country, '') FROM users WHERE id = ?", id).Scan(&country).Error; err != nil {
+		return "", err
 	}
 
-	// Create a new report record in the database
-	if err := i.DB.Create(&report).Error; err != nil {
-		return err
-	}
+	return country, nil
+}
 
-	return nil
+func (i *userDatabase) SetBillingCountry(id int, country string) error {
+	return i.DB.Exec("UPDATE users SET country = ? WHERE id = ?", country, id).Error
 }
 
 // CheckFollowRelationship checks if a follow relationship exists between two users
@@ -165,7 +163,7 @@ func (i *userDatabase) CheckFollowRelationship(followerID, followingID int) (boo
 // StoreFollow creates a new follow relationship in the database
 func (i *userDatabase) StoreFollow(followerID, followingID int) error {
 	// Use raw SQL query to insert a new follow record
-	err := i.DB.Exec("INSERT INTO follows (follower_id, following_id) VALUES (?, ?)", followerID, followingID).Error
+	err := i.DB.Exec("INSERT INTO follows (follower_id, following_id, created_at) VALUES (?, ?, ?)", followerID, followingID, time.Now()).Error
 	if err != nil {
 		return err
 	}
@@ -209,11 +207,22 @@ func (ur *userDatabase) SearchUsersByNameWithPagination(searchTerm string, page,
 
 	return searchResults, nil
 }
+
+// CountUsersByName counts the users SearchUsersByNameWithPagination matches, across all pages
+func (ur *userDatabase) CountUsersByName(searchTerm string) (int64, error) {
+	var count int64
+	if err := ur.DB.Raw("SELECT COUNT(*) FROM users WHERE LOWER(name) LIKE LOWER(?)", "%"+searchTerm+"%").Scan(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
 func (ar *userDatabase) GetSubscriptionPlans() ([]domain.SubscriptionPlan, error) {
 	var plans []domain.SubscriptionPlan
 
 	// Fetch the list of subscription plans from the database
-	err := ar.DB.Find(&plans).Error
+	err := ar.DB.Where("archived = false").Order("creator_id, price_amount").Find(&plans).Error
 	if err != nil {
 		// Handle any error during the fetch operation, you can log or perform additional actions as needed
 		return nil, err
@@ -71,21 +71,16 @@ func (vr *VideoRepository) UploadVideo(userID int, categoryID int, title, descri
 		CreatedAt:   time.Now(), // Set the creation time to the current time
 	}
 
-	if err := vr.DB.Create(&video).Error; err != nil {
-		return 0, err
-	}
-
-	// Insert tags into VideoTags table for the newly created video
-	for _, tag := range tags {
-		videoTag := domain.VideoTags{
-			UserID:  userID,
-			VideoID: video.ID,
-			Tag:     tag,
+	err := vr.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&video).Error; err != nil {
+			return err
 		}
 
-		if err := vr.DB.Create(&videoTag).Error; err != nil {
-			return 0, err
-		}
+		_, err := linkVideoTags(tx, video, tags)
+		return err
+	})
+	if err != nil {
+		return 0, err
 	}
 
 	// Return the auto-generated ID of the newly created video
@@ -99,13 +94,83 @@ func (vr *VideoRepository) ListVideos(userID, page, limit int) ([]models.Video,
 	offset := (page - 1) * limit
 
 	// Query the database with pagination
-	if err := vr.DB.Where("user_id = ?", userID).Offset(offset).Limit(limit).Find(&videos).Error; err != nil {
+	if err := vr.DB.Where("user_id = ? AND taken_down_at IS NULL", userID).Offset(offset).Limit(limit).Find(&videos).Error; err != nil {
 		return nil, err
 	}
 
 	return videos, nil
 }
 
+// linkVideoTags tags the video with the canonical tags for the creator's tags, aliases resolve to their tag and
+// new names become tags. Banned tags are left out and returned.
+func linkVideoTags(tx *gorm.DB, video domain.Video, tags []string) ([]string, error) {
+	banned := []string{}
+	seen := make(map[uint]bool)
+	for _, tag := range tags {
+		name := normalizeTagName(tag)
+		if name == "" {
+			continue
+		}
+
+		isBanned, err := isTagBanned(tx, name)
+		if err != nil {
+			return nil, err
+		}
+		if isBanned {
+			banned = append(banned, name)
+			continue
+		}
+
+		canonical, found, err := resolveTag(tx, name)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			canonical = domain.Tag{Tag: name}
+			if err := tx.Create(&canonical).Error; err != nil {
+				return nil, err
+			}
+		}
+		if seen[canonical.ID] {
+			continue
+		}
+		seen[canonical.ID] = true
+
+		videoTag := domain.VideoTags{
+			UserID:  int(video.UserID),
+			VideoID: video.ID,
+			Tag:     canonical.Tag,
+			TagID:   &canonical.ID,
+		}
+		if err := tx.Create(&videoTag).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return banned, nil
+}
+
+// SetVideoTags replaces the tags of the video, it returns the banned tags that were left out.
+func (vr *VideoRepository) SetVideoTags(videoID int, tags []string) ([]string, error) {
+	var banned []string
+	err := vr.DB.Transaction(func(tx *gorm.DB) error {
+		var video domain.Video
+		if err := tx.Select("id", "user_id").First(&video, videoID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("video_id = ?", video.ID).Delete(&domain.VideoTags{}).Error; err != nil {
+			return err
+		}
+
+		var err error
+		banned, err = linkVideoTags(tx, video, tags)
+		return err
+	})
+
+	return banned, err
+}
+
 // EditVideoDetails updates the title and description of a video in the database.
 func (vr *VideoRepository) EditVideoDetails(videoID int, title, description string) error {
 	// Find the video by ID
@@ -145,6 +210,28 @@ func (repo *VideoRepository) IncrementVideoViews(videoID int) error {
 	}
 	return nil
 }
+
+// RecordVideoView stores the view for the creator's analytics.
+func (vr *VideoRepository) RecordVideoView(view *domain.VideoView) error {
+	return vr.DB.Create(view).Error
+}
+
+// UpdateWatchProgress moves the user's latest view of the video since the time to the position, a view only ever moves forward
+// and never past the end of the video. It reports whether there was such a view.
+func (vr *VideoRepository) UpdateWatchProgress(userID, videoID, position int, since time.Time) (bool, error) {
+	result := vr.DB.Exec(`
+		UPDATE video_views vv SET watched_seconds = GREATEST(vv.watched_seconds, LEAST(?, CASE WHEN v.duration > 0 THEN v.duration ELSE ? END))
+		FROM videos v
+		WHERE v.id = vv.video_id AND vv.id = (
+			SELECT id FROM video_views WHERE user_id = ? AND video_id = ? AND created_at >= ? ORDER BY created_at DESC, id DESC LIMIT 1
+		)`, position, position, userID, videoID, since)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
 func (vr *VideoRepository) IsLikedByUser(userID uint, videoID uint) bool {
 	var likeCount int64
 	err := vr.DB.Model(&domain.VideoLikes{}).
@@ -166,9 +253,11 @@ func (vr *VideoRepository) UnlikeVideo(userID uint, videoID uint) error {
 }
 
 func (vr *VideoRepository) LikeVideo(userID uint, videoID uint) error {
+	now := time.Now()
 	like := &domain.VideoLikes{
-		UserID:  userID,
-		VideoID: videoID,
+		UserID:    userID,
+		VideoID:   videoID,
+		CreatedAt: &now,
 	}
 	return vr.DB.Create(like).Error
 }
@@ -188,50 +277,96 @@ func (vr *VideoRepository) UpdateVideoLikesCount(videoID uint) error {
 
 // CreateComment adds a new comment to the repository.
 func (vr *VideoRepository) CreateComment(comment *domain.Comment) error {
+	if comment.CreatedAt == nil {
+		now := time.Now()
+		comment.CreatedAt = &now
+	}
 	return vr.DB.Create(comment).Error
 }
 
 // GetCommentsByVideoID retrieves comments for a specific video from the database.
+// Comments tipped with are highlighted at the top, the biggest tip first.
 func (vr *VideoRepository) GetCommentsByVideoID(videoID uint) ([]domain.Comment, error) {
 	var comments []domain.Comment
 
-	// Assuming you have a 'comments' table in your database with a 'video_id' column
-	if err := vr.DB.Where("video_id = ?", videoID).Find(&comments).Error; err != nil {
+	err := vr.DB.Table("comments c").
+		Select("c.*, COALESCE(t.amount, 0) AS tip_amount, COALESCE(t.currency, '') AS tip_currency").
+		Joins(`LEFT JOIN (SELECT comment_id, MIN(currency) AS currency, SUM(amount - refunded) AS amount FROM tips
+			WHERE status = 'paid' AND comment_id IS NOT NULL GROUP BY comment_id) t ON t.comment_id = c.id`).
+		Where("c.video_id = ? AND c.taken_down_at IS NULL", videoID).
+		Order("tip_amount DESC, c.id").
+		Scan(&comments).Error
+	if err != nil {
 		return nil, err
 	}
 
 	return comments, nil
 }
 
-// AddTags adds multiple tags to the database.
-func (vr *VideoRepository) AddTags(tags []string) error {
+// AddTags adds multiple tags to the database, tags that already exist or are banned are skipped.
+// It returns the banned tags that were skipped.
+func (vr *VideoRepository) AddTags(tags []string) ([]string, error) {
 	// Create a slice to store individual tags
 	var tagRecords []domain.Tag
+	banned := []string{}
+	seen := make(map[string]bool)
 
 	// Iterate through the tags and create Tag instances
 	for _, tag := range tags {
-		tagRecord := domain.Tag{
-			Tag: tag,
+		name := normalizeTagName(tag)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		isBanned, err := isTagBanned(vr.DB, name)
+		if err != nil {
+			return nil, err
+		}
+		if isBanned {
+			banned = append(banned, name)
+			continue
 		}
-		tagRecords = append(tagRecords, tagRecord)
+
+		if _, found, err := resolveTag(vr.DB, name); err != nil {
+			return nil, err
+		} else if found {
+			continue
+		}
+
+		tagRecords = append(tagRecords, domain.Tag{Tag: name})
+	}
+
+	if len(tagRecords) == 0 {
+		return banned, nil
 	}
 
 	// Insert all tags in a single database transaction
 	if err := vr.DB.Create(&tagRecords).Error; err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return banned, nil
 }
 
-// DeleteTagByID deletes a tag from the database based on a tag ID.
+// DeleteTagByID deletes a tag from the database based on a tag ID, along with the videos' links to it.
 func (vr *VideoRepository) DeleteTagByID(tagID uint) error {
-	// Delete the tag based on the tag ID
-	if err := vr.DB.Where("id = ?", tagID).Delete(&domain.Tag{}).Error; err != nil {
-		return err
-	}
+	return vr.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tag_id = ?", tagID).Delete(&domain.VideoTags{}).Error; err != nil {
+			return err
+		}
 
-	return nil
+		// Delete the tag based on the tag ID
+		result := tx.Where("id = ?", tagID).Delete(&domain.Tag{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected < 1 {
+			return errors.New("no tag with that ID exist")
+		}
+
+		return nil
+	})
 }
 
 // GetTags retrieves a list of tags from the database.
@@ -269,7 +404,7 @@ func (vr *VideoRepository) GetAllVideos() ([]domain.Video, error) {
 	var videos []domain.Video
 
 	// Use raw SQL query to retrieve only necessary fields from videos
-	if err := vr.DB.Raw("SELECT id, user_id, title, description, url, category_id, likes, views FROM videos").Scan(&videos).Error; err != nil {
+	if err := vr.DB.Raw("SELECT id, user_id, title, description, url, category_id, likes, views FROM videos WHERE taken_down_at IS NULL").Scan(&videos).Error; err != nil {
 		return nil, err
 	}
 
@@ -313,10 +448,22 @@ func (vr *VideoRepository) GetVideoTagsByVideoID(videoID uint) ([]string, error)
 	return tags, nil
 }
 
+func (vr *VideoRepository) GetVideoByID(videoID int) (domain.Video, error) {
+	var video domain.Video
+	if err := vr.DB.Where("id = ? AND taken_down_at IS NULL", videoID).First(&video).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Video{}, errors.New("video not found")
+		}
+		return domain.Video{}, err
+	}
+
+	return video, nil
+}
+
 // IsVideoExclusive checks if a video is marked as exclusive.
 func (vr *VideoRepository) IsVideoExclusive(videoID int) (bool, error) {
 	var video domain.Video
-	err := vr.DB.Where("id = ?", videoID).First(&video).Error
+	err := vr.DB.Where("id = ? AND taken_down_at IS NULL", videoID).First(&video).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			// Video not found
@@ -345,9 +492,17 @@ func (vr *VideoRepository) IsVideoExclusive(videoID int) (bool, error) {
 //		// User is subscribed
 //		return true, nil
 //	}
-func (vr *VideoRepository) IsUserSubscribed(userID int, creatorID int) (bool, error) {
+//
+// IsUserSubscribed checks if the user has a subscription to the creator that unlocks the video.
+// A video in one or more tiers needs one of those tiers, any tier unlocks a video that is in none.
+func (vr *VideoRepository) IsUserSubscribed(userID int, creatorID int, videoID int) (bool, error) {
 	var count int
-	err := vr.DB.Raw("SELECT COUNT(*) FROM subscription_lists WHERE user_id = ? AND creator_id = ? AND is_active = true", userID, creatorID).Scan(&count).Error
+	err := vr.DB.Raw(`
+		SELECT COUNT(*) FROM subscription_lists s
+		WHERE s.user_id = ? AND s.creator_id = ? AND s.status IN ('active', 'past_due')
+			AND (NOT EXISTS (SELECT 1 FROM tier_videos tv WHERE tv.video_id = ?)
+				OR EXISTS (SELECT 1 FROM tier_videos tv WHERE tv.video_id = ? AND tv.plan_id = s.plan_id))`,
+		userID, creatorID, videoID, videoID).Scan(&count).Error
 	if err != nil {
 		return false, err
 	}
@@ -364,7 +519,7 @@ func (vr *VideoRepository) ListtVideos(page, limit int, sort, order, search stri
 	offset := (page - 1) * limit
 
 	// Query the database with sorting and pagination
-	query := vr.DB
+	query := vr.DB.Where("taken_down_at IS NULL")
 
 	if search != "" {
 		query = query.Where("title LIKE ?", "%"+search+"%")
@@ -388,3 +543,24 @@ func (vr *VideoRepository) ListtVideos(page, limit int, sort, order, search stri
 
 	return videos, nil
 }
+
+// CountVideosByTitle counts the videos ListtVideos matches for a search, across all pages.
+func (vr *VideoRepository) CountVideosByTitle(search string) (int64, error) {
+	var count int64
+	if err := vr.DB.Model(&domain.Video{}).Where("taken_down_at IS NULL AND title LIKE ?", "%"+search+"%").Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ListVideosWithoutDuration returns videos whose duration was never found, the oldest first.
+func (vr *VideoRepository) ListVideosWithoutDuration(limit int) ([]domain.Video, error) {
+	var videos []domain.Video
+	err := vr.DB.Where("duration = 0 AND url <> ''").Order("id").Limit(limit).Find(&videos).Error
+
+	return videos, err
+}
+
+func (vr *VideoRepository) SetVideoDuration(videoID uint, duration int) error {
+	return vr.DB.Model(&domain.Video{}).Where("id = ?", videoID).Update("duration", duration).Error
+}
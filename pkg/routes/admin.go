@@ -3,37 +3,130 @@ package routes
 import (
 	"main/pkg/api/handler"
 	"main/pkg/api/middleware"
+	"main/pkg/domain"
 
 	"github.com/gin-gonic/gin"
 )
 
-func AdminRoutes(engine *gin.RouterGroup, adminHandler *handler.AdminHandler, categoryHandler *handler.CategoryHandler, videoHandler *handler.VideoHandler) {
+func AdminRoutes(engine *gin.RouterGroup, adminHandler *handler.AdminHandler, categoryHandler *handler.CategoryHandler, videoHandler *handler.VideoHandler, searchHandler *handler.SearchHandler, tagHandler *handler.TagHandler, subscriptionHandler *handler.SubscriptionHandler, tierHandler *handler.TierHandler, couponHandler *handler.CouponHandler, ledgerHandler *handler.LedgerHandler, payoutHandler *handler.PayoutHandler, taxHandler *handler.TaxHandler, tipHandler *handler.TipHandler, analyticsHandler *handler.AnalyticsHandler, moderationHandler *handler.ModerationHandler, enforcementHandler *handler.EnforcementHandler, auditHandler *handler.AuditHandler) {
 	engine.POST("/adminlogin", adminHandler.LoginHandler)
-	engine.Use(middleware.AdminAuthMiddleware)
-	engine.POST("/addtags", videoHandler.AddTagsHandler)
-	engine.DELETE("/deletetags", videoHandler.DeleteTagHandler)
-	engine.GET("/tags", videoHandler.GetTagsHandler)
-	engine.GET("/reports", adminHandler.GetReports)
-	engine.GET("/userReports", adminHandler.GetReportsofuser)
+	// every route below needs the permission of its group, checked against the admin's current role
+	engine.Use(middleware.AdminAuthMiddleware, middleware.AdminAccountMiddleware(adminHandler.AdminUseCase), middleware.AuditMiddleware(auditHandler.AuditUseCase))
+	engine.POST("/addtags", middleware.RequirePermission(domain.PermissionContent), videoHandler.AddTagsHandler)
+	engine.DELETE("/deletetags", middleware.RequirePermission(domain.PermissionContent), videoHandler.DeleteTagHandler)
+	engine.GET("/tags", middleware.RequirePermission(domain.PermissionContent), videoHandler.GetTagsHandler)
+	engine.GET("/dashboard", middleware.RequirePermission(domain.PermissionAnalytics), analyticsHandler.GetDashboard)
+	engine.GET("/audit", middleware.RequirePermission(domain.PermissionAudit), auditHandler.ListLogs)
+	engine.GET("/reports", middleware.RequirePermission(domain.PermissionModeration), adminHandler.GetReports)
+	engine.GET("/userReports", middleware.RequirePermission(domain.PermissionModeration), adminHandler.GetReportsofuser)
 	{
-		usermanagement := engine.Group("/users")
+		adminmanagement := engine.Group("/admins", middleware.RequirePermission(domain.PermissionAdmins))
 		{
-			usermanagement.POST("/toggle-block", adminHandler.ToggleBlockUser)
+			adminmanagement.GET("", adminHandler.ListAdmins)
+			adminmanagement.POST("", adminHandler.CreateAdmin)
+			adminmanagement.PATCH("/role", adminHandler.SetAdminRole)
+			adminmanagement.PATCH("/disable", adminHandler.DisableAdmin)
+			adminmanagement.PATCH("/enable", adminHandler.EnableAdmin)
+		}
+		usermanagement := engine.Group("/users", middleware.RequirePermission(domain.PermissionUsers))
+		{
+			usermanagement.POST("/toggle-block", enforcementHandler.ToggleBlockUser)
 
 			usermanagement.GET("/getusers", adminHandler.GetUsers)
 		}
-		categorymanagement := engine.Group("/category")
+		categorymanagement := engine.Group("/category", middleware.RequirePermission(domain.PermissionContent))
 		{
 			categorymanagement.GET("/", categoryHandler.Categories)
 			categorymanagement.POST("/add", categoryHandler.AddCategory)
 			categorymanagement.PATCH("/update", categoryHandler.UpdateCategory)
 			categorymanagement.DELETE("/delete", categoryHandler.DeleteCategory)
+			categorymanagement.GET("/tree", categoryHandler.CategoryTree)
+			categorymanagement.PATCH("/reorder", categoryHandler.ReorderCategories)
+			categorymanagement.PATCH("/icon", categoryHandler.UploadCategoryIcon)
 		}
-		planmanagement := engine.Group("/plans")
+		planmanagement := engine.Group("/plans", middleware.RequirePermission(domain.PermissionPlans))
 		{
 			planmanagement.GET("/", adminHandler.GetSubscriptionPlans)
 			planmanagement.POST("/add", adminHandler.AddSubscriptionPlan)
+			planmanagement.GET("/limits", tierHandler.GetTierPriceLimits)
+			planmanagement.PUT("/limits", tierHandler.SetTierPriceLimits)
 			planmanagement.DELETE("/delete", adminHandler.DeleteSubscriptionPlan)
 		}
+		tagmanagement := engine.Group("/tags", middleware.RequirePermission(domain.PermissionContent))
+		{
+			tagmanagement.GET("/stats", tagHandler.GetTagStats)
+			tagmanagement.GET("/aliases", tagHandler.GetAliases)
+			tagmanagement.POST("/aliases", tagHandler.AddAlias)
+			tagmanagement.DELETE("/aliases", tagHandler.DeleteAlias)
+			tagmanagement.POST("/merge", tagHandler.MergeTags)
+			tagmanagement.GET("/banned", tagHandler.GetBannedTags)
+			tagmanagement.POST("/banned", tagHandler.BanTag)
+			tagmanagement.DELETE("/banned", tagHandler.UnbanTag)
+		}
+		couponmanagement := engine.Group("/coupons", middleware.RequirePermission(domain.PermissionFinance))
+		{
+			couponmanagement.GET("", couponHandler.ListCoupons)
+			couponmanagement.POST("", couponHandler.CreateCoupon)
+			couponmanagement.DELETE("", couponHandler.DeactivateCoupon)
+		}
+		paymentmanagement := engine.Group("/payments", middleware.RequirePermission(domain.PermissionFinance))
+		{
+			paymentmanagement.GET("/events", subscriptionHandler.ListPaymentEvents)
+			paymentmanagement.POST("/events/replay", subscriptionHandler.ReplayPaymentEvent)
+			paymentmanagement.POST("/refunds", subscriptionHandler.RefundSubscription)
+			paymentmanagement.GET("/disputes", subscriptionHandler.ListDisputes)
+			paymentmanagement.PATCH("/disputes/review", subscriptionHandler.ReviewDispute)
+		}
+		tipmanagement := engine.Group("/tips", middleware.RequirePermission(domain.PermissionFinance))
+		{
+			tipmanagement.GET("/limits", tipHandler.GetTipLimits)
+			tipmanagement.PUT("/limits", tipHandler.SetTipLimits)
+		}
+		ledgermanagement := engine.Group("/ledger", middleware.RequirePermission(domain.PermissionFinance))
+		{
+			ledgermanagement.GET("/transactions", ledgerHandler.ListTransactions)
+			ledgermanagement.GET("/reconcile", ledgerHandler.Reconcile)
+			ledgermanagement.GET("/fee", ledgerHandler.GetPlatformFee)
+			ledgermanagement.PUT("/fee", ledgerHandler.SetPlatformFee)
+		}
+		payoutmanagement := engine.Group("/payouts", middleware.RequirePermission(domain.PermissionFinance))
+		{
+			payoutmanagement.GET("/settings", payoutHandler.GetPayoutSettings)
+			payoutmanagement.PUT("/settings", payoutHandler.SetPayoutSettings)
+			payoutmanagement.GET("/batches", payoutHandler.ListPayoutBatches)
+			payoutmanagement.POST("/batches", payoutHandler.CreatePayoutBatch)
+			payoutmanagement.GET("/batch", payoutHandler.GetPayoutBatch)
+			payoutmanagement.PATCH("/batches/approve", payoutHandler.ApprovePayoutBatch)
+			payoutmanagement.PATCH("/paid", payoutHandler.MarkPayoutPaid)
+		}
+		taxmanagement := engine.Group("/taxes", middleware.RequirePermission(domain.PermissionFinance))
+		{
+			taxmanagement.GET("", taxHandler.ListTaxRules)
+			taxmanagement.PUT("", taxHandler.SetTaxRule)
+			taxmanagement.DELETE("", taxHandler.DeleteTaxRule)
+		}
+		moderation := engine.Group("/moderation", middleware.RequirePermission(domain.PermissionModeration))
+		{
+			moderation.GET("/cases", moderationHandler.ListCases)
+			moderation.GET("/case", moderationHandler.GetCase)
+			moderation.PATCH("/cases/assign", moderationHandler.AssignCase)
+			moderation.PATCH("/cases/unassign", moderationHandler.UnassignCase)
+			moderation.POST("/cases/notes", moderationHandler.AddCaseNote)
+			moderation.POST("/cases/resolve", moderationHandler.ResolveCase)
+		}
+		enforcement := engine.Group("/enforcement", middleware.RequirePermission(domain.PermissionModeration))
+		{
+			enforcement.POST("/actions", enforcementHandler.IssueAction)
+			enforcement.GET("/actions", enforcementHandler.ListUserActions)
+			enforcement.PATCH("/actions/revoke", enforcementHandler.RevokeAction)
+			enforcement.GET("/appeals", enforcementHandler.ListAppeals)
+			enforcement.POST("/appeals/decide", enforcementHandler.DecideAppeal)
+		}
+		searchanalytics := engine.Group("/search", middleware.RequirePermission(domain.PermissionAnalytics))
+		{
+			searchanalytics.GET("/top", searchHandler.TopQueries)
+			searchanalytics.GET("/zero-results", searchHandler.ZeroResultQueries)
+			searchanalytics.GET("/trends", searchHandler.SearchTrends)
+		}
 	}
 }
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"main/pkg/api/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PaymentRoutes are called by the payment gateway, they are authenticated by their signature instead of a token.
+func PaymentRoutes(engine *gin.RouterGroup, subscriptionHandler *handler.SubscriptionHandler) {
+	engine.POST("/webhook", subscriptionHandler.PaymentWebhook)
+}
@@ -7,7 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func UserRoutes(engine *gin.RouterGroup, userHandler *handler.UserHandler, otpHandler *handler.OtpHandler, categoyHandler *handler.CategoryHandler, videohandler *handler.VideoHandler, subscriptionhandler *handler.SubscriptionHandler) {
+func UserRoutes(engine *gin.RouterGroup, userHandler *handler.UserHandler, otpHandler *handler.OtpHandler, categoyHandler *handler.CategoryHandler, videohandler *handler.VideoHandler, subscriptionhandler *handler.SubscriptionHandler, searchHandler *handler.SearchHandler, notificationHandler *handler.NotificationHandler, tierHandler *handler.TierHandler, couponHandler *handler.CouponHandler, ledgerHandler *handler.LedgerHandler, payoutHandler *handler.PayoutHandler, invoiceHandler *handler.InvoiceHandler, giftHandler *handler.GiftHandler, tipHandler *handler.TipHandler, analyticsHandler *handler.AnalyticsHandler, moderationHandler *handler.ModerationHandler, enforcementHandler *handler.EnforcementHandler) {
 	engine.POST("/login", userHandler.Login)
 	engine.POST("/signup", userHandler.SignUp)
 	engine.POST("/logout", userHandler.Logout)
@@ -17,15 +17,44 @@ func UserRoutes(engine *gin.RouterGroup, userHandler *handler.UserHandler, otpHa
 	engine.GET("/category", categoyHandler.CategoriesList)
 	engine.GET("/plans", userHandler.GetSubscriptionPlans)
 	engine.GET("/category/videos", categoyHandler.ListVideosByCategory)
+	engine.GET("/category/tree", categoyHandler.CategoryTree)
+	engine.GET("/category/slug", categoyHandler.CategoryBySlug)
 	engine.GET("/videos", videohandler.ListtVideos)
-	// Auth middleware
-	engine.Use(middleware.UserAuthMiddleware)
+	engine.POST("/suspension/appeal", enforcementHandler.AppealSuspension)
+	// Auth middleware, suspended users are turned away
+	engine.Use(middleware.UserAuthMiddleware, middleware.SuspensionMiddleware(enforcementHandler.EnforcementUseCase))
+	engine.GET("/enforcement", enforcementHandler.ListMyActions)
+	engine.GET("/enforcement/appeals", enforcementHandler.ListMyAppeals)
+	engine.POST("/enforcement/appeals", enforcementHandler.Appeal)
 	engine.GET("/followingList", userHandler.GetFollowingList)
 	engine.GET("/followersList", userHandler.GetFollowersList)
 	engine.GET("/search", userHandler.SearchUsers)
+	engine.GET("/search/all", searchHandler.SearchAll)
+	engine.GET("/search/suggest", searchHandler.Suggest)
+	engine.POST("/search/click", searchHandler.RecordClick)
 	engine.POST("search/toggleFollow", userHandler.ToggleFollow)
 	engine.GET("/analytics", subscriptionhandler.GetAnalytics)
-	engine.POST("/reportUser", userHandler.ReportUser)
+	engine.GET("/analytics/timeseries", analyticsHandler.GetTimeSeries)
+	engine.GET("/analytics/videos", analyticsHandler.ListVideoAnalytics)
+	engine.GET("/analytics/videos/top", analyticsHandler.TopVideos)
+	engine.GET("/analytics/video", analyticsHandler.GetVideoTimeSeries)
+	engine.GET("/analytics/retention", analyticsHandler.GetVideoRetention)
+	engine.GET("/analytics/export", analyticsHandler.ExportAnalytics)
+	engine.GET("/analytics/reports", analyticsHandler.ListReports)
+	engine.GET("/analytics/reports/download", analyticsHandler.DownloadReport)
+	engine.GET("/analytics/reports/settings", analyticsHandler.GetReportSettings)
+	engine.PUT("/analytics/reports/settings", analyticsHandler.SetReportSettings)
+	engine.GET("/earnings", ledgerHandler.GetEarnings)
+	engine.GET("/payouts", payoutHandler.ListCreatorPayouts)
+	engine.GET("/payouts/balance", payoutHandler.GetBalance)
+	engine.GET("/payouts/account", payoutHandler.GetPayoutAccount)
+	engine.PUT("/payouts/account", payoutHandler.SavePayoutAccount)
+	engine.GET("/billing/country", userHandler.GetBillingCountry)
+	engine.PUT("/billing/country", userHandler.SetBillingCountry)
+	engine.GET("/billing/invoices", invoiceHandler.ListInvoices)
+	engine.GET("/billing/invoices/download", invoiceHandler.DownloadInvoice)
+	engine.POST("/reportUser", moderationHandler.ReportUser)
+	engine.POST("/reports", moderationHandler.Report)
 	engine.GET("/tags", videohandler.GetTagsForUserHandler)
 	engine.POST("/selectTags", videohandler.StoreUserTags)
 	// engine.POST("/upload/video", videohandler.UploadVideo)
@@ -33,7 +62,32 @@ func UserRoutes(engine *gin.RouterGroup, userHandler *handler.UserHandler, otpHa
 
 	engine.POST("plans/choose-plan", subscriptionhandler.ChoosePlan)
 	engine.GET("plans/choose-plan/razorpay", subscriptionhandler.MakePaymentRazorPay)
+	engine.POST("plans/fake-pay", subscriptionhandler.SimulatePayment)
 	engine.GET("plans/update_status", subscriptionhandler.VerifyPayment)
+	engine.GET("/subscriptions", subscriptionhandler.ListSubscriptions)
+	engine.POST("/subscriptions/cancel", subscriptionhandler.CancelSubscription)
+	engine.POST("/subscriptions/resume", subscriptionhandler.ResumeSubscription)
+	engine.GET("/gifts", giftHandler.ListGiftPurchases)
+	engine.POST("/gifts", giftHandler.PurchaseGift)
+	engine.POST("/gifts/verify", giftHandler.VerifyGiftPayment)
+	engine.GET("/gifts/codes", giftHandler.ListGiftCodes)
+	engine.POST("/gifts/redeem", giftHandler.RedeemGiftCode)
+	engine.POST("/tips", tipHandler.SendTip)
+	engine.POST("/tips/verify", tipHandler.VerifyTipPayment)
+	engine.GET("/tips/sent", tipHandler.ListSentTips)
+	engine.GET("/tips/received", tipHandler.ListReceivedTips)
+	engine.GET("/tips/video", tipHandler.ListVideoTips)
+	engine.GET("/tiers", tierHandler.ListTiers)
+	engine.POST("/tiers", tierHandler.CreateTier)
+	engine.PATCH("/tiers", tierHandler.UpdateTier)
+	engine.DELETE("/tiers", tierHandler.DeleteTier)
+	engine.PUT("/tiers/videos", tierHandler.SetTierVideos)
+	engine.PUT("/tiers/prices", tierHandler.SetTierPrices)
+	engine.GET("/coupons", couponHandler.ListCreatorCoupons)
+	engine.POST("/coupons", couponHandler.CreateCreatorCoupon)
+	engine.DELETE("/coupons", couponHandler.DeactivateCreatorCoupon)
+	engine.GET("/notifications", notificationHandler.GetNotifications)
+	engine.POST("/notifications/read", notificationHandler.MarkNotificationsRead)
 
 	profile := engine.Group("/profile")
 	{
@@ -42,7 +96,8 @@ func UserRoutes(engine *gin.RouterGroup, userHandler *handler.UserHandler, otpHa
 
 		profile.GET("/videos/comments", videohandler.GetCommentsHandler)
 		profile.POST("/videos/comment", videohandler.CommentVideoHandler)
-		// profile.GET("/videos/watch", videohandler.WatchVideo)
+		profile.GET("/videos/watch", videohandler.WatchVideo)
+		profile.POST("/videos/progress", videohandler.UpdateWatchProgress)
 		profile.PATCH("/videos/editVideo", videohandler.EditVideoDetails)
 		profile.DELETE("/videos/delete", videohandler.DeleteVideo)
 		profile.POST("/videos/like", videohandler.ToggleLikeVideo)
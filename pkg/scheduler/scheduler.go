@@ -0,0 +1,137 @@
+package scheduler
+
+import (
+	"log"
+	services "main/pkg/usecase/interface"
+	"sync"
+
+	"github.com/robfig/cron"
+)
+
+// Scheduler runs the background jobs of the application alongside the HTTP server.
+type Scheduler struct {
+	cron                *cron.Cron
+	subscriptionUseCase services.SubscriptionUseCase
+	payoutUseCase       services.PayoutUseCase
+	analyticsUseCase    services.AnalyticsUseCase
+	videoUseCase        services.VideoUseCase
+
+	// a job still running when its next tick comes is skipped instead of run twice
+	lifecycleMu sync.Mutex
+	payoutMu    sync.Mutex
+	analyticsMu sync.Mutex
+	durationMu  sync.Mutex
+}
+
+func NewScheduler(subscriptionUseCase services.SubscriptionUseCase, payoutUseCase services.PayoutUseCase, analyticsUseCase services.AnalyticsUseCase, videoUseCase services.VideoUseCase) *Scheduler {
+	return &Scheduler{
+		cron:                cron.New(),
+		subscriptionUseCase: subscriptionUseCase,
+		payoutUseCase:       payoutUseCase,
+		analyticsUseCase:    analyticsUseCase,
+		videoUseCase:        videoUseCase,
+	}
+}
+
+// Start schedules the jobs and runs them once right away, so work missed while the server was down is caught up.
+func (s *Scheduler) Start() {
+	if err := s.cron.AddFunc("@every 1h", s.runSubscriptionLifecycle); err != nil {
+		log.Println("Error scheduling the subscription lifecycle job:", err)
+	}
+	// the batch is made once per month, checking daily makes it soon after the month ends even if the server was down
+	if err := s.cron.AddFunc("@daily", s.runPayoutBatch); err != nil {
+		log.Println("Error scheduling the payout batch job:", err)
+	}
+	// today's rollup is redone every hour, so the creator analytics and admin dashboard are at most an hour behind, the weekly reports follow it
+	if err := s.cron.AddFunc("@every 1h", s.runAnalyticsRollup); err != nil {
+		log.Println("Error scheduling the analytics rollup job:", err)
+	}
+	// videos from before durations were recorded, or whose probe on upload failed, get their duration found in batches
+	if err := s.cron.AddFunc("@every 10m", s.runVideoDurationBackfill); err != nil {
+		log.Println("Error scheduling the video duration backfill job:", err)
+	}
+
+	s.cron.Start()
+	go s.runSubscriptionLifecycle()
+	go s.runPayoutBatch()
+	go s.runAnalyticsRollup()
+	go s.runVideoDurationBackfill()
+}
+
+func (s *Scheduler) Stop() {
+	s.cron.Stop()
+}
+
+func (s *Scheduler) runSubscriptionLifecycle() {
+	if !s.lifecycleMu.TryLock() {
+		return
+	}
+	defer s.lifecycleMu.Unlock()
+
+	result, err := s.subscriptionUseCase.ProcessSubscriptionLifecycle()
+	if err != nil {
+		log.Println("Error updating subscription statuses:", err)
+		return
+	}
+
+	if result.Reminded+result.PastDue+result.Cancelled+result.Expired+result.Abandoned > 0 {
+		log.Printf("Subscription lifecycle: %d reminded, %d past due, %d cancelled, %d expired, %d abandoned\n", result.Reminded, result.PastDue, result.Cancelled, result.Expired, result.Abandoned)
+	}
+}
+
+func (s *Scheduler) runPayoutBatch() {
+	if !s.payoutMu.TryLock() {
+		return
+	}
+	defer s.payoutMu.Unlock()
+
+	batches, created, err := s.payoutUseCase.CreateMonthlyBatches()
+	if err != nil {
+		log.Println("Error creating the payout batches:", err)
+		return
+	}
+
+	if created > 0 {
+		for _, batch := range batches {
+			log.Printf("Payout batch %s has %d minor units of %s to pay\n", batch.Period, batch.Total, batch.Currency)
+		}
+	}
+}
+
+func (s *Scheduler) runAnalyticsRollup() {
+	if !s.analyticsMu.TryLock() {
+		return
+	}
+	defer s.analyticsMu.Unlock()
+
+	if _, err := s.analyticsUseCase.RollupAnalytics(); err != nil {
+		log.Println("Error rolling up the analytics:", err)
+		return
+	}
+
+	// weekly reports are made from the rollups, once the week is rolled up
+	made, err := s.analyticsUseCase.GenerateWeeklyReports()
+	if err != nil {
+		log.Println("Error making the weekly analytics reports:", err)
+		return
+	}
+	if made > 0 {
+		log.Printf("Made %d weekly analytics reports\n", made)
+	}
+}
+
+func (s *Scheduler) runVideoDurationBackfill() {
+	if !s.durationMu.TryLock() {
+		return
+	}
+	defer s.durationMu.Unlock()
+
+	found, err := s.videoUseCase.BackfillVideoDurations()
+	if err != nil {
+		log.Println("Error backfilling video durations:", err)
+		return
+	}
+	if found > 0 {
+		log.Printf("Found the duration of %d videos\n", found)
+	}
+}
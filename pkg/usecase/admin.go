@@ -4,20 +4,26 @@ import (
 	"errors"
 	"main/pkg/domain"
 	"main/pkg/helper"
+	"main/pkg/money"
+	"main/pkg/payments"
 	interfaces "main/pkg/repository/interface"
 	services "main/pkg/usecase/interface"
 	"main/pkg/utils/models"
+	"net/mail"
+	"strings"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
 type adminUseCase struct {
 	adminRepository interfaces.AdminRepository
+	gateway         payments.Gateway
 }
 
-func NewAdminUseCase(repo interfaces.AdminRepository) services.AdminUseCase {
+func NewAdminUseCase(repo interfaces.AdminRepository, gateway payments.Gateway) services.AdminUseCase {
 	return &adminUseCase{
 		adminRepository: repo,
+		gateway:         gateway,
 	}
 }
 
@@ -35,6 +41,10 @@ func (ad *adminUseCase) LoginHandler(adminDetails models.AdminLogin) (models.Tok
 		return models.TokenAdmin{}, err
 	}
 
+	if adminCompareDetails.Disabled {
+		return models.TokenAdmin{}, errors.New("admin account is disabled")
+	}
+
 	accessTokenString, refreshTokenString, err := helper.GenerateTokensAdmin(adminCompareDetails)
 
 	if err != nil {
@@ -59,25 +69,6 @@ func (ad *adminUseCase) GetUsers(page int, limit int) ([]models.UserDetailsAtAdm
 
 }
 
-func (ad *adminUseCase) ToggleBlockUser(id string) error {
-	// Retrieve user by ID
-	user, err := ad.adminRepository.GetUserByID(id)
-	if err != nil {
-		return err
-	}
-
-	// Toggle the Permission field
-	user.Permission = !user.Permission
-
-	// Update user in the repository
-	err = ad.adminRepository.UpdateBlockUserByID(user)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
 func (u *adminUseCase) GetReports(page, limit int) ([]domain.Reports, error) {
 	reports, err := u.adminRepository.GetReports(page, limit)
 	if err != nil {
@@ -85,6 +76,9 @@ func (u *adminUseCase) GetReports(page, limit int) ([]domain.Reports, error) {
 	}
 	return reports, nil
 }
+
+// AddSubscriptionPlan adds a platform plan, it belongs to no creator so it can be bought for any of them.
+// The price is in the major unit of the platform currency.
 func (u *adminUseCase) AddSubscriptionPlan(name string, duration int, price float64) error {
 	// Validate the input parameters
 	if duration <= 10 || price <= 10 {
@@ -92,10 +86,14 @@ func (u *adminUseCase) AddSubscriptionPlan(name string, duration int, price floa
 		return errors.New("duration and price must be above 10")
 	}
 
-	// Perform any additional business logic or validation specific to adding a subscription plan
+	plan := domain.SubscriptionPlan{
+		Name:     name,
+		Duration: duration,
+		Price:    money.FromMajor(price, u.gateway.Currency()),
+	}
 
 	// Call the repository function to add the subscription plan
-	err := u.adminRepository.AddSubscriptionPlan(name, duration, price)
+	err := u.adminRepository.AddSubscriptionPlan(&plan)
 	if err != nil {
 		// Handle any error from the repository, you can log or perform additional actions as needed
 		return err
@@ -141,3 +139,128 @@ func (u *adminUseCase) GetUserReports(userId, page, limit int) ([]domain.Reports
 
 	return reports, count, nil
 }
+
+// GetAdminRole returns the role of an admin who may still act, it is looked up on every request so
+// a role change or disabling takes effect at once.
+func (ad *adminUseCase) GetAdminRole(adminID uint) (string, error) {
+	admin, found, err := ad.adminRepository.GetAdminByID(adminID)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", errors.New("admin not found")
+	}
+	if admin.Disabled {
+		return "", errors.New("admin account is disabled")
+	}
+
+	return admin.Role, nil
+}
+
+func (ad *adminUseCase) ListAdmins() ([]models.AdminAccount, error) {
+	return ad.adminRepository.ListAdmins()
+}
+
+func (ad *adminUseCase) CreateAdmin(details models.CreateAdmin) (models.AdminAccount, error) {
+	if _, ok := domain.AdminRolePermissions[details.Role]; !ok {
+		return models.AdminAccount{}, errors.New("role must be super_admin, moderator, finance or content_curator")
+	}
+
+	username := strings.TrimSpace(details.Username)
+	if username == "" {
+		return models.AdminAccount{}, errors.New("name is required")
+	}
+	email := strings.TrimSpace(details.Email)
+	if _, err := mail.ParseAddress(email); err != nil {
+		return models.AdminAccount{}, errors.New("email is not valid")
+	}
+	if len(details.Password) < 8 || len(details.Password) > 20 {
+		return models.AdminAccount{}, errors.New("password must be 8 to 20 characters")
+	}
+
+	exists, err := ad.adminRepository.AdminEmailExists(email)
+	if err != nil {
+		return models.AdminAccount{}, err
+	}
+	if exists {
+		return models.AdminAccount{}, errors.New("an admin with this email already exists")
+	}
+
+	password, err := helper.PasswordHashing(details.Password)
+	if err != nil {
+		return models.AdminAccount{}, err
+	}
+
+	admin := domain.Admin{
+		Username: username,
+		Email:    email,
+		Password: password,
+		Role:     details.Role,
+	}
+	if err := ad.adminRepository.CreateAdmin(&admin); err != nil {
+		return models.AdminAccount{}, err
+	}
+
+	return models.AdminAccount{
+		ID:       admin.ID,
+		Username: admin.Username,
+		Email:    admin.Email,
+		Role:     admin.Role,
+	}, nil
+}
+
+// SetAdminRole changes the role of another admin, the last active super admin keeps their role.
+func (ad *adminUseCase) SetAdminRole(actorID, adminID uint, role string) error {
+	if _, ok := domain.AdminRolePermissions[role]; !ok {
+		return errors.New("role must be super_admin, moderator, finance or content_curator")
+	}
+	if actorID == adminID {
+		return errors.New("cannot change your own role")
+	}
+
+	admin, err := ad.lastSuperAdminCheck(adminID, role != domain.AdminSuperAdmin)
+	if err != nil {
+		return err
+	}
+	if admin.Role == role {
+		return nil
+	}
+
+	return ad.adminRepository.UpdateAdmin(adminID, map[string]interface{}{"role": role})
+}
+
+// SetAdminDisabled disables or enables another admin, the last active super admin cannot be disabled.
+func (ad *adminUseCase) SetAdminDisabled(actorID, adminID uint, disabled bool) error {
+	if actorID == adminID {
+		return errors.New("cannot disable or enable yourself")
+	}
+
+	if _, err := ad.lastSuperAdminCheck(adminID, disabled); err != nil {
+		return err
+	}
+
+	return ad.adminRepository.UpdateAdmin(adminID, map[string]interface{}{"disabled": disabled})
+}
+
+// lastSuperAdminCheck returns the admin, refusing when taking away their rights would leave no active super admin.
+func (ad *adminUseCase) lastSuperAdminCheck(adminID uint, removing bool) (domain.Admin, error) {
+	admin, found, err := ad.adminRepository.GetAdminByID(adminID)
+	if err != nil {
+		return domain.Admin{}, err
+	}
+	if !found {
+		return domain.Admin{}, errors.New("admin not found")
+	}
+
+	if removing && admin.Role == domain.AdminSuperAdmin && !admin.Disabled {
+		count, err := ad.adminRepository.CountActiveSuperAdmins()
+		if err != nil {
+			return domain.Admin{}, err
+		}
+		if count <= 1 {
+			return domain.Admin{}, errors.New("cannot remove the last active super admin")
+		}
+	}
+
+	return admin, nil
+}
@@ -0,0 +1,793 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"main/pkg/domain"
+	"main/pkg/payments"
+	interfaces "main/pkg/repository/interface"
+	services "main/pkg/usecase/interface"
+	"main/pkg/utils/models"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	analyticsRolledUpKey = "analytics_rolled_up_through"
+
+	// days rolled up the first time, before that the events were not all recorded
+	analyticsBackfillDays = 90
+
+	granularityDay   = "day"
+	granularityWeek  = "week"
+	granularityMonth = "month"
+
+	// longest range a series covers, daily points are limited to a year
+	maxDailyAnalyticsDays  = 366
+	maxAnalyticsDays       = 3 * 366
+	maxTopVideos           = 50
+	retentionStepPercent   = 5
+	analyticsDateFormat    = "2006-01-02"
+	maxVideoAnalyticsLimit = 100
+
+	// videos are exported a page at a time
+	exportVideoBatch = 100
+)
+
+// analyticsExportColumns are the columns of every report that can be exported
+var analyticsExportColumns = map[string][]string{
+	"views":       {"date", "views", "watch_seconds", "likes", "comments"},
+	"subscribers": {"date", "new_followers", "subscribers_gained", "subscribers_lost"},
+	"revenue":     {"date", "currency", "revenue"},
+	"videos":      {"video_id", "title", "duration", "views", "watch_seconds", "average_view_seconds", "likes", "comments"},
+}
+
+var weeklyReportColumns = []string{"date", "views", "watch_seconds", "likes", "comments", "new_followers", "subscribers_gained", "subscribers_lost", "revenue", "currency"}
+
+type analyticsUseCase struct {
+	repository    interfaces.AnalyticsRepository
+	settings      interfaces.SettingRepository
+	notifications interfaces.NotificationRepository
+	gateway       payments.Gateway
+}
+
+func NewAnalyticsUseCase(repo interfaces.AnalyticsRepository, settingRepo interfaces.SettingRepository, notificationRepo interfaces.NotificationRepository, gateway payments.Gateway) services.AnalyticsUseCase {
+	return &analyticsUseCase{
+		repository:    repo,
+		settings:      settingRepo,
+		notifications: notificationRepo,
+		gateway:       gateway,
+	}
+}
+
+// RollupAnalytics brings the daily rollups up to date and returns the number of days rolled up. The day before the last one
+// rolled up is done again too, views opened just before midnight keep taking progress after it and refunds can land late.
+func (a *analyticsUseCase) RollupAnalytics() (int, error) {
+	today := startOfDay(time.Now())
+	start := today.AddDate(0, 0, -analyticsBackfillDays)
+
+	values, err := a.settings.GetSettings(analyticsRolledUpKey)
+	if err != nil {
+		return 0, err
+	}
+	if value, ok := values[analyticsRolledUpKey]; ok {
+		last, err := time.ParseInLocation(analyticsDateFormat, value, time.Local)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s setting: %w", analyticsRolledUpKey, err)
+		}
+		start = last.AddDate(0, 0, -1)
+	}
+
+	days := 0
+	for day := start; !day.After(today); day = day.AddDate(0, 0, 1) {
+		if err := a.repository.RollupDay(day); err != nil {
+			return days, fmt.Errorf("rolling up %s: %w", day.Format(analyticsDateFormat), err)
+		}
+		days++
+	}
+
+	return days, a.settings.SetSettings(map[string]string{analyticsRolledUpKey: today.Format(analyticsDateFormat)})
+}
+
+// GetTimeSeries returns the creator's activity and revenue in the range, the last 30 days by default, per day, week or month.
+// Today's figures are as of the last rollup, which runs every hour.
+func (a *analyticsUseCase) GetTimeSeries(creatorID int, startDate, endDate, granularity string) (models.AnalyticsSeries, error) {
+	from, to, err := analyticsRange(startDate, endDate, granularity)
+	if err != nil {
+		return models.AnalyticsSeries{}, err
+	}
+
+	stats, err := a.repository.GetCreatorDailyStats(creatorID, from, to)
+	if err != nil {
+		return models.AnalyticsSeries{}, err
+	}
+	revenue, err := a.repository.GetCreatorDailyRevenue(creatorID, from, to)
+	if err != nil {
+		return models.AnalyticsSeries{}, err
+	}
+
+	series := newAnalyticsSeries(from, to, granularity, a.gateway.Currency())
+	for _, stat := range stats {
+		point := series.point(stat.Day)
+		point.Views += stat.Views
+		point.WatchSeconds += stat.WatchSeconds
+		point.Likes += stat.Likes
+		point.Comments += stat.Comments
+		point.NewFollowers += stat.NewFollowers
+		point.SubscribersGained += stat.SubscribersGained
+		point.SubscribersLost += stat.SubscribersLost
+	}
+
+	// summed in the minor unit so rounding is done once per point
+	amounts := make(map[string]map[string]int64)
+	totals := make(map[string]int64)
+	for _, row := range revenue {
+		date := series.periodOf(row.Day)
+		if amounts[date] == nil {
+			amounts[date] = make(map[string]int64)
+		}
+		amounts[date][row.Currency] += row.Amount
+		totals[row.Currency] += row.Amount
+	}
+	for i := range series.points {
+		series.points[i].RevenueByCurrency, series.points[i].Revenue = revenueByCurrency(amounts[series.points[i].Date], series.currency)
+	}
+
+	result := series.result()
+	result.Totals.RevenueByCurrency, result.Totals.Revenue = revenueByCurrency(totals, series.currency)
+
+	return result, nil
+}
+
+// ListVideoAnalytics returns the activity on each of the creator's videos in the range, the last 30 days by default,
+// sorted by views, watch_time, likes, comments or newest.
+func (a *analyticsUseCase) ListVideoAnalytics(creatorID int, startDate, endDate, sort string, page, limit int) ([]models.VideoAnalytics, error) {
+	// totals over the range are not limited like daily points are
+	from, to, err := analyticsRange(startDate, endDate, granularityMonth)
+	if err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > maxVideoAnalyticsLimit {
+		limit = 20
+	}
+
+	return a.repository.ListVideoAnalytics(creatorID, from, to, sort, page, limit)
+}
+
+// TopVideos returns the creator's best videos in the range by the metric, views by default.
+func (a *analyticsUseCase) TopVideos(creatorID int, startDate, endDate, metric string, limit int) ([]models.VideoAnalytics, error) {
+	if metric == "" {
+		metric = "views"
+	}
+	if metric == "newest" {
+		return nil, errors.New("metric must be views, watch_time, likes or comments")
+	}
+	if limit < 1 || limit > maxTopVideos {
+		limit = 10
+	}
+
+	return a.ListVideoAnalytics(creatorID, startDate, endDate, metric, 1, limit)
+}
+
+// GetVideoTimeSeries returns the activity on one of the creator's videos in the range, per day, week or month.
+func (a *analyticsUseCase) GetVideoTimeSeries(creatorID int, videoID uint, startDate, endDate, granularity string) (models.AnalyticsSeries, error) {
+	if _, err := a.creatorVideo(creatorID, videoID); err != nil {
+		return models.AnalyticsSeries{}, err
+	}
+
+	from, to, err := analyticsRange(startDate, endDate, granularity)
+	if err != nil {
+		return models.AnalyticsSeries{}, err
+	}
+
+	stats, err := a.repository.GetVideoDailyStats(videoID, from, to)
+	if err != nil {
+		return models.AnalyticsSeries{}, err
+	}
+
+	series := newAnalyticsSeries(from, to, granularity, a.gateway.Currency())
+	for _, stat := range stats {
+		point := series.point(stat.Day)
+		point.Views += stat.Views
+		point.WatchSeconds += stat.WatchSeconds
+		point.Likes += stat.Likes
+		point.Comments += stat.Comments
+	}
+
+	return series.result(), nil
+}
+
+// GetVideoRetention returns the share of the views opened in the range that reached every 5% of one of the creator's videos.
+// It is worked out from the views themselves, so it is always up to date.
+func (a *analyticsUseCase) GetVideoRetention(creatorID int, videoID uint, startDate, endDate string) (models.VideoRetention, error) {
+	video, err := a.creatorVideo(creatorID, videoID)
+	if err != nil {
+		return models.VideoRetention{}, err
+	}
+	if video.Duration <= 0 {
+		return models.VideoRetention{}, errors.New("the video's duration is not known")
+	}
+
+	from, to, err := analyticsRange(startDate, endDate, granularityDay)
+	if err != nil {
+		return models.VideoRetention{}, err
+	}
+
+	points, err := a.repository.GetRetention(videoID, video.Duration, from, to.AddDate(0, 0, 1), retentionStepPercent)
+	if err != nil {
+		return models.VideoRetention{}, err
+	}
+
+	retention := models.VideoRetention{
+		VideoID:   videoID,
+		Duration:  video.Duration,
+		StartDate: from.Format(analyticsDateFormat),
+		EndDate:   to.Format(analyticsDateFormat),
+		Points:    points,
+	}
+	// every view reaches the start of the video
+	if len(points) > 0 {
+		retention.Views = points[0].Views
+	}
+	if retention.Views > 0 {
+		for i := range retention.Points {
+			retention.Points[i].Retention = float64(retention.Points[i].Views) * 100 / float64(retention.Views)
+		}
+	}
+
+	return retention, nil
+}
+
+// GetDashboard returns the platform's overview for the range, the last 30 days by default, next to the range of the same
+// length just before it. It is read from the rollups, so today's figures are as of the last hourly run.
+func (a *analyticsUseCase) GetDashboard(startDate, endDate string) (models.Dashboard, error) {
+	from, to, err := analyticsRange(startDate, endDate, granularityDay)
+	if err != nil {
+		return models.Dashboard{}, err
+	}
+	days := int(to.Sub(from).Hours()/24+0.5) + 1
+	previousFrom, previousTo := from.AddDate(0, 0, -days), from.AddDate(0, 0, -1)
+
+	stats, err := a.repository.GetPlatformDailyStats(previousFrom, to)
+	if err != nil {
+		return models.Dashboard{}, err
+	}
+	revenue, err := a.repository.GetPlatformDailyRevenue(previousFrom, to)
+	if err != nil {
+		return models.Dashboard{}, err
+	}
+
+	start := from.Format(analyticsDateFormat)
+	var current, previous dashboardPeriod
+	for _, stat := range stats {
+		period := &previous
+		if stat.Day.Format(analyticsDateFormat) >= start {
+			period = &current
+		}
+		period.add(stat)
+	}
+	gross := map[string][2]int64{}
+	for _, row := range revenue {
+		amounts := gross[row.Currency]
+		if row.Day.Format(analyticsDateFormat) >= start {
+			amounts[0] += row.Gross
+		} else {
+			amounts[1] += row.Gross
+		}
+		gross[row.Currency] = amounts
+	}
+
+	dashboard := models.Dashboard{
+		StartDate:              start,
+		EndDate:                to.Format(analyticsDateFormat),
+		PreviousStartDate:      previousFrom.Format(analyticsDateFormat),
+		PreviousEndDate:        previousTo.Format(analyticsDateFormat),
+		Currency:               a.gateway.Currency(),
+		DailyActiveUsers:       dashboardMetric(float64(current.last.ActiveUsers), float64(previous.last.ActiveUsers)),
+		WeeklyActiveUsers:      dashboardMetric(float64(current.last.WeeklyActiveUsers), float64(previous.last.WeeklyActiveUsers)),
+		MonthlyActiveUsers:     dashboardMetric(float64(current.last.MonthlyActiveUsers), float64(previous.last.MonthlyActiveUsers)),
+		Signups:                dashboardMetric(float64(current.total.Signups), float64(previous.total.Signups)),
+		Uploads:                dashboardMetric(float64(current.total.Uploads), float64(previous.total.Uploads)),
+		Views:                  dashboardMetric(float64(current.total.Views), float64(previous.total.Views)),
+		WatchSeconds:           dashboardMetric(float64(current.total.WatchSeconds), float64(previous.total.WatchSeconds)),
+		ActiveSubscriptions:    dashboardMetric(float64(current.last.ActiveSubscriptions), float64(previous.last.ActiveSubscriptions)),
+		Reports:                dashboardMetric(float64(current.total.Reports), float64(previous.total.Reports)),
+		ModerationBacklog:      dashboardMetric(float64(current.backlog), float64(previous.backlog)),
+		GrossRevenueByCurrency: make(map[string]models.DashboardMetric),
+	}
+	if _, ok := gross[dashboard.Currency]; !ok {
+		gross[dashboard.Currency] = [2]int64{}
+	}
+	for currency, amounts := range gross {
+		dashboard.GrossRevenueByCurrency[currency] = dashboardMetric(majorAmount(amounts[0], currency), majorAmount(amounts[1], currency))
+	}
+	dashboard.GrossRevenue = dashboard.GrossRevenueByCurrency[dashboard.Currency]
+	// a backlog that was never counted has nothing to compare with
+	if !previous.backlogKnown {
+		dashboard.ModerationBacklog.Previous, dashboard.ModerationBacklog.Change, dashboard.ModerationBacklog.ChangePercent = 0, 0, nil
+	}
+
+	return dashboard, nil
+}
+
+// ExportAnalytics writes one of the creator's reports as CSV or NDJSON, with a row per period or per video.
+// start is given the file's name and type once the export is known to be good, and returns where to write it.
+func (a *analyticsUseCase) ExportAnalytics(creatorID int, export models.AnalyticsExport, start func(filename, contentType string) io.Writer) error {
+	columns, ok := analyticsExportColumns[export.Report]
+	if !ok {
+		return errors.New("report must be views, subscribers, revenue or videos")
+	}
+	if export.Format == "" {
+		export.Format = domain.ReportCSV
+	}
+	contentType, err := reportContentType(export.Format)
+	if err != nil {
+		return err
+	}
+
+	if export.Report == "videos" {
+		return a.exportVideos(creatorID, export, columns, contentType, start)
+	}
+
+	if export.Granularity == "" {
+		export.Granularity = granularityDay
+	}
+	series, err := a.GetTimeSeries(creatorID, export.StartDate, export.EndDate, export.Granularity)
+	if err != nil {
+		return err
+	}
+
+	filename := fmt.Sprintf("%s_%s_%s.%s", export.Report, series.StartDate, series.EndDate, export.Format)
+	writer, err := newExportWriter(start(filename, contentType), export.Format, columns)
+	if err != nil {
+		return err
+	}
+	for _, point := range series.Points {
+		switch export.Report {
+		case "views":
+			err = writer.write(point.Date, point.Views, point.WatchSeconds, point.Likes, point.Comments)
+		case "subscribers":
+			err = writer.write(point.Date, point.NewFollowers, point.SubscribersGained, point.SubscribersLost)
+		case "revenue":
+			for _, currency := range revenueCurrencies(point.RevenueByCurrency, series.Currency) {
+				if err = writer.write(point.Date, currency, point.RevenueByCurrency[currency]); err != nil {
+					break
+				}
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return writer.flush()
+}
+
+// exportVideos writes the videos a batch at a time, newest first so the pages stay put while the rollups change
+func (a *analyticsUseCase) exportVideos(creatorID int, export models.AnalyticsExport, columns []string, contentType string, start func(filename, contentType string) io.Writer) error {
+	from, to, err := analyticsRange(export.StartDate, export.EndDate, granularityMonth)
+	if err != nil {
+		return err
+	}
+
+	videos, err := a.repository.ListVideoAnalytics(creatorID, from, to, "newest", 1, exportVideoBatch)
+	if err != nil {
+		return err
+	}
+
+	filename := fmt.Sprintf("videos_%s_%s.%s", from.Format(analyticsDateFormat), to.Format(analyticsDateFormat), export.Format)
+	writer, err := newExportWriter(start(filename, contentType), export.Format, columns)
+	if err != nil {
+		return err
+	}
+	for page := 1; ; page++ {
+		if page > 1 {
+			if videos, err = a.repository.ListVideoAnalytics(creatorID, from, to, "newest", page, exportVideoBatch); err != nil {
+				return err
+			}
+		}
+		for _, video := range videos {
+			if err := writer.write(video.VideoID, video.Title, video.Duration, video.Views, video.WatchSeconds, video.AverageViewSeconds, video.Likes, video.Comments); err != nil {
+				return err
+			}
+		}
+		if len(videos) < exportVideoBatch {
+			break
+		}
+	}
+
+	return writer.flush()
+}
+
+func (a *analyticsUseCase) GetReportSettings(creatorID int) (models.AnalyticsReportSettings, error) {
+	setting, found, err := a.repository.GetReportSettings(creatorID)
+	if err != nil {
+		return models.AnalyticsReportSettings{}, err
+	}
+	if !found {
+		return models.AnalyticsReportSettings{Format: domain.ReportCSV}, nil
+	}
+
+	return models.AnalyticsReportSettings{Weekly: setting.Weekly, Format: setting.Format}, nil
+}
+
+// SetReportSettings turns the weekly report on or off, the first one is made for the last full week.
+func (a *analyticsUseCase) SetReportSettings(creatorID int, settings models.AnalyticsReportSettings) error {
+	if settings.Format == "" {
+		settings.Format = domain.ReportCSV
+	}
+	if _, err := reportContentType(settings.Format); err != nil {
+		return err
+	}
+
+	return a.repository.SaveReportSettings(domain.AnalyticsReportSetting{
+		CreatorID: creatorID,
+		Weekly:    settings.Weekly,
+		Format:    settings.Format,
+	})
+}
+
+// GenerateWeeklyReports makes the summary of the last full week, Monday to Sunday, for the creators who want one and
+// returns how many were made. It waits until the whole week is rolled up, a creator whose report fails is tried again next time.
+func (a *analyticsUseCase) GenerateWeeklyReports() (int, error) {
+	today := startOfDay(time.Now())
+	periodEnd := today.AddDate(0, 0, -((int(today.Weekday())+6)%7 + 1))
+	periodStart := periodEnd.AddDate(0, 0, -6)
+
+	values, err := a.settings.GetSettings(analyticsRolledUpKey)
+	if err != nil {
+		return 0, err
+	}
+	value, ok := values[analyticsRolledUpKey]
+	if !ok {
+		return 0, nil
+	}
+	rolledUp, err := time.ParseInLocation(analyticsDateFormat, value, time.Local)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s setting: %w", analyticsRolledUpKey, err)
+	}
+	// the rollup of the day it ran on may still change, the days before it are final
+	if !rolledUp.After(periodEnd) {
+		return 0, nil
+	}
+
+	due, err := a.repository.ListWeeklyReportsDue(periodStart)
+	if err != nil {
+		return 0, err
+	}
+
+	made := 0
+	for _, setting := range due {
+		report, err := a.weeklyReport(setting, periodStart, periodEnd)
+		if err != nil {
+			log.Printf("Error making the weekly report of creator %d: %v\n", setting.CreatorID, err)
+			continue
+		}
+
+		created, err := a.repository.CreateReport(&report)
+		if err != nil {
+			log.Printf("Error storing the weekly report of creator %d: %v\n", setting.CreatorID, err)
+			continue
+		}
+		if !created {
+			continue
+		}
+		made++
+
+		notification := domain.Notification{
+			UserID:  setting.CreatorID,
+			Type:    "analytics_report",
+			Title:   "Your weekly report is ready",
+			Message: fmt.Sprintf("Your analytics for %s to %s are ready to download.", periodStart.Format(analyticsDateFormat), periodEnd.Format(analyticsDateFormat)),
+		}
+		if err := a.notifications.CreateNotification(&notification); err != nil {
+			log.Println("Error creating notification:", err)
+		}
+	}
+
+	return made, nil
+}
+
+// weeklyReport has a row for every day of the week and one with the week's totals
+func (a *analyticsUseCase) weeklyReport(setting domain.AnalyticsReportSetting, periodStart, periodEnd time.Time) (domain.AnalyticsReport, error) {
+	series, err := a.GetTimeSeries(setting.CreatorID, periodStart.Format(analyticsDateFormat), periodEnd.Format(analyticsDateFormat), granularityDay)
+	if err != nil {
+		return domain.AnalyticsReport{}, err
+	}
+
+	var content bytes.Buffer
+	writer, err := newExportWriter(&content, setting.Format, weeklyReportColumns)
+	if err != nil {
+		return domain.AnalyticsReport{}, err
+	}
+	for _, point := range append(series.Points, series.Totals) {
+		if point.Date == "" {
+			point.Date = "total"
+		}
+		err := writer.write(point.Date, point.Views, point.WatchSeconds, point.Likes, point.Comments, point.NewFollowers,
+			point.SubscribersGained, point.SubscribersLost, point.Revenue, series.Currency)
+		if err != nil {
+			return domain.AnalyticsReport{}, err
+		}
+	}
+	if err := writer.flush(); err != nil {
+		return domain.AnalyticsReport{}, err
+	}
+
+	return domain.AnalyticsReport{
+		CreatorID:   setting.CreatorID,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+		Format:      setting.Format,
+		Filename:    fmt.Sprintf("weekly_%s_%s.%s", series.StartDate, series.EndDate, setting.Format),
+		Content:     content.Bytes(),
+	}, nil
+}
+
+// ListReports returns the creator's reports newest first.
+func (a *analyticsUseCase) ListReports(creatorID int, page, limit int) ([]domain.AnalyticsReport, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	return a.repository.ListReports(creatorID, page, limit)
+}
+
+// GetReportFile returns the report's file with its name and content type.
+func (a *analyticsUseCase) GetReportFile(creatorID int, reportID uint) ([]byte, string, string, error) {
+	report, err := a.repository.GetReport(reportID)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if report.CreatorID != creatorID {
+		return nil, "", "", errors.New("report not found")
+	}
+
+	contentType, err := reportContentType(report.Format)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	return report.Content, report.Filename, contentType, nil
+}
+
+func (a *analyticsUseCase) creatorVideo(creatorID int, videoID uint) (domain.Video, error) {
+	video, found, err := a.repository.GetVideo(videoID)
+	if err != nil {
+		return domain.Video{}, err
+	}
+	if !found || int(video.UserID) != creatorID {
+		return domain.Video{}, errors.New("video not found")
+	}
+
+	return video, nil
+}
+
+// analyticsRange turns the dates into the first and last day of the range, both at local midnight as the rollups are kept.
+func analyticsRange(startDate, endDate, granularity string) (time.Time, time.Time, error) {
+	start, end, err := parseDateRange(startDate, endDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	from := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.Local)
+	to := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.Local)
+
+	maxDays := maxAnalyticsDays
+	switch granularity {
+	case granularityDay:
+		maxDays = maxDailyAnalyticsDays
+	case granularityWeek, granularityMonth:
+	default:
+		return time.Time{}, time.Time{}, errors.New("granularity must be day, week or month")
+	}
+	if to.Sub(from) >= time.Duration(maxDays)*24*time.Hour {
+		return time.Time{}, time.Time{}, fmt.Errorf("the range can be at most %d days for %s granularity", maxDays, granularity)
+	}
+
+	return from, to, nil
+}
+
+// dashboardPeriod sums the days of one period and keeps its last day for the metrics taken as of the period's end
+type dashboardPeriod struct {
+	total        domain.PlatformDailyStat
+	last         domain.PlatformDailyStat
+	backlog      int64
+	backlogKnown bool
+}
+
+func (p *dashboardPeriod) add(stat domain.PlatformDailyStat) {
+	p.total.Signups += stat.Signups
+	p.total.Uploads += stat.Uploads
+	p.total.Views += stat.Views
+	p.total.WatchSeconds += stat.WatchSeconds
+	p.total.Reports += stat.Reports
+	p.last = stat
+	if stat.ModerationBacklog != nil {
+		p.backlog, p.backlogKnown = *stat.ModerationBacklog, true
+	}
+}
+
+func dashboardMetric(current, previous float64) models.DashboardMetric {
+	metric := models.DashboardMetric{
+		Current:  current,
+		Previous: previous,
+		Change:   current - previous,
+	}
+	if previous != 0 {
+		percent := metric.Change * 100 / previous
+		metric.ChangePercent = &percent
+	}
+
+	return metric
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// revenueByCurrency converts the minor unit amounts, the platform currency is always there
+func revenueByCurrency(amounts map[string]int64, platform string) (map[string]float64, float64) {
+	revenue := map[string]float64{platform: 0}
+	for currency, amount := range amounts {
+		revenue[currency] = majorAmount(amount, currency)
+	}
+
+	return revenue, revenue[platform]
+}
+
+// analyticsSeries gathers daily rows into the periods of a series, every period of the range has its point.
+type analyticsSeries struct {
+	granularity string
+	from, to    time.Time
+	currency    string
+	points      []models.AnalyticsPoint
+	index       map[string]int
+}
+
+func newAnalyticsSeries(from, to time.Time, granularity, currency string) *analyticsSeries {
+	series := &analyticsSeries{
+		granularity: granularity,
+		from:        from,
+		to:          to,
+		currency:    currency,
+		index:       make(map[string]int),
+	}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := series.periodOf(day)
+		if _, ok := series.index[date]; !ok {
+			series.index[date] = len(series.points)
+			series.points = append(series.points, models.AnalyticsPoint{Date: date})
+		}
+	}
+
+	return series
+}
+
+// periodOf is the first day of the day's period, weeks start on Monday. Rollup days are compared by their date only.
+func (s *analyticsSeries) periodOf(day time.Time) string {
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.Local)
+	switch s.granularity {
+	case granularityWeek:
+		day = day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case granularityMonth:
+		day = time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.Local)
+	}
+
+	return day.Format(analyticsDateFormat)
+}
+
+func (s *analyticsSeries) point(day time.Time) *models.AnalyticsPoint {
+	return &s.points[s.index[s.periodOf(day)]]
+}
+
+func (s *analyticsSeries) result() models.AnalyticsSeries {
+	result := models.AnalyticsSeries{
+		Granularity: s.granularity,
+		StartDate:   s.from.Format(analyticsDateFormat),
+		EndDate:     s.to.Format(analyticsDateFormat),
+		Currency:    s.currency,
+		Points:      s.points,
+	}
+	for _, point := range s.points {
+		result.Totals.Views += point.Views
+		result.Totals.WatchSeconds += point.WatchSeconds
+		result.Totals.Likes += point.Likes
+		result.Totals.Comments += point.Comments
+		result.Totals.NewFollowers += point.NewFollowers
+		result.Totals.SubscribersGained += point.SubscribersGained
+		result.Totals.SubscribersLost += point.SubscribersLost
+	}
+
+	return result
+}
+
+func reportContentType(format string) (string, error) {
+	switch format {
+	case domain.ReportCSV:
+		return "text/csv", nil
+	case domain.ReportNDJSON:
+		return "application/x-ndjson", nil
+	}
+
+	return "", errors.New("format must be csv or ndjson")
+}
+
+// revenueCurrencies lists the platform currency first and the others by name
+func revenueCurrencies(revenue map[string]float64, platform string) []string {
+	currencies := []string{}
+	for currency := range revenue {
+		if currency != platform {
+			currencies = append(currencies, currency)
+		}
+	}
+	sort.Strings(currencies)
+
+	return append([]string{platform}, currencies...)
+}
+
+// exportWriter writes rows of the columns as CSV with a header, or as one JSON object per line.
+type exportWriter struct {
+	columns []string
+	csv     *csv.Writer
+	json    *json.Encoder
+}
+
+func newExportWriter(w io.Writer, format string, columns []string) (*exportWriter, error) {
+	writer := &exportWriter{columns: columns}
+	if format == domain.ReportNDJSON {
+		writer.json = json.NewEncoder(w)
+		return writer, nil
+	}
+
+	writer.csv = csv.NewWriter(w)
+	return writer, writer.csv.Write(columns)
+}
+
+func (e *exportWriter) write(values ...interface{}) error {
+	if e.json != nil {
+		line := make(map[string]interface{}, len(values))
+		for i, value := range values {
+			line[e.columns[i]] = value
+		}
+		return e.json.Encode(line)
+	}
+
+	record := make([]string, len(values))
+	for i, value := range values {
+		switch v := value.(type) {
+		case float64:
+			record[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		case string:
+			// spreadsheets run a cell starting with these as a formula, titles are the creator's own text
+			if v != "" && strings.ContainsRune("=+-@", rune(v[0])) {
+				v = "'" + v
+			}
+			record[i] = v
+		default:
+			record[i] = fmt.Sprint(v)
+		}
+	}
+	return e.csv.Write(record)
+}
+
+func (e *exportWriter) flush() error {
+	if e.csv == nil {
+		return nil
+	}
+	e.csv.Flush()
+	return e.csv.Error()
+}
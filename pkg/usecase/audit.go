@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"encoding/json"
+	"log"
+	"main/pkg/domain"
+	interfaces "main/pkg/repository/interface"
+	services "main/pkg/usecase/interface"
+	"main/pkg/utils/models"
+	"strings"
+)
+
+// maxAuditBodySize is the largest request body kept in the audit log, larger bodies such as uploads are left out
+const maxAuditBodySize = 64 << 10
+
+type auditUseCase struct {
+	repository interfaces.AuditRepository
+}
+
+func NewAuditUseCase(repo interfaces.AuditRepository) services.AuditUseCase {
+	return &auditUseCase{
+		repository: repo,
+	}
+}
+
+// Snapshot returns the target as it is now, a failure leaves the snapshot out rather than failing the action
+func (a *auditUseCase) Snapshot(targetType, targetID string) *string {
+	if targetType == "" {
+		return nil
+	}
+
+	snapshot, err := a.repository.Snapshot(targetType, targetID)
+	if err != nil {
+		log.Println("Error taking audit snapshot:", err)
+		return nil
+	}
+
+	return snapshot
+}
+
+// Record stores the entry with the target as it is after the action, the action already happened so a failure is only logged
+func (a *auditUseCase) Record(entry models.AuditEntry) {
+	auditLog := domain.AuditLog{
+		AdminID:    entry.AdminID,
+		Action:     entry.Action,
+		Method:     entry.Method,
+		Path:       entry.Path,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		Query:      entry.Query,
+		Request:    auditBody(entry.Body),
+		Before:     entry.Before,
+		After:      a.Snapshot(entry.TargetType, entry.TargetID),
+		Status:     entry.Status,
+		IP:         entry.IP,
+	}
+	if err := a.repository.CreateLog(&auditLog); err != nil {
+		log.Println("Error writing audit log:", err)
+	}
+}
+
+func (a *auditUseCase) ListLogs(filter models.AuditFilter, page, limit int) ([]domain.AuditLog, error) {
+	from, to, err := parseDateRange(filter.StartDate, filter.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.repository.ListLogs(filter, from, to, page, limit)
+}
+
+// auditBody returns the JSON body without passwords, nil when there is none or it is not JSON
+func auditBody(body []byte) *string {
+	if len(body) == 0 || len(body) > maxAuditBodySize {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return nil
+	}
+
+	redacted, err := json.Marshal(redactPasswords(value))
+	if err != nil {
+		return nil
+	}
+	request := string(redacted)
+
+	return &request
+}
+
+func redactPasswords(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if strings.Contains(strings.ToLower(key), "password") {
+				delete(v, key)
+				continue
+			}
+			v[key] = redactPasswords(field)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactPasswords(item)
+		}
+	}
+
+	return value
+}
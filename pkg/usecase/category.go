@@ -2,25 +2,61 @@ package usecase
 
 import (
 	"errors"
+	conf "main/pkg/config"
 	"main/pkg/domain"
+	"main/pkg/helper"
 	interfaces "main/pkg/repository/interface"
 	services "main/pkg/usecase/interface"
 	"main/pkg/utils/models"
+	"mime/multipart"
+	"strconv"
+	"strings"
 )
 
 type categoryUseCase struct {
 	repository interfaces.CategoryRepository
+	config     conf.Config
 }
 
-func NewCategoryUseCase(repo interfaces.CategoryRepository) services.CategoryUseCase {
+func NewCategoryUseCase(cfg conf.Config, repo interfaces.CategoryRepository) services.CategoryUseCase {
 	return &categoryUseCase{
 		repository: repo,
+		config:     cfg,
 	}
 }
 
-func (Cat *categoryUseCase) AddCategory(category string) (domain.Category, error) {
+func (Cat *categoryUseCase) AddCategory(category models.AddCategory) (domain.Category, error) {
+	category.Category = strings.TrimSpace(category.Category)
+	if category.Category == "" {
+		return domain.Category{}, errors.New("category name is required")
+	}
 
-	productResponse, err := Cat.repository.AddCategory(category)
+	exists, err := Cat.repository.CheckCategory(category.Category)
+	if err != nil {
+		return domain.Category{}, err
+	}
+	if exists {
+		return domain.Category{}, errors.New("category already exists")
+	}
+
+	if category.ParentID != nil {
+		if _, err := Cat.repository.GetCategoryByID(*category.ParentID); err != nil {
+			return domain.Category{}, errors.New("parent category does not exist")
+		}
+	}
+
+	slug, err := Cat.uniqueSlug(category.Category, 0)
+	if err != nil {
+		return domain.Category{}, err
+	}
+
+	productResponse, err := Cat.repository.AddCategory(domain.Category{
+		Category:    category.Category,
+		Slug:        slug,
+		ParentID:    category.ParentID,
+		Description: category.Description,
+		SortOrder:   category.SortOrder,
+	})
 
 	if err != nil {
 		return domain.Category{}, err
@@ -30,18 +66,87 @@ func (Cat *categoryUseCase) AddCategory(category string) (domain.Category, error
 
 }
 
-func (Cat *categoryUseCase) UpdateCategory(current string, new string) (domain.Category, error) {
+// uniqueSlug slugifies the name and adds a number suffix while the slug is taken by another category
+func (Cat *categoryUseCase) uniqueSlug(name string, categoryID uint) (string, error) {
+	base := helper.Slugify(name)
+	if base == "" {
+		return "", errors.New("category name must contain letters or digits")
+	}
+
+	slug := base
+	for i := 2; ; i++ {
+		exists, err := Cat.repository.SlugExists(slug, categoryID)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return slug, nil
+		}
+		slug = base + "-" + strconv.Itoa(i)
+	}
+}
+
+// UpdateCategory changes the fields set in the update, the slug is kept on rename so existing links keep working.
+func (Cat *categoryUseCase) UpdateCategory(update models.UpdateCategory) (domain.Category, error) {
 
-	result, err := Cat.repository.CheckCategory(current)
+	category, err := Cat.repository.GetCategoryByID(update.ID)
 	if err != nil {
 		return domain.Category{}, err
 	}
 
-	if !result {
-		return domain.Category{}, errors.New("there is no category as you mentioned")
+	if update.Category != nil {
+		name := strings.TrimSpace(*update.Category)
+		if name == "" {
+			return domain.Category{}, errors.New("category name is required")
+		}
+		if name != category.Category {
+			exists, err := Cat.repository.CheckCategory(name)
+			if err != nil {
+				return domain.Category{}, err
+			}
+			if exists {
+				return domain.Category{}, errors.New("category already exists")
+			}
+		}
+		category.Category = name
 	}
 
-	newcat, err := Cat.repository.UpdateCategory(current, new)
+	if update.Slug != nil {
+		slug := helper.Slugify(*update.Slug)
+		if slug == "" {
+			return domain.Category{}, errors.New("slug must contain letters or digits")
+		}
+		exists, err := Cat.repository.SlugExists(slug, category.ID)
+		if err != nil {
+			return domain.Category{}, err
+		}
+		if exists {
+			return domain.Category{}, errors.New("slug is already used by another category")
+		}
+		category.Slug = slug
+	}
+
+	if update.Description != nil {
+		category.Description = *update.Description
+	}
+
+	if update.SortOrder != nil {
+		category.SortOrder = *update.SortOrder
+	}
+
+	if update.ParentID != nil {
+		if *update.ParentID == 0 {
+			category.ParentID = nil
+		} else {
+			if err := Cat.checkParent(category.ID, *update.ParentID); err != nil {
+				return domain.Category{}, err
+			}
+			parentID := *update.ParentID
+			category.ParentID = &parentID
+		}
+	}
+
+	newcat, err := Cat.repository.UpdateCategory(category)
 	if err != nil {
 		return domain.Category{}, err
 	}
@@ -49,9 +154,74 @@ func (Cat *categoryUseCase) UpdateCategory(current string, new string) (domain.C
 	return newcat, err
 }
 
-func (Cat *categoryUseCase) DeleteCategory(categoryID string) error {
+// checkParent makes sure the parent exists and is not the category itself or one of its descendants
+func (Cat *categoryUseCase) checkParent(categoryID, parentID uint) error {
+	current := parentID
+	for {
+		if current == categoryID {
+			return errors.New("a category cannot be moved under itself or one of its subcategories")
+		}
+
+		parent, err := Cat.repository.GetCategoryByID(current)
+		if err != nil {
+			return errors.New("parent category does not exist")
+		}
+
+		if parent.ParentID == nil {
+			return nil
+		}
+		current = *parent.ParentID
+	}
+}
+
+func (Cat *categoryUseCase) UploadCategoryIcon(categoryID uint, icon *multipart.FileHeader) (domain.Category, error) {
+	category, err := Cat.repository.GetCategoryByID(categoryID)
+	if err != nil {
+		return domain.Category{}, err
+	}
+
+	url, err := helper.AddImageToS3(icon, Cat.config)
+	if err != nil {
+		return domain.Category{}, err
+	}
+
+	category.IconURL = url
+	return Cat.repository.UpdateCategory(category)
+}
+
+// DeleteCategory deletes a category after moving its videos to the moveTo category, its subcategories move up one level.
+func (Cat *categoryUseCase) DeleteCategory(categoryID string, moveTo string) error {
+	id, err := strconv.Atoi(categoryID)
+	if err != nil {
+		return errors.New("converting into integer not happened")
+	}
 
-	err := Cat.repository.DeleteCategory(categoryID)
+	if _, err := Cat.repository.GetCategoryByID(uint(id)); err != nil {
+		return err
+	}
+
+	videos, err := Cat.repository.CountCategoryVideos(uint(id))
+	if err != nil {
+		return err
+	}
+
+	var target int
+	if moveTo != "" {
+		target, err = strconv.Atoi(moveTo)
+		if err != nil {
+			return errors.New("move_to must be a category ID")
+		}
+		if target == id {
+			return errors.New("videos cannot be moved to the category being deleted")
+		}
+		if _, err := Cat.repository.GetCategoryByID(uint(target)); err != nil {
+			return errors.New("category to move the videos to does not exist")
+		}
+	} else if videos > 0 {
+		return errors.New("the category has videos, choose the category to move them to with move_to")
+	}
+
+	err = Cat.repository.DeleteCategory(uint(id), uint(target))
 	if err != nil {
 		return err
 	}
@@ -67,6 +237,51 @@ func (Cat *categoryUseCase) GetCategories(page, limit int) ([]domain.Category, e
 	return categories, nil
 }
 
+// GetCategoryTree returns the top level categories with their subcategories nested in display order.
+func (Cat *categoryUseCase) GetCategoryTree() ([]models.CategoryTree, error) {
+	categories, err := Cat.repository.GetAllCategories()
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[uint][]models.CategoryTree)
+	var roots []models.CategoryTree
+	for _, category := range categories {
+		if category.ParentID == nil {
+			roots = append(roots, category)
+			continue
+		}
+		children[*category.ParentID] = append(children[*category.ParentID], category)
+	}
+
+	var build func(nodes []models.CategoryTree) []models.CategoryTree
+	build = func(nodes []models.CategoryTree) []models.CategoryTree {
+		for i := range nodes {
+			nodes[i].Children = build(children[nodes[i].ID])
+		}
+		if nodes == nil {
+			nodes = []models.CategoryTree{}
+		}
+		return nodes
+	}
+
+	return build(roots), nil
+}
+
+func (Cat *categoryUseCase) GetCategoryBySlug(slug string) (domain.Category, error) {
+	if slug == "" {
+		return domain.Category{}, errors.New("slug is required")
+	}
+	return Cat.repository.GetCategoryBySlug(slug)
+}
+
+func (Cat *categoryUseCase) ReorderCategories(orders []models.CategoryOrder) error {
+	if len(orders) == 0 {
+		return errors.New("no categories to reorder")
+	}
+	return Cat.repository.ReorderCategories(orders)
+}
+
 // ListVideosByCategory retrieves a list of videos in a specific category based on category ID, page, and limit.
 func (uc *categoryUseCase) ListVideosByCategory(categoryID, page, limit int) ([]models.VideoResponses, error) {
 	// Validate categoryID
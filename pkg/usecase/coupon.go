@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"errors"
+	"main/pkg/domain"
+	interfaces "main/pkg/repository/interface"
+	services "main/pkg/usecase/interface"
+	"main/pkg/utils/models"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type couponUseCase struct {
+	repository interfaces.CouponRepository
+}
+
+func NewCouponUseCase(repo interfaces.CouponRepository) services.CouponUseCase {
+	return &couponUseCase{
+		repository: repo,
+	}
+}
+
+var couponCodePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,32}$`)
+
+// CreateCoupon adds a coupon, it is platform-wide when it has no creator.
+func (c *couponUseCase) CreateCoupon(input models.AddCoupon) (domain.Coupon, error) {
+	coupon := domain.Coupon{
+		Code:           strings.ToUpper(strings.TrimSpace(input.Code)),
+		CreatorID:      input.CreatorID,
+		DiscountType:   input.DiscountType,
+		DiscountValue:  input.DiscountValue,
+		MaxRedemptions: input.MaxRedemptions,
+		FirstTimeOnly:  input.FirstTimeOnly,
+		ExpiresAt:      input.ExpiresAt,
+		Active:         true,
+	}
+
+	if !couponCodePattern.MatchString(coupon.Code) {
+		return domain.Coupon{}, errors.New("code must be 3 to 32 letters, digits, dashes or underscores")
+	}
+
+	switch coupon.DiscountType {
+	case domain.CouponPercent:
+		if coupon.DiscountValue <= 0 || coupon.DiscountValue > 100 {
+			return domain.Coupon{}, errors.New("a percent discount must be above 0 and at most 100")
+		}
+	case domain.CouponFixed:
+		if coupon.DiscountValue <= 0 {
+			return domain.Coupon{}, errors.New("a fixed discount must be above 0")
+		}
+	default:
+		return domain.Coupon{}, errors.New("discount type must be percent or fixed")
+	}
+
+	if coupon.MaxRedemptions < 0 {
+		return domain.Coupon{}, errors.New("max redemptions cannot be negative")
+	}
+	if coupon.CreatorID < 0 {
+		return domain.Coupon{}, errors.New("invalid creator id")
+	}
+	if coupon.ExpiresAt != nil && !coupon.ExpiresAt.After(time.Now()) {
+		return domain.Coupon{}, errors.New("expiry must be in the future")
+	}
+
+	if err := c.repository.CreateCoupon(&coupon); err != nil {
+		return domain.Coupon{}, err
+	}
+
+	return coupon, nil
+}
+
+// ListCoupons returns all coupons for admins, or only the creator's own when creatorID is set.
+func (c *couponUseCase) ListCoupons(creatorID *int, page, limit int) ([]domain.Coupon, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	coupons, err := c.repository.ListCoupons(creatorID, page, limit)
+	if err != nil {
+		return nil, err
+	}
+	if coupons == nil {
+		coupons = []domain.Coupon{}
+	}
+
+	return coupons, nil
+}
+
+// DeactivateCoupon stops a coupon from being applied, creators can only deactivate their own coupons.
+func (c *couponUseCase) DeactivateCoupon(creatorID *int, couponID uint) error {
+	coupon, err := c.repository.GetCoupon(couponID)
+	if err != nil {
+		return err
+	}
+
+	if creatorID != nil && coupon.CreatorID != *creatorID {
+		return errors.New("coupon does not belong to the creator")
+	}
+
+	return c.repository.DeactivateCoupon(couponID)
+}
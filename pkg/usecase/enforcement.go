@@ -0,0 +1,378 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"main/pkg/domain"
+	"main/pkg/helper"
+	interfaces "main/pkg/repository/interface"
+	services "main/pkg/usecase/interface"
+	"main/pkg/utils/models"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	maxEnforcementReasonLength = 500
+	maxAppealLength            = 2000
+	warningExpiry              = 90 * 24 * time.Hour
+	strikeExpiry               = 180 * 24 * time.Hour
+	maxSuspension              = 365 * 24 * time.Hour
+	// reaching this many active strikes suspends the user for strikeSuspension
+	strikesBeforeSuspension = 3
+	strikeSuspension        = 7 * 24 * time.Hour
+)
+
+type enforcementUseCase struct {
+	repository    interfaces.EnforcementRepository
+	notifications interfaces.NotificationRepository
+}
+
+func NewEnforcementUseCase(repo interfaces.EnforcementRepository, notificationRepo interfaces.NotificationRepository) services.EnforcementUseCase {
+	return &enforcementUseCase{
+		repository:    repo,
+		notifications: notificationRepo,
+	}
+}
+
+// IssueAction takes the action against the user, or against who posted the video or comment for a takedown, and tells them why.
+// A strike that brings the user to strikesBeforeSuspension active strikes also suspends them.
+func (e *enforcementUseCase) IssueAction(adminID uint, request models.EnforcementRequest) (domain.EnforcementAction, error) {
+	reason := strings.TrimSpace(request.Reason)
+	if reason == "" {
+		return domain.EnforcementAction{}, errors.New("reason is required, it is shown to the user")
+	}
+	if len(reason) > maxEnforcementReasonLength {
+		return domain.EnforcementAction{}, errors.New("reason : length exceeds the limit")
+	}
+	if request.DurationHours < 0 {
+		return domain.EnforcementAction{}, errors.New("duration cannot be negative")
+	}
+	duration := time.Duration(request.DurationHours) * time.Hour
+	if duration > maxSuspension {
+		return domain.EnforcementAction{}, errors.New("duration cannot be more than a year")
+	}
+
+	action := domain.EnforcementAction{
+		UserID:   request.UserID,
+		Type:     request.Type,
+		Reason:   reason,
+		CaseID:   request.CaseID,
+		IssuedBy: adminID,
+	}
+
+	now := time.Now()
+	switch request.Type {
+	case domain.EnforcementWarning, domain.EnforcementStrike:
+		if duration == 0 {
+			duration = warningExpiry
+			if request.Type == domain.EnforcementStrike {
+				duration = strikeExpiry
+			}
+		}
+		expiresAt := now.Add(duration)
+		action.ExpiresAt = &expiresAt
+	case domain.EnforcementSuspension:
+		if duration == 0 {
+			return domain.EnforcementAction{}, errors.New("a suspension needs a duration")
+		}
+		expiresAt := now.Add(duration)
+		action.ExpiresAt = &expiresAt
+	case domain.EnforcementBan:
+		// a ban has no end, it lasts until it is revoked
+	case domain.EnforcementTakedown:
+		ownerID, found, err := e.repository.FindContentOwner(request.TargetType, request.TargetID)
+		if err != nil {
+			return domain.EnforcementAction{}, err
+		}
+		if !found {
+			return domain.EnforcementAction{}, fmt.Errorf("%s not found", request.TargetType)
+		}
+		action.UserID = ownerID
+		action.TargetType = request.TargetType
+		action.TargetID = request.TargetID
+
+		if err := e.repository.TakeDown(&action); err != nil {
+			return domain.EnforcementAction{}, err
+		}
+		notifyEnforcement(e.notifications, action.UserID, action.Type, action.Reason, nil)
+		return action, nil
+	default:
+		return domain.EnforcementAction{}, errors.New("type must be warning, strike, suspension, takedown or ban")
+	}
+
+	exists, err := e.repository.UserExists(action.UserID)
+	if err != nil {
+		return domain.EnforcementAction{}, err
+	}
+	if !exists {
+		return domain.EnforcementAction{}, errors.New("user not found")
+	}
+
+	if action.Type == domain.EnforcementBan {
+		if _, banned, err := e.repository.GetActiveBan(action.UserID); err != nil {
+			return domain.EnforcementAction{}, err
+		} else if banned {
+			return domain.EnforcementAction{}, errors.New("user is already banned")
+		}
+		err = e.repository.Ban(&action)
+	} else {
+		err = e.repository.CreateAction(&action)
+	}
+	if err != nil {
+		return domain.EnforcementAction{}, err
+	}
+	notifyEnforcement(e.notifications, action.UserID, action.Type, action.Reason, action.ExpiresAt)
+
+	if action.Type == domain.EnforcementStrike {
+		e.suspendForStrikes(action)
+	}
+
+	return action, nil
+}
+
+// suspendForStrikes suspends the user when the strike brings them to the limit, the strike stands even if this fails
+func (e *enforcementUseCase) suspendForStrikes(strike domain.EnforcementAction) {
+	strikes, err := e.repository.CountActiveStrikes(strike.UserID)
+	if err != nil {
+		log.Println("Error counting strikes:", err)
+		return
+	}
+	if strikes < strikesBeforeSuspension {
+		return
+	}
+
+	expiresAt := time.Now().Add(strikeSuspension)
+	suspension := domain.EnforcementAction{
+		UserID:    strike.UserID,
+		Type:      domain.EnforcementSuspension,
+		Reason:    fmt.Sprintf("%d active strikes, the latest for: %s", strikes, strike.Reason),
+		CaseID:    strike.CaseID,
+		IssuedBy:  strike.IssuedBy,
+		ExpiresAt: &expiresAt,
+	}
+	if err := e.repository.CreateAction(&suspension); err != nil {
+		log.Println("Error suspending user:", err)
+		return
+	}
+	notifyEnforcement(e.notifications, suspension.UserID, suspension.Type, suspension.Reason, suspension.ExpiresAt)
+}
+
+// RevokeAction lifts the action early, showing taken down content again and unblocking a banned user.
+func (e *enforcementUseCase) RevokeAction(actionID, adminID uint, reason string) (domain.EnforcementAction, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return domain.EnforcementAction{}, errors.New("reason is required")
+	}
+	if len(reason) > maxEnforcementReasonLength {
+		return domain.EnforcementAction{}, errors.New("reason : length exceeds the limit")
+	}
+
+	if _, found, err := e.repository.GetAction(actionID); err != nil {
+		return domain.EnforcementAction{}, err
+	} else if !found {
+		return domain.EnforcementAction{}, errors.New("action not found")
+	}
+
+	action, revoked, err := e.repository.RevokeAction(actionID, adminID, reason)
+	if err != nil {
+		return domain.EnforcementAction{}, err
+	}
+	if !revoked {
+		return domain.EnforcementAction{}, errors.New("action is already revoked")
+	}
+
+	notify(e.notifications, action.UserID, "enforcement_revoked", "An action on your account was lifted",
+		fmt.Sprintf("The %s on your account was lifted: %s", action.Type, reason))
+
+	return action, nil
+}
+
+// ToggleBan bans the user, or revokes their ban when they are banned. It reports whether the user is banned now.
+func (e *enforcementUseCase) ToggleBan(adminID uint, userID int, reason string) (domain.EnforcementAction, bool, error) {
+	ban, banned, err := e.repository.GetActiveBan(userID)
+	if err != nil {
+		return domain.EnforcementAction{}, false, err
+	}
+	if banned {
+		action, err := e.RevokeAction(ban.ID, adminID, reason)
+		return action, false, err
+	}
+
+	action, err := e.IssueAction(adminID, models.EnforcementRequest{Type: domain.EnforcementBan, UserID: userID, Reason: reason})
+	if err != nil {
+		return domain.EnforcementAction{}, false, err
+	}
+	return action, true, nil
+}
+
+// ListActions returns the actions against the user, including expired and revoked ones.
+func (e *enforcementUseCase) ListActions(userID int, page, limit int) ([]domain.EnforcementAction, error) {
+	return e.repository.ListActions(userID, page, limit)
+}
+
+func (e *enforcementUseCase) GetActiveSuspension(userID int) (domain.EnforcementAction, bool, error) {
+	return e.repository.GetActiveSuspension(userID)
+}
+
+// Appeal asks for a review of an action against the user that is still in place.
+func (e *enforcementUseCase) Appeal(userID int, request models.AppealRequest) (domain.Appeal, error) {
+	message := strings.TrimSpace(request.Message)
+	if message == "" {
+		return domain.Appeal{}, errors.New("message is required")
+	}
+	if len(message) > maxAppealLength {
+		return domain.Appeal{}, errors.New("message : length exceeds the limit")
+	}
+
+	action, found, err := e.repository.GetAction(request.ActionID)
+	if err != nil {
+		return domain.Appeal{}, err
+	}
+	if !found || action.UserID != userID {
+		return domain.Appeal{}, errors.New("action not found")
+	}
+	if action.RevokedAt != nil {
+		return domain.Appeal{}, errors.New("action is already revoked")
+	}
+	if action.ExpiresAt != nil && !action.ExpiresAt.After(time.Now()) {
+		return domain.Appeal{}, errors.New("action has already expired")
+	}
+
+	appeal := domain.Appeal{
+		ActionID: action.ID,
+		UserID:   userID,
+		Message:  message,
+		Status:   domain.AppealPending,
+	}
+	created, err := e.repository.CreateAppeal(&appeal)
+	if err != nil {
+		return domain.Appeal{}, err
+	}
+	if !created {
+		return domain.Appeal{}, errors.New("action was already appealed")
+	}
+
+	return appeal, nil
+}
+
+// AppealSuspension appeals the current suspension or ban of a user who cannot log in because of it.
+func (e *enforcementUseCase) AppealSuspension(request models.SuspensionAppeal) (domain.Appeal, error) {
+	userID, password, found, err := e.repository.FindUserCredentials(request.Email)
+	if err != nil {
+		return domain.Appeal{}, err
+	}
+	if !found || bcrypt.CompareHashAndPassword([]byte(password), []byte(request.Password)) != nil {
+		return domain.Appeal{}, errors.New("email or password incorrect")
+	}
+
+	action, suspended, err := e.repository.GetActiveSuspension(userID)
+	if err != nil {
+		return domain.Appeal{}, err
+	}
+	if !suspended {
+		var banned bool
+		action, banned, err = e.repository.GetActiveBan(userID)
+		if err != nil {
+			return domain.Appeal{}, err
+		}
+		if !banned {
+			return domain.Appeal{}, errors.New("account is not suspended or blocked")
+		}
+	}
+
+	return e.Appeal(userID, models.AppealRequest{ActionID: action.ID, Message: request.Message})
+}
+
+func (e *enforcementUseCase) ListUserAppeals(userID int) ([]domain.Appeal, error) {
+	return e.repository.ListUserAppeals(userID)
+}
+
+func (e *enforcementUseCase) ListAppeals(status string, page, limit int) ([]domain.Appeal, error) {
+	switch status {
+	case "", domain.AppealPending, domain.AppealUpheld, domain.AppealOverturned:
+	default:
+		return nil, errors.New("status must be pending, upheld or overturned")
+	}
+
+	return e.repository.ListAppeals(status, page, limit)
+}
+
+// DecideAppeal answers a pending appeal and tells the user, overturning it lifts the action.
+func (e *enforcementUseCase) DecideAppeal(appealID, adminID uint, decision models.AppealDecision) (domain.Appeal, error) {
+	response := strings.TrimSpace(decision.Response)
+	if response == "" {
+		return domain.Appeal{}, errors.New("response is required, it is shown to the user")
+	}
+	if len(response) > maxAppealLength {
+		return domain.Appeal{}, errors.New("response : length exceeds the limit")
+	}
+
+	if _, found, err := e.repository.GetAppeal(appealID); err != nil {
+		return domain.Appeal{}, err
+	} else if !found {
+		return domain.Appeal{}, errors.New("appeal not found")
+	}
+
+	appeal, decided, err := e.repository.DecideAppeal(appealID, adminID, decision.Overturn, response)
+	if err != nil {
+		return domain.Appeal{}, err
+	}
+	if !decided {
+		return domain.Appeal{}, errors.New("appeal was already decided")
+	}
+
+	message := "Your appeal was reviewed and the action stands: " + response
+	if decision.Overturn {
+		message = "Your appeal was accepted and the action was lifted: " + response
+	}
+	notify(e.notifications, appeal.UserID, "appeal_decided", "Your appeal was reviewed", message)
+
+	return appeal, nil
+}
+
+// notifyEnforcement tells the user about an action taken against them and why
+func notifyEnforcement(notifications interfaces.NotificationRepository, userID int, actionType, reason string, expiresAt *time.Time) {
+	var title, message string
+	switch actionType {
+	case domain.EnforcementWarning:
+		title = "You received a warning"
+		message = "You received a warning for: " + reason
+	case domain.EnforcementStrike:
+		title = "You received a strike"
+		message = fmt.Sprintf("You received a strike for: %s. Reaching %d active strikes suspends your account.", reason, strikesBeforeSuspension)
+	case domain.EnforcementSuspension:
+		title = "Your account is suspended"
+		message = helper.SuspensionMessage(*expiresAt, reason)
+	case domain.EnforcementTakedown:
+		title = "Your content was removed"
+		message = "Your content was removed for: " + reason
+	case domain.EnforcementBan:
+		title = "Your account is blocked"
+		message = helper.BanMessage(reason)
+	}
+	if actionType != domain.EnforcementSuspension && actionType != domain.EnforcementBan {
+		if expiresAt != nil {
+			message += fmt.Sprintf(" It expires on %s.", expiresAt.UTC().Format(time.RFC1123))
+		}
+		message += " You can appeal it."
+	}
+
+	notify(notifications, userID, "enforcement", title, message)
+}
+
+// notify stores a notification for the user, a failure here must not fail the action
+func notify(notifications interfaces.NotificationRepository, userID int, kind, title, message string) {
+	notification := domain.Notification{
+		UserID:  userID,
+		Type:    kind,
+		Title:   title,
+		Message: message,
+	}
+	if err := notifications.CreateNotification(&notification); err != nil {
+		log.Println("Error creating notification:", err)
+	}
+}
@@ -0,0 +1,424 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"main/pkg/domain"
+	"main/pkg/payments"
+	interfaces "main/pkg/repository/interface"
+	services "main/pkg/usecase/interface"
+	"main/pkg/utils/models"
+	"strings"
+	"time"
+)
+
+const (
+	// maxGiftCodes caps the codes one purchase makes
+	maxGiftCodes = 500
+	// defaultGiftValidDays and maxGiftValidDays bound how long codes can be redeemed for
+	defaultGiftValidDays = 90
+	maxGiftValidDays     = 365
+	// giftCodeAlphabet leaves out letters and digits that are easily mixed up, like O and 0
+	giftCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+// giftFulfilment turns paid gift purchases into codes and codes into subscriptions,
+// shared by the gift checkout and the payment webhook.
+type giftFulfilment struct {
+	gifts         interfaces.GiftRepository
+	subscriptions interfaces.SubscriptionRepository
+	ledger        interfaces.LedgerRepository
+	settings      interfaces.SettingRepository
+	notifications interfaces.NotificationRepository
+}
+
+type giftUseCase struct {
+	giftFulfilment
+	taxes   interfaces.TaxRepository
+	gateway payments.Gateway
+}
+
+func NewGiftUseCase(repo interfaces.GiftRepository, subscriptionRepo interfaces.SubscriptionRepository, ledgerRepo interfaces.LedgerRepository, settingRepo interfaces.SettingRepository, notificationRepo interfaces.NotificationRepository, taxRepo interfaces.TaxRepository, gateway payments.Gateway) services.GiftUseCase {
+	return &giftUseCase{
+		giftFulfilment: giftFulfilment{
+			gifts:         repo,
+			subscriptions: subscriptionRepo,
+			ledger:        ledgerRepo,
+			settings:      settingRepo,
+			notifications: notificationRepo,
+		},
+		taxes:   taxRepo,
+		gateway: gateway,
+	}
+}
+
+// PurchaseGift opens a gift purchase for one of a creator's plans. Codes are priced like a subscription for the buyer,
+// each one at the plan's price, and are made once the gateway order is paid.
+// A creator's gift of their own plan is a giveaway, its codes are made right away and nothing is charged.
+func (g *giftUseCase) PurchaseGift(buyerID int, order models.GiftOrder) (models.GiftCheckout, error) {
+	plan, err := g.subscriptions.GetPlan(order.PlanID)
+	if err != nil {
+		return models.GiftCheckout{}, err
+	}
+	if plan.Archived {
+		return models.GiftCheckout{}, errors.New("plan is no longer offered")
+	}
+	if plan.CreatorID == 0 {
+		return models.GiftCheckout{}, errors.New("gifts can only be bought for a creator's tier")
+	}
+
+	quantity := order.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+	if quantity < 1 || quantity > maxGiftCodes {
+		return models.GiftCheckout{}, fmt.Errorf("quantity must be between 1 and %d", maxGiftCodes)
+	}
+
+	validDays := order.ValidDays
+	if validDays == 0 {
+		validDays = defaultGiftValidDays
+	}
+	if validDays < 1 || validDays > maxGiftValidDays {
+		return models.GiftCheckout{}, fmt.Errorf("codes can be valid for 1 to %d days", maxGiftValidDays)
+	}
+
+	if order.RecipientID != nil {
+		recipientID := *order.RecipientID
+		if quantity != 1 {
+			return models.GiftCheckout{}, errors.New("a gift for a user is a single subscription")
+		}
+		if recipientID == buyerID {
+			return models.GiftCheckout{}, errors.New("choose the plan to subscribe yourself")
+		}
+		if recipientID == plan.CreatorID {
+			return models.GiftCheckout{}, errors.New("cannot gift a creator their own tier")
+		}
+		username, err := g.subscriptions.FindUsername(recipientID)
+		if err != nil {
+			return models.GiftCheckout{}, err
+		}
+		if username == "" {
+			return models.GiftCheckout{}, errors.New("recipient not found")
+		}
+	}
+
+	purchase := domain.GiftPurchase{
+		BuyerID:     buyerID,
+		CreatorID:   plan.CreatorID,
+		PlanID:      plan.ID,
+		RecipientID: order.RecipientID,
+		Quantity:    quantity,
+		ValidDays:   validDays,
+		Status:      domain.GiftPending,
+	}
+
+	if buyerID == plan.CreatorID {
+		purchase.Giveaway = true
+		purchase.Currency = plan.Price.Currency
+		if err := g.gifts.CreateGiftPurchase(&purchase); err != nil {
+			return models.GiftCheckout{}, err
+		}
+
+		codes, err := g.fulfil(purchase, fmt.Sprintf("giveaway_%d", purchase.ID))
+		if err != nil {
+			return models.GiftCheckout{}, err
+		}
+
+		purchase, err = g.gifts.GetGiftPurchase(purchase.ID)
+		if err != nil {
+			return models.GiftCheckout{}, err
+		}
+
+		return models.GiftCheckout{Purchase: purchase, Currency: purchase.Currency, Paid: true, Codes: withGiftCodeStatus(codes)}, nil
+	}
+
+	// Gifts are priced for the buyer, coupons are for the buyer's own subscriptions
+	quote, err := priceQuote(g.subscriptions, g.taxes, g.gateway.Currency(), plan, buyerID, nil)
+	if err != nil {
+		return models.GiftCheckout{}, err
+	}
+	if quote.Total <= 0 {
+		return models.GiftCheckout{}, errors.New("plan has no price to pay for")
+	}
+
+	currency := quote.Price.Currency
+	purchase.Amount = quote.Total * int64(quantity)
+	purchase.Tax = quote.Tax.Amount * int64(quantity)
+	purchase.Currency = currency
+	purchase.TaxName = quote.Tax.Name
+	purchase.TaxRate = quote.Tax.Rate
+	purchase.TaxInclusive = quote.Tax.Inclusive
+	if err := g.gifts.CreateGiftPurchase(&purchase); err != nil {
+		return models.GiftCheckout{}, err
+	}
+
+	gatewayOrder, err := g.gateway.CreateOrder(purchase.Amount, currency, fmt.Sprintf("gift_%d", purchase.ID))
+	if err != nil {
+		return models.GiftCheckout{}, err
+	}
+	if err := g.gifts.SetGiftOrder(purchase.ID, gatewayOrder.ID); err != nil {
+		return models.GiftCheckout{}, err
+	}
+	purchase.RazorOrderID = gatewayOrder.ID
+
+	tax := quote.Tax
+	tax.Amount = purchase.Tax
+
+	return models.GiftCheckout{
+		Purchase:   purchase,
+		UnitPrice:  majorAmount(quote.Total, currency),
+		Tax:        majorAmount(purchase.Tax, currency),
+		TaxLabel:   taxLabel(tax),
+		FinalPrice: majorAmount(purchase.Amount, currency),
+		Currency:   currency,
+		RazorID:    gatewayOrder.ID,
+		Amount:     gatewayOrder.Amount,
+		Gateway:    g.gateway.Name(),
+		KeyID:      g.gateway.KeyID(),
+	}, nil
+}
+
+// VerifyGiftPayment checks the checkout signature before making the codes, the client supplied IDs are not trusted on their own.
+func (g *giftUseCase) VerifyGiftPayment(buyerID int, purchaseID uint, paymentID, razorID, signature string) ([]domain.GiftCode, error) {
+	purchase, err := g.ownPurchase(buyerID, purchaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if purchase.RazorOrderID == "" || purchase.RazorOrderID != razorID {
+		return nil, errors.New("payment does not belong to this gift purchase")
+	}
+	if !g.gateway.VerifySignature(razorID, paymentID, signature) {
+		return nil, errors.New("invalid payment signature")
+	}
+
+	// The webhook may have confirmed the payment already
+	if purchase.Status != domain.GiftPending && purchase.PaymentID != paymentID {
+		return nil, errors.New("gift purchase is already paid")
+	}
+
+	codes, err := g.fulfil(purchase, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return withGiftCodeStatus(codes), nil
+}
+
+// RedeemGiftCode starts the subscription the code pays for, or renews or changes the user's subscription to the creator.
+func (g *giftUseCase) RedeemGiftCode(userID int, code string) (models.RedeemedGift, error) {
+	giftCode, err := g.gifts.GetGiftCode(code)
+	if err != nil {
+		return models.RedeemedGift{}, err
+	}
+
+	return g.redeem(giftCode, userID)
+}
+
+func (g *giftUseCase) ListGiftPurchases(buyerID int, page, limit int) ([]domain.GiftPurchase, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	purchases, err := g.gifts.ListGiftPurchases(buyerID, page, limit)
+	if err != nil {
+		return nil, err
+	}
+	if purchases == nil {
+		purchases = []domain.GiftPurchase{}
+	}
+
+	return purchases, nil
+}
+
+// ListGiftCodes returns the codes of one of the buyer's purchases with whether each is still available.
+func (g *giftUseCase) ListGiftCodes(buyerID int, purchaseID uint) ([]domain.GiftCode, error) {
+	if _, err := g.ownPurchase(buyerID, purchaseID); err != nil {
+		return nil, err
+	}
+
+	codes, err := g.gifts.ListGiftCodes(purchaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	return withGiftCodeStatus(codes), nil
+}
+
+// ownPurchase loads the gift purchase and makes sure the user bought it
+func (g *giftUseCase) ownPurchase(buyerID int, purchaseID uint) (domain.GiftPurchase, error) {
+	purchase, err := g.gifts.GetGiftPurchase(purchaseID)
+	if err != nil {
+		return domain.GiftPurchase{}, err
+	}
+	if purchase.BuyerID != buyerID {
+		return domain.GiftPurchase{}, errors.New("gift purchase does not belong to the user")
+	}
+
+	return purchase, nil
+}
+
+// fulfil makes the codes of a paid purchase and posts its payment to the ledger, paying twice does not make codes twice.
+// A purchase for a recipient is redeemed for them straight away.
+func (f giftFulfilment) fulfil(purchase domain.GiftPurchase, paymentID string) ([]domain.GiftCode, error) {
+	expiresAt := time.Now().AddDate(0, 0, purchase.ValidDays)
+	codes := make([]domain.GiftCode, purchase.Quantity)
+	for i := range codes {
+		code, err := newGiftCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = domain.GiftCode{
+			Code:       code,
+			PurchaseID: purchase.ID,
+			PlanID:     purchase.PlanID,
+			CreatorID:  purchase.CreatorID,
+			ExpiresAt:  expiresAt,
+		}
+	}
+
+	fulfilled, err := f.gifts.FulfilGiftPurchase(purchase.ID, paymentID, codes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Posted even when the purchase was already fulfilled, a retried payment still gets its charge if posting it failed before
+	if purchase.Amount > 0 {
+		feePercent, err := platformFeePercent(f.settings)
+		if err != nil {
+			return nil, err
+		}
+		transaction := giftChargeTransaction(purchase, paymentID, feePercent)
+		if _, err := f.ledger.PostTransaction(&transaction); err != nil {
+			return nil, err
+		}
+	}
+
+	if !fulfilled {
+		return f.gifts.ListGiftCodes(purchase.ID)
+	}
+
+	if purchase.RecipientID == nil {
+		f.notify(purchase.BuyerID, "gift_codes", "Your gift codes are ready",
+			fmt.Sprintf("%d gift codes can be redeemed until %s.", purchase.Quantity, expiresAt.Format("2 Jan 2006")))
+		return codes, nil
+	}
+
+	if _, err := f.redeem(codes[0], *purchase.RecipientID); err != nil {
+		return nil, err
+	}
+	f.notify(*purchase.RecipientID, "gift_received", "You received a gift subscription",
+		"Someone gifted you a subscription, enjoy the exclusive videos.")
+
+	return f.gifts.ListGiftCodes(purchase.ID)
+}
+
+// redeem claims the code for the user and starts its subscription the way a paid one starts
+func (f giftFulfilment) redeem(code domain.GiftCode, userID int) (models.RedeemedGift, error) {
+	now := time.Now()
+	switch {
+	case code.RedeemedBy != nil:
+		return models.RedeemedGift{}, errors.New("gift code has already been redeemed")
+	case !code.ExpiresAt.After(now):
+		return models.RedeemedGift{}, errors.New("gift code has expired")
+	case code.CreatorID == userID:
+		return models.RedeemedGift{}, errors.New("cannot redeem a gift for your own tier")
+	}
+
+	subscription := domain.SubscriptionList{
+		CreatorID: code.CreatorID,
+		UserID:    userID,
+		PlanID:    code.PlanID,
+		PaymentID: fmt.Sprintf("gift_%d", code.ID),
+	}
+	activation, err := subscriptionActivation(f.subscriptions, subscription, now)
+	if err != nil {
+		return models.RedeemedGift{}, err
+	}
+
+	redeemed, err := f.gifts.RedeemGiftCode(code.ID, &subscription, activation)
+	if err != nil {
+		return models.RedeemedGift{}, err
+	}
+	if !redeemed {
+		return models.RedeemedGift{}, errors.New("gift code has already been redeemed or has expired")
+	}
+
+	return models.RedeemedGift{
+		SubscriptionID: subscription.ID,
+		CreatorID:      subscription.CreatorID,
+		PlanID:         subscription.PlanID,
+		Kind:           activation.Kind,
+		PeriodEnd:      activation.PeriodEnd,
+	}, nil
+}
+
+// refund takes a refund the gateway processed out of the ledger, once per refund. A purchase refunded in full
+// has its unredeemed codes expired.
+func (f giftFulfilment) refund(purchase domain.GiftPurchase, refundID string, amount int64) error {
+	created, err := postRefund(f.ledger, purchase.PaymentID, refundID, amount)
+	if err != nil || !created {
+		return err
+	}
+
+	return f.gifts.AddGiftRefund(purchase.ID, amount)
+}
+
+// notify stores a notification for the user, a failure here must not fail the purchase
+func (f giftFulfilment) notify(userID int, kind, title, message string) {
+	notification := domain.Notification{
+		UserID:  userID,
+		Type:    kind,
+		Title:   title,
+		Message: message,
+	}
+	if err := f.notifications.CreateNotification(&notification); err != nil {
+		log.Println("Error creating notification:", err)
+	}
+}
+
+// newGiftCode makes a random code such as GIFT-7KQ2-M9XD-4HTP
+func newGiftCode() (string, error) {
+	random := make([]byte, 12)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+
+	var code strings.Builder
+	code.WriteString("GIFT")
+	for i, b := range random {
+		if i%4 == 0 {
+			code.WriteByte('-')
+		}
+		code.WriteByte(giftCodeAlphabet[int(b)%len(giftCodeAlphabet)])
+	}
+
+	return code.String(), nil
+}
+
+// withGiftCodeStatus tells for each code whether it can still be redeemed
+func withGiftCodeStatus(codes []domain.GiftCode) []domain.GiftCode {
+	if codes == nil {
+		return []domain.GiftCode{}
+	}
+
+	now := time.Now()
+	for i := range codes {
+		switch {
+		case codes[i].RedeemedBy != nil:
+			codes[i].Status = domain.GiftCodeRedeemed
+		case !codes[i].ExpiresAt.After(now):
+			codes[i].Status = domain.GiftCodeExpired
+		default:
+			codes[i].Status = domain.GiftCodeAvailable
+		}
+	}
+
+	return codes
+}
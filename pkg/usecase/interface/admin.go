@@ -8,10 +8,14 @@ import (
 type AdminUseCase interface {
 	LoginHandler(adminDetails models.AdminLogin) (models.TokenAdmin, error)
 	GetUsers(page int, limit int) ([]models.UserDetailsAtAdmin, error)
-	ToggleBlockUser(id string) error
 	GetReports(page, limit int) ([]domain.Reports, error)
 	AddSubscriptionPlan(name string, duration int, price float64) error
 	DeleteSubscriptionPlan(planID int) error
 	GetSubscriptionPlans() ([]domain.SubscriptionPlan, error)
 	GetUserReports(userId, page, limit int) ([]domain.Reports, int64, error)
+	GetAdminRole(adminID uint) (string, error)
+	ListAdmins() ([]models.AdminAccount, error)
+	CreateAdmin(admin models.CreateAdmin) (models.AdminAccount, error)
+	SetAdminRole(actorID, adminID uint, role string) error
+	SetAdminDisabled(actorID, adminID uint, disabled bool) error
 }
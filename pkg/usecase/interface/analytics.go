@@ -0,0 +1,23 @@
+package interfaces
+
+import (
+	"io"
+	"main/pkg/domain"
+	"main/pkg/utils/models"
+)
+
+type AnalyticsUseCase interface {
+	RollupAnalytics() (int, error)
+	GetTimeSeries(creatorID int, startDate, endDate, granularity string) (models.AnalyticsSeries, error)
+	ListVideoAnalytics(creatorID int, startDate, endDate, sort string, page, limit int) ([]models.VideoAnalytics, error)
+	TopVideos(creatorID int, startDate, endDate, metric string, limit int) ([]models.VideoAnalytics, error)
+	GetVideoTimeSeries(creatorID int, videoID uint, startDate, endDate, granularity string) (models.AnalyticsSeries, error)
+	GetVideoRetention(creatorID int, videoID uint, startDate, endDate string) (models.VideoRetention, error)
+	GetDashboard(startDate, endDate string) (models.Dashboard, error)
+	ExportAnalytics(creatorID int, export models.AnalyticsExport, start func(filename, contentType string) io.Writer) error
+	GetReportSettings(creatorID int) (models.AnalyticsReportSettings, error)
+	SetReportSettings(creatorID int, settings models.AnalyticsReportSettings) error
+	GenerateWeeklyReports() (int, error)
+	ListReports(creatorID int, page, limit int) ([]domain.AnalyticsReport, error)
+	GetReportFile(creatorID int, reportID uint) ([]byte, string, string, error)
+}
@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"main/pkg/domain"
+	"main/pkg/utils/models"
+)
+
+type AuditUseCase interface {
+	Snapshot(targetType, targetID string) *string
+	Record(entry models.AuditEntry)
+	ListLogs(filter models.AuditFilter, page, limit int) ([]domain.AuditLog, error)
+}
@@ -3,12 +3,17 @@ package interfaces
 import (
 	"main/pkg/domain"
 	"main/pkg/utils/models"
+	"mime/multipart"
 )
 
 type CategoryUseCase interface {
-	AddCategory(category string) (domain.Category, error)
-	UpdateCategory(current string, new string) (domain.Category, error)
-	DeleteCategory(categoryID string) error
+	AddCategory(category models.AddCategory) (domain.Category, error)
+	UpdateCategory(update models.UpdateCategory) (domain.Category, error)
+	UploadCategoryIcon(categoryID uint, icon *multipart.FileHeader) (domain.Category, error)
+	DeleteCategory(categoryID string, moveTo string) error
 	GetCategories(page, limit int) ([]domain.Category, error)
+	GetCategoryTree() ([]models.CategoryTree, error)
+	GetCategoryBySlug(slug string) (domain.Category, error)
+	ReorderCategories(orders []models.CategoryOrder) error
 	ListVideosByCategory(categoryID, page, limit int) ([]models.VideoResponses, error)
 }
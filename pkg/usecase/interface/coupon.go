@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"main/pkg/domain"
+	"main/pkg/utils/models"
+)
+
+type CouponUseCase interface {
+	CreateCoupon(coupon models.AddCoupon) (domain.Coupon, error)
+	ListCoupons(creatorID *int, page, limit int) ([]domain.Coupon, error)
+	DeactivateCoupon(creatorID *int, couponID uint) error
+}
@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"main/pkg/domain"
+	"main/pkg/utils/models"
+)
+
+type EnforcementUseCase interface {
+	IssueAction(adminID uint, request models.EnforcementRequest) (domain.EnforcementAction, error)
+	RevokeAction(actionID, adminID uint, reason string) (domain.EnforcementAction, error)
+	ToggleBan(adminID uint, userID int, reason string) (domain.EnforcementAction, bool, error)
+	ListActions(userID int, page, limit int) ([]domain.EnforcementAction, error)
+	GetActiveSuspension(userID int) (domain.EnforcementAction, bool, error)
+	Appeal(userID int, request models.AppealRequest) (domain.Appeal, error)
+	AppealSuspension(request models.SuspensionAppeal) (domain.Appeal, error)
+	ListUserAppeals(userID int) ([]domain.Appeal, error)
+	ListAppeals(status string, page, limit int) ([]domain.Appeal, error)
+	DecideAppeal(appealID, adminID uint, decision models.AppealDecision) (domain.Appeal, error)
+}
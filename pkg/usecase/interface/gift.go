@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"main/pkg/domain"
+	"main/pkg/utils/models"
+)
+
+type GiftUseCase interface {
+	PurchaseGift(buyerID int, order models.GiftOrder) (models.GiftCheckout, error)
+	VerifyGiftPayment(buyerID int, purchaseID uint, paymentID, razorID, signature string) ([]domain.GiftCode, error)
+	RedeemGiftCode(userID int, code string) (models.RedeemedGift, error)
+	ListGiftPurchases(buyerID int, page, limit int) ([]domain.GiftPurchase, error)
+	ListGiftCodes(buyerID int, purchaseID uint) ([]domain.GiftCode, error)
+}
@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"main/pkg/domain"
+	"main/pkg/utils/models"
+)
+
+type InvoiceUseCase interface {
+	ListInvoices(userID int, page, limit int) ([]domain.Invoice, error)
+	GetInvoiceView(userID int, invoiceID uint) (models.InvoiceView, error)
+	GetInvoicePDF(userID int, invoiceID uint) ([]byte, string, error)
+}
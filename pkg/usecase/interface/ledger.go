@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"main/pkg/domain"
+	"main/pkg/utils/models"
+)
+
+type LedgerUseCase interface {
+	ListTransactions(kind string, creatorID int, page, limit int) ([]domain.LedgerTransaction, error)
+	GetCreatorEarnings(creatorID int) ([]models.CreatorEarnings, error)
+	Reconcile(startDate, endDate string) (models.LedgerReconciliation, error)
+	GetPlatformFee() (models.PlatformFee, error)
+	SetPlatformFee(fee models.PlatformFee) error
+}
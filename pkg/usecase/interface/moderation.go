@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"main/pkg/domain"
+	"main/pkg/utils/models"
+)
+
+type ModerationUseCase interface {
+	Report(reporterID int, request models.ReportRequest) (domain.Reports, error)
+	ListCases(filter models.CaseFilter, page, limit int) ([]domain.ModerationCase, error)
+	GetCase(caseID uint) (domain.ModerationCase, error)
+	AssignCase(caseID uint, assigneeID *uint) error
+	AddCaseNote(caseID, adminID uint, note string) (domain.CaseNote, error)
+	ResolveCase(caseID, adminID uint, resolution models.CaseResolution) (domain.ModerationCase, error)
+}
@@ -0,0 +1,8 @@
+package interfaces
+
+import "main/pkg/utils/models"
+
+type NotificationUseCase interface {
+	GetNotifications(userID int, unreadOnly bool, page, limit int) (models.NotificationList, error)
+	MarkNotificationsRead(userID int, notificationIDs []uint) error
+}
@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"main/pkg/domain"
+	"main/pkg/utils/models"
+)
+
+type PayoutUseCase interface {
+	GetPayoutAccount(creatorID int) (domain.PayoutAccount, error)
+	SavePayoutAccount(creatorID int, details models.PayoutAccountDetails) (domain.PayoutAccount, error)
+	GetBalance(creatorID int) ([]models.PayoutBalance, error)
+	ListCreatorPayouts(creatorID int, page, limit int) ([]domain.Payout, error)
+	GetPayoutSettings() (models.PayoutSettings, error)
+	SetPayoutSettings(settings models.PayoutSettings) error
+	CreateMonthlyBatches() ([]domain.PayoutBatch, int, error)
+	ListPayoutBatches(page, limit int) ([]domain.PayoutBatch, error)
+	GetPayoutBatch(batchID uint) (domain.PayoutBatch, error)
+	ApprovePayoutBatch(batchID uint) error
+	MarkPayoutPaid(payoutID uint, reference string) error
+}
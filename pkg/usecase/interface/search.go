@@ -0,0 +1,12 @@
+package interfaces
+
+import "main/pkg/utils/models"
+
+type SearchUseCase interface {
+	SearchAll(userID int, filter models.SearchFilter) (models.SearchResults, error)
+	RecordClick(userID int, searchID uint, clickedType string, clickedID int) error
+	TopQueries(startDate, endDate string, limit int) ([]models.SearchQueryStat, error)
+	ZeroResultQueries(startDate, endDate string, limit int) ([]models.SearchQueryStat, error)
+	SearchTrends(interval, startDate, endDate, query string) ([]models.SearchTrendPoint, error)
+	Suggest(query string, limit int) (models.SuggestResults, error)
+}
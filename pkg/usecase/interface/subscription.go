@@ -1,11 +1,25 @@
 package interfaces
 
-import "main/pkg/utils/models"
+import (
+	"main/pkg/domain"
+	"main/pkg/utils/models"
+)
 
 type SubscriptionUseCase interface {
-	PurchasePlan(planID int, creatorID int, userID int) (string, error)
-	MakePaymentRazorPay(planID string, userID int) (models.OrderPaymentDetails, error)
-	VerifyPayment(paymentID string, razorID string, orderID string) error
+	PurchasePlan(planID int, creatorID int, userID int, code string) (models.PurchaseResult, error)
+	MakePaymentRazorPay(planID string, userID int, code string) (models.OrderPaymentDetails, error)
+	SimulatePayment(razorID string) (models.SimulatedPayment, error)
+	VerifyPayment(userID int, paymentID string, razorID string, orderID string, signature string) error
+	HandleWebhook(body []byte, signature string, eventID string) error
+	ReplayPaymentEvent(eventID uint) (domain.PaymentEvent, error)
+	ListPaymentEvents(status string, page, limit int) ([]domain.PaymentEvent, error)
+	RefundSubscription(request models.RefundRequest) (domain.PaymentRefund, error)
+	ListDisputes(needsReview bool, page, limit int) ([]domain.PaymentDispute, error)
+	ReviewDispute(review models.DisputeReview) error
 	GetAnalytics(userID int, startDate string, endDate string) (models.AnalyticsData, error)
+	ProcessSubscriptionLifecycle() (models.LifecycleResult, error)
+	CancelSubscription(userID, subscriptionID int) error
+	ResumeSubscription(userID, subscriptionID int) error
+	ListSubscriptions(userID int) ([]models.UserSubscription, error)
 	// StartSubscriptionUpdateJob()
 }
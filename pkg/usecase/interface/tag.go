@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"main/pkg/domain"
+	"main/pkg/utils/models"
+)
+
+type TagUseCase interface {
+	AddAlias(tagID uint, alias string) (domain.TagAlias, error)
+	DeleteAlias(aliasID uint) error
+	GetAliases(tagID uint) ([]domain.TagAlias, error)
+	MergeTags(sourceID, targetID uint) (models.TagMergeResult, error)
+	BanTag(tag, reason string) (domain.BannedTag, error)
+	UnbanTag(bannedID uint) error
+	GetBannedTags(page, limit int) ([]domain.BannedTag, error)
+	GetTagStats(page, limit int) ([]models.TagStat, error)
+}
@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"main/pkg/domain"
+	"main/pkg/utils/models"
+)
+
+type TaxUseCase interface {
+	ListTaxRules() ([]domain.TaxRule, error)
+	SetTaxRule(details models.TaxRuleDetails) (domain.TaxRule, error)
+	DeleteTaxRule(region string) error
+}
@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"main/pkg/money"
+	"main/pkg/utils/models"
+)
+
+type TierUseCase interface {
+	CreateTier(creatorID int, tier models.AddTier) (models.Tier, error)
+	UpdateTier(creatorID int, tier models.UpdateTier) (models.Tier, error)
+	DeleteTier(creatorID, tierID int) (bool, error)
+	ListTiers(creatorID int) ([]models.Tier, error)
+	SetTierVideos(creatorID int, tierVideos models.TierVideos) ([]uint, error)
+	SetTierPrices(creatorID int, tierPrices models.TierPrices) (map[string]money.Money, error)
+	GetTierPriceLimits() (models.TierPriceLimits, error)
+	SetTierPriceLimits(limits models.TierPriceLimits) error
+}
@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"main/pkg/domain"
+	"main/pkg/utils/models"
+)
+
+type TipUseCase interface {
+	GetTipLimits() (models.TipLimits, error)
+	SetTipLimits(limits models.TipLimits) error
+	SendTip(userID int, order models.TipOrder) (models.TipCheckout, error)
+	VerifyTipPayment(userID int, tipID uint, paymentID, razorID, signature string) (domain.Tip, error)
+	ListSentTips(userID int, page, limit int) ([]models.TipDetails, error)
+	ListReceivedTips(creatorID int, page, limit int) ([]models.TipDetails, error)
+	ListVideoTips(videoID uint, page, limit int) ([]models.TipDetails, error)
+}
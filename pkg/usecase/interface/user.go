@@ -12,10 +12,11 @@ type UserUseCase interface {
 	Login(user models.UserLogin) (models.TokenUser, error)
 	EditProfile(id int, name, email, username, phone, bio string, image *multipart.FileHeader) error
 	GetProfile(id int) (*models.UserProfileResponse, error)
-	ReportUser(reporterID, targetID int, reason string) error
+	GetBillingCountry(id int) (models.BillingCountry, error)
+	SetBillingCountry(id int, country string) (models.BillingCountry, error)
 	ToggleFollow(followerID, followingID int) error
 	GetFollowingListWithPagination(userID int, page, limit int) ([]models.FollowingUser, error)
-	SearchUsersByNameWithPagination(searchTerm string, page, limit int) ([]domain.User, error)
+	SearchUsersByNameWithPagination(userID int, searchTerm string, page, limit int) ([]domain.User, error)
 	GetSubscriptionPlans() ([]domain.SubscriptionPlan, error)
 	GetFollowersListWithPagination(userID int, page, limit int) ([]models.FollowerUser, error)
 }
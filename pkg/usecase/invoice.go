@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"errors"
+	"main/pkg/domain"
+	"main/pkg/invoice"
+	"main/pkg/money"
+	interfaces "main/pkg/repository/interface"
+	services "main/pkg/usecase/interface"
+	"main/pkg/utils/models"
+)
+
+type invoiceUseCase struct {
+	repository interfaces.InvoiceRepository
+}
+
+func NewInvoiceUseCase(repo interfaces.InvoiceRepository) services.InvoiceUseCase {
+	return &invoiceUseCase{
+		repository: repo,
+	}
+}
+
+// invoiceIssuer is the platform, which collects the payment on the creator's behalf
+const invoiceIssuer = "GameVerse"
+
+func (i *invoiceUseCase) ListInvoices(userID int, page, limit int) ([]domain.Invoice, error) {
+	if limit > 100 {
+		limit = 100
+	}
+
+	invoices, err := i.repository.ListUserInvoices(userID, page, limit)
+	if err != nil {
+		return nil, err
+	}
+	if invoices == nil {
+		invoices = []domain.Invoice{}
+	}
+
+	return invoices, nil
+}
+
+// ownInvoice loads the invoice and makes sure it was issued to the user
+func (i *invoiceUseCase) ownInvoice(userID int, invoiceID uint) (domain.Invoice, error) {
+	inv, err := i.repository.GetInvoice(invoiceID)
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+	if inv.UserID != userID {
+		return domain.Invoice{}, errors.New("invoice not found")
+	}
+
+	return inv, nil
+}
+
+func (i *invoiceUseCase) GetInvoiceView(userID int, invoiceID uint) (models.InvoiceView, error) {
+	inv, err := i.ownInvoice(userID, invoiceID)
+	if err != nil {
+		return models.InvoiceView{}, err
+	}
+
+	return invoiceView(inv), nil
+}
+
+// GetInvoicePDF returns the invoice as a PDF with the file name to download it as.
+func (i *invoiceUseCase) GetInvoicePDF(userID int, invoiceID uint) ([]byte, string, error) {
+	inv, err := i.ownInvoice(userID, invoiceID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	document, err := invoice.PDF(invoiceView(inv))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return document, inv.Number + ".pdf", nil
+}
+
+func invoiceView(inv domain.Invoice) models.InvoiceView {
+	view := models.InvoiceView{
+		Issuer:      invoiceIssuer,
+		Number:      inv.Number,
+		IssuedAt:    inv.IssuedAt.Format("2 Jan 2006"),
+		BuyerName:   inv.BuyerName,
+		BuyerEmail:  inv.BuyerEmail,
+		CreatorName: inv.CreatorName,
+		PlanName:    inv.PlanName,
+		PaymentID:   inv.PaymentID,
+		Total:       formatAmount(inv.Total, inv.Currency),
+	}
+
+	if inv.PeriodStart != nil && inv.PeriodEnd != nil {
+		view.Period = inv.PeriodStart.Format("2 Jan 2006") + " to " + inv.PeriodEnd.Format("2 Jan 2006")
+	}
+
+	view.Lines = append(view.Lines, models.InvoiceLine{Label: "Subtotal", Amount: formatAmount(inv.Subtotal, inv.Currency)})
+	if inv.Discount > 0 {
+		label := "Discount"
+		if inv.CouponCode != "" {
+			label += " (" + inv.CouponCode + ")"
+		}
+		view.Lines = append(view.Lines, models.InvoiceLine{Label: label, Amount: "-" + formatAmount(inv.Discount, inv.Currency)})
+	}
+
+	if inv.Tax > 0 {
+		view.Lines = append(view.Lines, models.InvoiceLine{
+			Label:  taxLabel(models.TaxQuote{Name: inv.TaxLabel, Rate: inv.TaxRate, Inclusive: inv.TaxInclusive, Amount: inv.Tax}),
+			Amount: formatAmount(inv.Tax, inv.Currency),
+		})
+	} else {
+		view.TaxNote = "No tax was charged on this payment."
+	}
+
+	return view
+}
+
+// formatAmount shows an amount in minor units in the major unit, e.g. 49900 INR as 499.00 INR
+func formatAmount(amount int64, currency string) string {
+	return money.Money{Amount: amount, Currency: currency}.String()
+}
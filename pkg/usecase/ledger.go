@@ -0,0 +1,321 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"main/pkg/domain"
+	"main/pkg/payments"
+	interfaces "main/pkg/repository/interface"
+	services "main/pkg/usecase/interface"
+	"main/pkg/utils/models"
+	"math"
+	"sort"
+	"strconv"
+)
+
+type ledgerUseCase struct {
+	repository interfaces.LedgerRepository
+	settings   interfaces.SettingRepository
+	gateway    payments.Gateway
+}
+
+func NewLedgerUseCase(repo interfaces.LedgerRepository, settingRepo interfaces.SettingRepository, gateway payments.Gateway) services.LedgerUseCase {
+	return &ledgerUseCase{
+		repository: repo,
+		settings:   settingRepo,
+		gateway:    gateway,
+	}
+}
+
+const (
+	// maxReconcileCharges caps the gateway lookups one report makes
+	maxReconcileCharges = 500
+)
+
+func platformFeePercent(settings interfaces.SettingRepository) (float64, error) {
+	values, err := settings.GetSettings(domain.PlatformFeeKey)
+	if err != nil {
+		return 0, err
+	}
+
+	value, ok := values[domain.PlatformFeeKey]
+	if !ok {
+		return domain.DefaultPlatformFeePercent, nil
+	}
+
+	percent, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s setting: %w", domain.PlatformFeeKey, err)
+	}
+
+	return percent, nil
+}
+
+// chargeTransaction records money collected for a subscription: the gateway holds it, the tax in it is owed
+// to the authorities, and of the rest the platform keeps its fee and owes the creator what is left.
+func chargeTransaction(subscription domain.SubscriptionList, paymentID string, feePercent float64) domain.LedgerTransaction {
+	transaction := chargeEntries(subscription.CreatorID, subscription.Amount, subscription.Tax, feePercent)
+	transaction.Reference = paymentID
+	transaction.SubscriptionListID = &subscription.ID
+	transaction.Currency = subscription.Currency
+	transaction.Description = fmt.Sprintf("subscription %d paid by user %d", subscription.ID, subscription.UserID)
+
+	return transaction
+}
+
+// giftChargeTransaction records money collected for gift codes, split like a subscription payment
+func giftChargeTransaction(purchase domain.GiftPurchase, paymentID string, feePercent float64) domain.LedgerTransaction {
+	transaction := chargeEntries(purchase.CreatorID, purchase.Amount, purchase.Tax, feePercent)
+	transaction.Reference = paymentID
+	transaction.Currency = purchase.Currency
+	transaction.Description = fmt.Sprintf("gift purchase %d of %d codes paid by user %d", purchase.ID, purchase.Quantity, purchase.BuyerID)
+
+	return transaction
+}
+
+// tipChargeTransaction records a tip, split like a subscription payment
+func tipChargeTransaction(tip domain.Tip, paymentID string, feePercent float64) domain.LedgerTransaction {
+	transaction := chargeEntries(tip.CreatorID, tip.Amount, 0, feePercent)
+	transaction.Reference = paymentID
+	transaction.Currency = tip.Currency
+	transaction.Description = fmt.Sprintf("tip %d paid by user %d", tip.ID, tip.UserID)
+
+	return transaction
+}
+
+// chargeEntries splits an amount collected for the creator between the tax in it, the platform's fee and the creator
+func chargeEntries(creatorID int, amount, tax int64, feePercent float64) domain.LedgerTransaction {
+	net := amount - tax
+	fee := int64(math.Round(float64(net) * feePercent / 100))
+
+	entries := []domain.LedgerEntry{{Account: domain.AccountGatewayClearing, Debit: amount}}
+	if tax > 0 {
+		entries = append(entries, domain.LedgerEntry{Account: domain.AccountTaxPayable, Credit: tax})
+	}
+	entries = append(entries,
+		domain.LedgerEntry{Account: domain.AccountPlatformRevenue, Credit: fee},
+		domain.LedgerEntry{Account: domain.AccountCreatorPayable, CreatorID: creatorID, Credit: net - fee},
+	)
+
+	return domain.LedgerTransaction{
+		Type:      domain.LedgerCharge,
+		CreatorID: creatorID,
+		Entries:   entries,
+	}
+}
+
+// postRefund posts a refund the gateway processed against the charge of its payment, created is false when it was already posted
+func postRefund(ledger interfaces.LedgerRepository, paymentID, refundID string, amount int64) (bool, error) {
+	charge, found, err := ledger.GetTransaction(domain.LedgerCharge, paymentID)
+	if err != nil {
+		return false, err
+	}
+	if !found {
+		return false, errors.New("the ledger has no charge for the refunded payment")
+	}
+
+	transaction, err := refundTransaction(charge, refundID, amount)
+	if err != nil {
+		return false, err
+	}
+
+	return ledger.PostTransaction(&transaction)
+}
+
+// refundTransaction reverses part of a charge, every account the charge credited gives back its share of it in proportion.
+// The creator's share takes the rounding, so the refund always balances.
+func refundTransaction(charge domain.LedgerTransaction, refundID string, amount int64) (domain.LedgerTransaction, error) {
+	var charged int64
+	for _, entry := range charge.Entries {
+		if entry.Account == domain.AccountGatewayClearing {
+			charged += entry.Debit
+		}
+	}
+	if charged <= 0 {
+		return domain.LedgerTransaction{}, errors.New("charge has no amount to refund")
+	}
+	if amount <= 0 || amount > charged {
+		return domain.LedgerTransaction{}, errors.New("refund amount must be between 1 and the charged amount")
+	}
+
+	entries := []domain.LedgerEntry{}
+	creatorShare := amount
+	for _, entry := range charge.Entries {
+		if entry.Account == domain.AccountGatewayClearing || entry.Account == domain.AccountCreatorPayable || entry.Credit <= 0 {
+			continue
+		}
+		share := int64(math.Round(float64(amount) * float64(entry.Credit) / float64(charged)))
+		creatorShare -= share
+		entries = append(entries, domain.LedgerEntry{Account: entry.Account, Debit: share})
+	}
+	entries = append(entries,
+		domain.LedgerEntry{Account: domain.AccountCreatorPayable, CreatorID: charge.CreatorID, Debit: creatorShare},
+		domain.LedgerEntry{Account: domain.AccountGatewayClearing, Credit: amount},
+	)
+
+	return domain.LedgerTransaction{
+		Type:               domain.LedgerRefund,
+		Reference:          refundID,
+		SubscriptionListID: charge.SubscriptionListID,
+		CreatorID:          charge.CreatorID,
+		Currency:           charge.Currency,
+		Description:        "refund of payment " + charge.Reference,
+		Entries:            entries,
+	}, nil
+}
+
+func (l *ledgerUseCase) ListTransactions(kind string, creatorID int, page, limit int) ([]domain.LedgerTransaction, error) {
+	switch kind {
+	case "", domain.LedgerCharge, domain.LedgerRefund, domain.LedgerPayout:
+	default:
+		return nil, errors.New("type must be one of charge, refund, payout")
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	transactions, err := l.repository.ListTransactions(kind, creatorID, page, limit)
+	if err != nil {
+		return nil, err
+	}
+	if transactions == nil {
+		transactions = []domain.LedgerTransaction{}
+	}
+
+	return transactions, nil
+}
+
+// GetCreatorEarnings returns the creator's earnings in each currency they earned in, the platform currency always comes first.
+func (l *ledgerUseCase) GetCreatorEarnings(creatorID int) ([]models.CreatorEarnings, error) {
+	totals, err := l.repository.GetCreatorTotals(creatorID, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	earnings := []models.CreatorEarnings{}
+	for _, currency := range totalCurrencies(totals, l.gateway.Currency()) {
+		byType := totals[currency]
+		earned, refunded, paidOut := byType[domain.LedgerCharge], byType[domain.LedgerRefund], byType[domain.LedgerPayout]
+
+		earnings = append(earnings, models.CreatorEarnings{
+			Earned:   majorAmount(earned, currency),
+			Refunded: majorAmount(refunded, currency),
+			PaidOut:  majorAmount(paidOut, currency),
+			Balance:  majorAmount(earned-refunded-paidOut, currency),
+			Currency: currency,
+		})
+	}
+
+	return earnings, nil
+}
+
+// totalCurrencies lists the platform currency and then the other currencies of the totals alphabetically
+func totalCurrencies(totals map[string]map[string]int64, platform string) []string {
+	currencies := []string{}
+	for currency := range totals {
+		if currency != platform {
+			currencies = append(currencies, currency)
+		}
+	}
+	sort.Strings(currencies)
+
+	return append([]string{platform}, currencies...)
+}
+
+// Reconcile checks the ledger against the gateway for the period, the last 30 days by default: every charge must match
+// a captured payment of the same amount and refunds, every paid subscription must have a charge, and every transaction must balance.
+func (l *ledgerUseCase) Reconcile(startDate, endDate string) (models.LedgerReconciliation, error) {
+	from, to, err := parseDateRange(startDate, endDate)
+	if err != nil {
+		return models.LedgerReconciliation{}, err
+	}
+
+	report := models.LedgerReconciliation{From: from, To: to, Issues: []models.ReconcileIssue{}}
+
+	charges, err := l.repository.GetCharges(from, to, maxReconcileCharges+1)
+	if err != nil {
+		return models.LedgerReconciliation{}, err
+	}
+	if len(charges) > maxReconcileCharges {
+		charges = charges[:maxReconcileCharges]
+		report.Truncated = true
+	}
+	report.Charges = len(charges)
+
+	for _, charge := range charges {
+		issue := models.ReconcileIssue{
+			SubscriptionListID: charge.SubscriptionListID,
+			PaymentID:          charge.PaymentID,
+			LedgerAmount:       charge.Amount,
+			LedgerRefunded:     charge.Refunded,
+		}
+
+		payment, err := l.gateway.FetchPayment(charge.PaymentID)
+		if err != nil {
+			issue.Issue = "payment could not be fetched from the gateway: " + err.Error()
+			report.Issues = append(report.Issues, issue)
+			continue
+		}
+		issue.GatewayAmount = payment.Amount
+		issue.GatewayRefunded = payment.AmountRefunded
+
+		switch {
+		case payment.Status != "captured" && payment.Status != "refunded":
+			issue.Issue = "payment is " + payment.Status + " on the gateway"
+		case payment.Currency != charge.Currency:
+			issue.Issue = "charged currency differs from the gateway"
+		case payment.Amount != charge.Amount:
+			issue.Issue = "charged amount differs from the gateway"
+		case payment.AmountRefunded != charge.Refunded:
+			issue.Issue = "refunded amount differs from the gateway"
+		default:
+			report.Matched++
+			continue
+		}
+		report.Issues = append(report.Issues, issue)
+	}
+
+	missing, err := l.repository.GetPaidSubscriptionsWithoutCharge(from, to)
+	if err != nil {
+		return models.LedgerReconciliation{}, err
+	}
+	for _, subscription := range missing {
+		report.Issues = append(report.Issues, models.ReconcileIssue{
+			SubscriptionListID: subscription.ID,
+			PaymentID:          subscription.PaymentID,
+			Issue:              "subscription is paid but the ledger has no charge for it",
+			GatewayAmount:      subscription.Amount,
+		})
+	}
+
+	report.UnbalancedTransactions, err = l.repository.GetUnbalancedTransactions()
+	if err != nil {
+		return models.LedgerReconciliation{}, err
+	}
+
+	return report, nil
+}
+
+func (l *ledgerUseCase) GetPlatformFee() (models.PlatformFee, error) {
+	percent, err := platformFeePercent(l.settings)
+	if err != nil {
+		return models.PlatformFee{}, err
+	}
+
+	return models.PlatformFee{Percent: percent}, nil
+}
+
+// SetPlatformFee changes the fee taken from future charges, charges already in the ledger keep theirs.
+func (l *ledgerUseCase) SetPlatformFee(fee models.PlatformFee) error {
+	if fee.Percent < 0 || fee.Percent > 100 {
+		return errors.New("fee must be between 0 and 100 percent")
+	}
+
+	return l.settings.SetSettings(map[string]string{
+		domain.PlatformFeeKey: strconv.FormatFloat(fee.Percent, 'f', -1, 64),
+	})
+}
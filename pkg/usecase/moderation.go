@@ -0,0 +1,228 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"main/pkg/domain"
+	interfaces "main/pkg/repository/interface"
+	services "main/pkg/usecase/interface"
+	"main/pkg/utils/models"
+	"strings"
+)
+
+const (
+	maxReasonLength   = 60
+	maxCaseNoteLength = 2000
+)
+
+var reportCategories = map[string]bool{
+	domain.ReportSpam:          true,
+	domain.ReportHarassment:    true,
+	domain.ReportCheating:      true,
+	domain.ReportImpersonation: true,
+	domain.ReportCopyright:     true,
+	domain.ReportOther:         true,
+}
+
+type moderationUseCase struct {
+	repository    interfaces.ModerationRepository
+	notifications interfaces.NotificationRepository
+}
+
+func NewModerationUseCase(repo interfaces.ModerationRepository, notificationRepo interfaces.NotificationRepository) services.ModerationUseCase {
+	return &moderationUseCase{
+		repository:    repo,
+		notifications: notificationRepo,
+	}
+}
+
+// Report files the user's report of a user, video or comment into the case for it, reporting the same thing for the same
+// reason again while its case is handled is refused so duplicates do not inflate the count.
+func (m *moderationUseCase) Report(reporterID int, request models.ReportRequest) (domain.Reports, error) {
+	if !reportCategories[request.Category] {
+		return domain.Reports{}, errors.New("category must be spam, harassment, cheating, impersonation, copyright or other")
+	}
+
+	reason := strings.TrimSpace(request.Reason)
+	if len(reason) > maxReasonLength {
+		return domain.Reports{}, errors.New("reason : length exceeds the limit")
+	}
+
+	targetUserID, found, err := m.repository.FindReportTarget(request.TargetType, request.TargetID)
+	if err != nil {
+		return domain.Reports{}, err
+	}
+	if !found {
+		return domain.Reports{}, fmt.Errorf("%s not found", request.TargetType)
+	}
+	if targetUserID == reporterID {
+		return domain.Reports{}, errors.New("cannot report yourself")
+	}
+
+	reported, err := m.repository.HasOpenReport(reporterID, request.TargetType, request.TargetID, request.Category)
+	if err != nil {
+		return domain.Reports{}, err
+	}
+	if reported {
+		return domain.Reports{}, errors.New("you already reported this and it is being reviewed")
+	}
+
+	report := domain.Reports{
+		ReporterID:   reporterID,
+		TargetType:   request.TargetType,
+		TargetID:     request.TargetID,
+		TargetUserID: targetUserID,
+		Category:     request.Category,
+		Reason:       reason,
+	}
+	if _, err := m.repository.FileReport(&report); err != nil {
+		return domain.Reports{}, err
+	}
+
+	return report, nil
+}
+
+// ListCases returns the moderation queue, the most reported cases first.
+func (m *moderationUseCase) ListCases(filter models.CaseFilter, page, limit int) ([]domain.ModerationCase, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	return m.repository.ListCases(filter, page, limit)
+}
+
+func (m *moderationUseCase) GetCase(caseID uint) (domain.ModerationCase, error) {
+	return m.repository.GetCase(caseID)
+}
+
+// AssignCase gives the case to an admin, or back to the queue without one.
+func (m *moderationUseCase) AssignCase(caseID uint, assigneeID *uint) error {
+	if assigneeID != nil {
+		exists, err := m.repository.AdminExists(*assigneeID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return errors.New("admin not found")
+		}
+	}
+
+	assigned, err := m.repository.AssignCase(caseID, assigneeID)
+	if err != nil {
+		return err
+	}
+	if !assigned {
+		return errors.New("case not found or already closed")
+	}
+
+	return nil
+}
+
+func (m *moderationUseCase) AddCaseNote(caseID, adminID uint, note string) (domain.CaseNote, error) {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return domain.CaseNote{}, errors.New("note cannot be empty")
+	}
+	if len(note) > maxCaseNoteLength {
+		return domain.CaseNote{}, fmt.Errorf("note can be at most %d characters", maxCaseNoteLength)
+	}
+
+	if _, err := m.repository.GetCase(caseID); err != nil {
+		return domain.CaseNote{}, err
+	}
+
+	caseNote := domain.CaseNote{
+		CaseID:  caseID,
+		AdminID: adminID,
+		Note:    note,
+	}
+	if err := m.repository.AddCaseNote(&caseNote); err != nil {
+		return domain.CaseNote{}, err
+	}
+
+	return caseNote, nil
+}
+
+// ResolveCase closes the case with the action, which is taken on the target, and lets every reporter know their report was reviewed.
+// The note, if any, is kept on the case, the reason is what the user whose content is removed or who is blocked is told.
+func (m *moderationUseCase) ResolveCase(caseID, adminID uint, resolution models.CaseResolution) (domain.ModerationCase, error) {
+	moderationCase, err := m.repository.GetCase(caseID)
+	if err != nil {
+		return domain.ModerationCase{}, err
+	}
+	if moderationCase.Status == domain.CaseResolved || moderationCase.Status == domain.CaseDismissed {
+		return domain.ModerationCase{}, errors.New("case is already closed")
+	}
+
+	switch resolution.Action {
+	case domain.ModerationDismiss, domain.ModerationBlockUser:
+	case domain.ModerationRemoveContent:
+		if moderationCase.TargetType == domain.ReportTargetUser {
+			return domain.ModerationCase{}, errors.New("only videos and comments can be removed, block the user instead")
+		}
+	default:
+		return domain.ModerationCase{}, errors.New("action must be dismiss, remove_content or block_user")
+	}
+
+	reason := strings.TrimSpace(resolution.Reason)
+	if reason == "" {
+		reason = moderationCase.Category
+	}
+	if len(reason) > maxEnforcementReasonLength {
+		return domain.ModerationCase{}, errors.New("reason : length exceeds the limit")
+	}
+
+	if strings.TrimSpace(resolution.Note) != "" {
+		if _, err := m.AddCaseNote(caseID, adminID, resolution.Note); err != nil {
+			return domain.ModerationCase{}, err
+		}
+	}
+
+	resolved, err := m.repository.ResolveCase(caseID, adminID, resolution.Action, reason)
+	if err != nil {
+		return domain.ModerationCase{}, err
+	}
+	if !resolved {
+		return domain.ModerationCase{}, errors.New("case is already closed")
+	}
+
+	m.notifyReporters(moderationCase, resolution.Action)
+	switch resolution.Action {
+	case domain.ModerationRemoveContent:
+		notifyEnforcement(m.notifications, moderationCase.TargetUserID, domain.EnforcementTakedown, reason, nil)
+	case domain.ModerationBlockUser:
+		notifyEnforcement(m.notifications, moderationCase.TargetUserID, domain.EnforcementBan, reason, nil)
+	}
+
+	return m.repository.GetCase(caseID)
+}
+
+// notifyReporters tells the reporters the outcome without saying what was done to whom, a failure here must not fail the resolution
+func (m *moderationUseCase) notifyReporters(moderationCase domain.ModerationCase, action string) {
+	reporterIDs, err := m.repository.ListCaseReporters(moderationCase.ID)
+	if err != nil {
+		log.Println("Error finding the reporters:", err)
+		return
+	}
+
+	message := fmt.Sprintf("Thanks for your report of a %s. We reviewed it and took action.", moderationCase.TargetType)
+	if action == domain.ModerationDismiss {
+		message = fmt.Sprintf("Thanks for your report of a %s. We reviewed it and found it does not break our rules.", moderationCase.TargetType)
+	}
+
+	for _, reporterID := range reporterIDs {
+		notification := domain.Notification{
+			UserID:  reporterID,
+			Type:    "report_resolved",
+			Title:   "Your report was reviewed",
+			Message: message,
+		}
+		if err := m.notifications.CreateNotification(&notification); err != nil {
+			log.Println("Error creating notification:", err)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"main/pkg/domain"
+	interfaces "main/pkg/repository/interface"
+	services "main/pkg/usecase/interface"
+	"main/pkg/utils/models"
+)
+
+type notificationUseCase struct {
+	repository interfaces.NotificationRepository
+}
+
+func NewNotificationUseCase(repo interfaces.NotificationRepository) services.NotificationUseCase {
+	return &notificationUseCase{
+		repository: repo,
+	}
+}
+
+// GetNotifications returns the user's notifications, newest first, with the number still unread.
+func (n *notificationUseCase) GetNotifications(userID int, unreadOnly bool, page, limit int) (models.NotificationList, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	notifications, err := n.repository.GetNotifications(userID, unreadOnly, page, limit)
+	if err != nil {
+		return models.NotificationList{}, err
+	}
+	if notifications == nil {
+		notifications = []domain.Notification{}
+	}
+
+	unread, err := n.repository.CountUnreadNotifications(userID)
+	if err != nil {
+		return models.NotificationList{}, err
+	}
+
+	return models.NotificationList{Unread: unread, Notifications: notifications}, nil
+}
+
+func (n *notificationUseCase) MarkNotificationsRead(userID int, notificationIDs []uint) error {
+	return n.repository.MarkNotificationsRead(userID, notificationIDs)
+}
@@ -0,0 +1,320 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"main/pkg/domain"
+	"main/pkg/money"
+	"main/pkg/payments"
+	interfaces "main/pkg/repository/interface"
+	services "main/pkg/usecase/interface"
+	"main/pkg/utils/models"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type payoutUseCase struct {
+	repository    interfaces.PayoutRepository
+	ledger        interfaces.LedgerRepository
+	settings      interfaces.SettingRepository
+	notifications interfaces.NotificationRepository
+	gateway       payments.Gateway
+}
+
+func NewPayoutUseCase(repo interfaces.PayoutRepository, ledgerRepo interfaces.LedgerRepository, settingRepo interfaces.SettingRepository, notificationRepo interfaces.NotificationRepository, gateway payments.Gateway) services.PayoutUseCase {
+	return &payoutUseCase{
+		repository:    repo,
+		ledger:        ledgerRepo,
+		settings:      settingRepo,
+		notifications: notificationRepo,
+		gateway:       gateway,
+	}
+}
+
+const (
+	payoutMinAmountKey = "payout_min_amount"
+	payoutHoldDaysKey  = "payout_hold_days"
+
+	// used until an admin sets them, the hold covers the usual window for refunds and disputes
+	defaultPayoutMinAmount = 500
+	defaultPayoutHoldDays  = 14
+
+	maxPayoutHoldDays = 180
+	maxPayoutPageSize = 100
+)
+
+var (
+	accountNumberPattern = regexp.MustCompile(`^[0-9]{9,18}$`)
+	ifscPattern          = regexp.MustCompile(`^[A-Z]{4}0[A-Z0-9]{6}$`)
+	upiIDPattern         = regexp.MustCompile(`^[a-zA-Z0-9._-]{2,256}@[a-zA-Z]{2,64}$`)
+)
+
+func (p *payoutUseCase) GetPayoutAccount(creatorID int) (domain.PayoutAccount, error) {
+	account, found, err := p.repository.GetPayoutAccount(creatorID)
+	if err != nil {
+		return domain.PayoutAccount{}, err
+	}
+	if !found {
+		return domain.PayoutAccount{}, errors.New("no payout account has been added")
+	}
+
+	return account, nil
+}
+
+// SavePayoutAccount sets where the creator is paid, payouts already in a batch keep the account they were made with.
+func (p *payoutUseCase) SavePayoutAccount(creatorID int, details models.PayoutAccountDetails) (domain.PayoutAccount, error) {
+	account := domain.PayoutAccount{
+		CreatorID:     creatorID,
+		Method:        strings.ToLower(strings.TrimSpace(details.Method)),
+		AccountHolder: strings.TrimSpace(details.AccountHolder),
+	}
+
+	switch account.Method {
+	case domain.PayoutMethodBank:
+		account.AccountNumber = strings.TrimSpace(details.AccountNumber)
+		account.IFSC = strings.ToUpper(strings.TrimSpace(details.IFSC))
+		if account.AccountHolder == "" {
+			return domain.PayoutAccount{}, errors.New("account holder is required")
+		}
+		if !accountNumberPattern.MatchString(account.AccountNumber) {
+			return domain.PayoutAccount{}, errors.New("account number must be 9 to 18 digits")
+		}
+		if !ifscPattern.MatchString(account.IFSC) {
+			return domain.PayoutAccount{}, errors.New("invalid IFSC code")
+		}
+	case domain.PayoutMethodUPI:
+		account.UPIID = strings.TrimSpace(details.UPIID)
+		if !upiIDPattern.MatchString(account.UPIID) {
+			return domain.PayoutAccount{}, errors.New("invalid UPI ID")
+		}
+	default:
+		return domain.PayoutAccount{}, errors.New("method must be bank or upi")
+	}
+
+	if err := p.repository.SavePayoutAccount(&account); err != nil {
+		return domain.PayoutAccount{}, err
+	}
+
+	return p.GetPayoutAccount(creatorID)
+}
+
+func (p *payoutUseCase) GetPayoutSettings() (models.PayoutSettings, error) {
+	settings := models.PayoutSettings{MinAmount: defaultPayoutMinAmount, HoldDays: defaultPayoutHoldDays}
+
+	values, err := p.settings.GetSettings(payoutMinAmountKey, payoutHoldDaysKey)
+	if err != nil {
+		return models.PayoutSettings{}, err
+	}
+
+	if value, ok := values[payoutMinAmountKey]; ok {
+		if settings.MinAmount, err = strconv.ParseFloat(value, 64); err != nil {
+			return models.PayoutSettings{}, fmt.Errorf("invalid %s setting: %w", payoutMinAmountKey, err)
+		}
+	}
+	if value, ok := values[payoutHoldDaysKey]; ok {
+		if settings.HoldDays, err = strconv.Atoi(value); err != nil {
+			return models.PayoutSettings{}, fmt.Errorf("invalid %s setting: %w", payoutHoldDaysKey, err)
+		}
+	}
+
+	return settings, nil
+}
+
+func (p *payoutUseCase) SetPayoutSettings(settings models.PayoutSettings) error {
+	if settings.MinAmount <= 0 {
+		return errors.New("minimum payout must be positive")
+	}
+	if settings.HoldDays < 0 || settings.HoldDays > maxPayoutHoldDays {
+		return fmt.Errorf("hold period must be between 0 and %d days", maxPayoutHoldDays)
+	}
+
+	return p.settings.SetSettings(map[string]string{
+		payoutMinAmountKey: strconv.FormatFloat(settings.MinAmount, 'f', -1, 64),
+		payoutHoldDaysKey:  strconv.Itoa(settings.HoldDays),
+	})
+}
+
+// GetBalance returns the creator's balance in each currency they earned in, a creator who earned nothing gets an empty one in the platform currency.
+func (p *payoutUseCase) GetBalance(creatorID int) ([]models.PayoutBalance, error) {
+	settings, err := p.GetPayoutSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	balances, err := p.repository.GetCreatorBalances(creatorID, time.Now().AddDate(0, 0, -settings.HoldDays))
+	if err != nil {
+		return nil, err
+	}
+	if len(balances) == 0 {
+		balances = []models.CreatorBalance{{Currency: p.gateway.Currency()}}
+	}
+
+	result := make([]models.PayoutBalance, 0, len(balances))
+	for _, balance := range balances {
+		available := balance.Eligible - balance.Reserved
+		if available < 0 {
+			available = 0
+		}
+
+		result = append(result, models.PayoutBalance{
+			Balance:   majorAmount(balance.Total, balance.Currency),
+			OnHold:    majorAmount(balance.Total-balance.Eligible, balance.Currency),
+			InPayout:  majorAmount(balance.Reserved, balance.Currency),
+			Available: majorAmount(available, balance.Currency),
+			MinAmount: settings.MinAmount,
+			HoldDays:  settings.HoldDays,
+			Currency:  balance.Currency,
+		})
+	}
+
+	return result, nil
+}
+
+func (p *payoutUseCase) ListCreatorPayouts(creatorID int, page, limit int) ([]domain.Payout, error) {
+	if limit > maxPayoutPageSize {
+		limit = maxPayoutPageSize
+	}
+
+	payouts, err := p.repository.ListCreatorPayouts(creatorID, page, limit)
+	if err != nil {
+		return nil, err
+	}
+	if payouts == nil {
+		payouts = []domain.Payout{}
+	}
+
+	return payouts, nil
+}
+
+// CreateMonthlyBatches makes the batches of the month that just ended, once per currency creators earned in. They pay what
+// creators earned up to the end of that month, except the earnings still within the hold period which wait for the next batch.
+// created is how many of the batches were made now rather than before.
+func (p *payoutUseCase) CreateMonthlyBatches() ([]domain.PayoutBatch, int, error) {
+	settings, err := p.GetPayoutSettings()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	now := time.Now()
+	periodEnd := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	period := periodEnd.AddDate(0, -1, 0).Format("2006-01")
+
+	cutoff := now.AddDate(0, 0, -settings.HoldDays)
+	if cutoff.After(periodEnd) {
+		cutoff = periodEnd
+	}
+
+	currencies, err := p.repository.GetPayableCurrencies()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	batches := []domain.PayoutBatch{}
+	created := 0
+	for _, currency := range currencies {
+		batch, isNew, err := p.repository.CreatePayoutBatch(period, cutoff, money.FromMajor(settings.MinAmount, currency).Amount, currency)
+		if err != nil {
+			return nil, 0, fmt.Errorf("payout batch in %s: %w", currency, err)
+		}
+		if isNew {
+			created++
+		}
+		batches = append(batches, batch)
+	}
+
+	return batches, created, nil
+}
+
+func (p *payoutUseCase) ListPayoutBatches(page, limit int) ([]domain.PayoutBatch, error) {
+	if limit > maxPayoutPageSize {
+		limit = maxPayoutPageSize
+	}
+
+	batches, err := p.repository.ListPayoutBatches(page, limit)
+	if err != nil {
+		return nil, err
+	}
+	if batches == nil {
+		batches = []domain.PayoutBatch{}
+	}
+
+	return batches, nil
+}
+
+func (p *payoutUseCase) GetPayoutBatch(batchID uint) (domain.PayoutBatch, error) {
+	return p.repository.GetPayoutBatch(batchID)
+}
+
+func (p *payoutUseCase) ApprovePayoutBatch(batchID uint) error {
+	batch, err := p.repository.GetPayoutBatch(batchID)
+	if err != nil {
+		return err
+	}
+	if batch.Status != domain.PayoutPending {
+		return errors.New("payout batch is already " + batch.Status)
+	}
+
+	approved, err := p.repository.ApprovePayoutBatch(batchID)
+	if err != nil {
+		return err
+	}
+	if !approved {
+		return errors.New("payout batch was approved meanwhile")
+	}
+
+	return nil
+}
+
+// MarkPayoutPaid records the transfer made for an approved payout, the ledger then no longer owes the creator the amount.
+func (p *payoutUseCase) MarkPayoutPaid(payoutID uint, reference string) error {
+	reference = strings.TrimSpace(reference)
+	if reference == "" {
+		return errors.New("transfer reference is required")
+	}
+
+	payout, err := p.repository.GetPayout(payoutID)
+	if err != nil {
+		return err
+	}
+	if payout.Status != domain.PayoutApproved {
+		return errors.New("only payouts of an approved batch can be marked paid, this one is " + payout.Status)
+	}
+
+	transaction := domain.LedgerTransaction{
+		Type:        domain.LedgerPayout,
+		Reference:   fmt.Sprintf("payout_%d", payout.ID),
+		CreatorID:   payout.CreatorID,
+		Currency:    payout.Currency,
+		Description: fmt.Sprintf("payout %d of batch %d, transfer %s", payout.ID, payout.BatchID, reference),
+		Entries: []domain.LedgerEntry{
+			{Account: domain.AccountCreatorPayable, CreatorID: payout.CreatorID, Debit: payout.Amount},
+			{Account: domain.AccountGatewayClearing, Credit: payout.Amount},
+		},
+	}
+	if _, err := p.ledger.PostTransaction(&transaction); err != nil {
+		return err
+	}
+
+	paid, err := p.repository.MarkPayoutPaid(payoutID, reference)
+	if err != nil {
+		return err
+	}
+	if !paid {
+		return errors.New("payout was marked paid meanwhile")
+	}
+
+	notification := domain.Notification{
+		UserID:  payout.CreatorID,
+		Type:    "payout_paid",
+		Title:   "Your payout was sent",
+		Message: fmt.Sprintf("%s was sent to your payout account, transfer reference %s.", money.Money{Amount: payout.Amount, Currency: payout.Currency}, reference),
+	}
+	if err := p.notifications.CreateNotification(&notification); err != nil {
+		log.Println("Error creating notification:", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,446 @@
+package usecase
+
+import (
+	"errors"
+	"log"
+	"main/pkg/domain"
+	"main/pkg/helper"
+	interfaces "main/pkg/repository/interface"
+	services "main/pkg/usecase/interface"
+	"main/pkg/utils/models"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agnivade/levenshtein"
+)
+
+type searchUseCase struct {
+	repository interfaces.SearchRepository
+
+	// suggestions are requested on every keystroke, so recent answers are kept in memory
+	cacheMu         sync.Mutex
+	suggestCache    map[string]suggestCacheEntry
+	vocabulary      []string
+	vocabularyUntil time.Time
+}
+
+type suggestCacheEntry struct {
+	results models.SuggestResults
+	expires time.Time
+}
+
+func NewSearchUseCase(repo interfaces.SearchRepository) services.SearchUseCase {
+	return &searchUseCase{
+		repository:   repo,
+		suggestCache: make(map[string]suggestCacheEntry),
+	}
+}
+
+const maxSearchLimit = 50
+
+// SearchAll searches videos, creators, tags and categories at once and returns the results grouped by type.
+func (s *searchUseCase) SearchAll(userID int, filter models.SearchFilter) (models.SearchResults, error) {
+	filter.Query = strings.TrimSpace(filter.Query)
+	if filter.Query == "" && filter.CategoryID == 0 && filter.CreatorID == 0 {
+		return models.SearchResults{}, errors.New("search term or a category or creator filter is required")
+	}
+
+	// Validate page and limit
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 {
+		filter.Limit = 10
+	}
+	if filter.Limit > maxSearchLimit {
+		filter.Limit = maxSearchLimit
+	}
+
+	if filter.Type == "" {
+		filter.Type = "all"
+	}
+	switch filter.Type {
+	case "all", "videos", "creators", "tags", "categories":
+	default:
+		return models.SearchResults{}, errors.New("type must be one of all, videos, creators, tags, categories")
+	}
+
+	results := models.SearchResults{Query: filter.Query, Groups: []models.SearchGroup{}}
+
+	if filter.Type == "all" || filter.Type == "videos" {
+		videos, count, err := s.repository.SearchVideos(filter)
+		if err != nil {
+			return models.SearchResults{}, err
+		}
+		if videos == nil {
+			videos = []models.VideoSearchResult{}
+		}
+		results.Groups = append(results.Groups, models.SearchGroup{Type: "videos", Count: count, Items: videos})
+		results.Total += count
+
+		facets, err := s.repository.VideoFacets(filter)
+		if err != nil {
+			return models.SearchResults{}, err
+		}
+		results.Facets = facets
+	}
+
+	// Creators, tags and categories are only matched by the search term
+	if filter.Query == "" {
+		return results, nil
+	}
+
+	if filter.Type == "all" || filter.Type == "creators" {
+		creators, count, err := s.repository.SearchCreators(filter.Query, filter.Page, filter.Limit)
+		if err != nil {
+			return models.SearchResults{}, err
+		}
+		if creators == nil {
+			creators = []models.CreatorSearchResult{}
+		}
+		results.Groups = append(results.Groups, models.SearchGroup{Type: "creators", Count: count, Items: creators})
+		results.Total += count
+	}
+
+	if filter.Type == "all" || filter.Type == "tags" {
+		tags, count, err := s.repository.SearchTags(filter.Query, filter.Page, filter.Limit)
+		if err != nil {
+			return models.SearchResults{}, err
+		}
+		if tags == nil {
+			tags = []models.TagSearchResult{}
+		}
+		results.Groups = append(results.Groups, models.SearchGroup{Type: "tags", Count: count, Items: tags})
+		results.Total += count
+	}
+
+	if filter.Type == "all" || filter.Type == "categories" {
+		categories, count, err := s.repository.SearchCategories(filter.Query, filter.Page, filter.Limit)
+		if err != nil {
+			return models.SearchResults{}, err
+		}
+		if categories == nil {
+			categories = []models.CategorySearchResult{}
+		}
+		results.Groups = append(results.Groups, models.SearchGroup{Type: "categories", Count: count, Items: categories})
+		results.Total += count
+	}
+
+	results.SearchID = logSearch(s.repository, userID, filter.Query, "search", results.Total)
+	return results, nil
+}
+
+const (
+	suggestCacheTTL      = time.Minute
+	suggestCacheSize     = 5000
+	vocabularyTTL        = 10 * time.Minute
+	vocabularySize       = 20000
+	maxSuggestLimit      = 10
+	minSuggestQueryRunes = 2
+)
+
+// Suggest returns prefix completions ranked by popularity and "did you mean" corrections for the query.
+func (s *searchUseCase) Suggest(query string, limit int) (models.SuggestResults, error) {
+	query = strings.TrimSpace(query)
+	if len([]rune(query)) < minSuggestQueryRunes {
+		return models.SuggestResults{}, errors.New("query must be at least 2 characters")
+	}
+
+	if limit < 1 || limit > maxSuggestLimit {
+		limit = 5
+	}
+
+	key := strings.ToLower(query) + "|" + strconv.Itoa(limit)
+	if cached, ok := s.cachedSuggestions(key); ok {
+		return cached, nil
+	}
+
+	titles, err := s.repository.SuggestTitles(query, limit)
+	if err != nil {
+		return models.SuggestResults{}, err
+	}
+	creators, err := s.repository.SuggestCreators(query, limit)
+	if err != nil {
+		return models.SuggestResults{}, err
+	}
+	tags, err := s.repository.SuggestTags(query, limit)
+	if err != nil {
+		return models.SuggestResults{}, err
+	}
+
+	// Popularity is counted differently for each type, so the sources are ranked on their own and interleaved
+	completions := interleaveSuggestions(limit, titles, creators, tags)
+
+	results := models.SuggestResults{
+		Query:       query,
+		Completions: completions,
+		DidYouMean:  s.corrections(query, limit),
+	}
+
+	s.storeSuggestions(key, results)
+	return results, nil
+}
+
+// interleaveSuggestions merges completions of different types by their rank within their own type,
+// so one type with large popularity numbers can't push the others out of the limit. Completions of
+// the same rank are ordered by their popularity relative to the most popular of their type
+func interleaveSuggestions(limit int, groups ...[]models.Suggestion) []models.Suggestion {
+	longest := 0
+	maxPopularity := make([]int64, len(groups))
+	for i, group := range groups {
+		sort.SliceStable(group, func(a, b int) bool {
+			return group[a].Popularity > group[b].Popularity
+		})
+		if len(group) > longest {
+			longest = len(group)
+		}
+		if len(group) > 0 {
+			maxPopularity[i] = group[0].Popularity
+		}
+	}
+
+	relative := func(group int, popularity int64) float64 {
+		if maxPopularity[group] <= 0 {
+			return 0
+		}
+		return float64(popularity) / float64(maxPopularity[group])
+	}
+
+	completions := []models.Suggestion{}
+	for rank := 0; rank < longest && len(completions) < limit; rank++ {
+		type ranked struct {
+			suggestion models.Suggestion
+			score      float64
+		}
+		var round []ranked
+		for i, group := range groups {
+			if rank < len(group) {
+				round = append(round, ranked{group[rank], relative(i, group[rank].Popularity)})
+			}
+		}
+		sort.SliceStable(round, func(a, b int) bool {
+			return round[a].score > round[b].score
+		})
+		for _, r := range round {
+			if len(completions) == limit {
+				break
+			}
+			completions = append(completions, r.suggestion)
+		}
+	}
+	return completions
+}
+
+// corrections finds close spellings of the query with pg_trgm and falls back to Levenshtein distance
+func (s *searchUseCase) corrections(query string, limit int) []string {
+	corrections := []string{}
+
+	similar, err := s.repository.SimilarTerms(query, limit)
+	if err == nil {
+		for _, term := range similar {
+			if !strings.EqualFold(term, query) {
+				corrections = append(corrections, term)
+			}
+		}
+	}
+	if len(corrections) > 0 {
+		return corrections
+	}
+
+	// Assuming a threshold of 2 for longer words and 1 for short ones, adjust as needed
+	threshold := 1
+	if len([]rune(query)) > 4 {
+		threshold = 2
+	}
+
+	type candidate struct {
+		term     string
+		distance int
+	}
+	var candidates []candidate
+	lowerQuery := strings.ToLower(query)
+	for _, term := range s.searchVocabulary() {
+		lowerTerm := strings.ToLower(term)
+		if lowerTerm == lowerQuery {
+			continue
+		}
+		if distance := levenshtein.ComputeDistance(lowerQuery, lowerTerm); distance <= threshold {
+			candidates = append(candidates, candidate{term, distance})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].distance < candidates[j].distance
+	})
+	for i := 0; i < len(candidates) && i < limit; i++ {
+		corrections = append(corrections, candidates[i].term)
+	}
+
+	return corrections
+}
+
+func (s *searchUseCase) searchVocabulary() []string {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+
+	if time.Now().Before(s.vocabularyUntil) {
+		return s.vocabulary
+	}
+
+	vocabulary, err := s.repository.GetSearchVocabulary(vocabularySize)
+	if err != nil {
+		// Keep serving the previous vocabulary if the refresh fails
+		return s.vocabulary
+	}
+
+	s.vocabulary = vocabulary
+	s.vocabularyUntil = time.Now().Add(vocabularyTTL)
+	return s.vocabulary
+}
+
+func (s *searchUseCase) cachedSuggestions(key string) (models.SuggestResults, bool) {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+
+	entry, ok := s.suggestCache[key]
+	if !ok || time.Now().After(entry.expires) {
+		return models.SuggestResults{}, false
+	}
+
+	return entry.results, true
+}
+
+func (s *searchUseCase) storeSuggestions(key string, results models.SuggestResults) {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+
+	now := time.Now()
+	if len(s.suggestCache) >= suggestCacheSize {
+		// Drop expired entries first and start over if the cache is still full
+		for k, entry := range s.suggestCache {
+			if now.After(entry.expires) {
+				delete(s.suggestCache, k)
+			}
+		}
+		if len(s.suggestCache) >= suggestCacheSize {
+			s.suggestCache = make(map[string]suggestCacheEntry)
+		}
+	}
+
+	s.suggestCache[key] = suggestCacheEntry{results: results, expires: now.Add(suggestCacheTTL)}
+}
+
+// normalizeQuery lowercases the query and collapses the whitespace, so analytics group the same search together
+func normalizeQuery(query string) string {
+	return strings.Join(strings.Fields(strings.ToLower(query)), " ")
+}
+
+// logSearch stores the search for analytics and returns its ID, 0 when it could not be stored.
+// A userID of 0 is a search made without logging in. A failure here must not fail the search itself
+func logSearch(repository interfaces.SearchRepository, userID int, query string, source string, resultCount int64) uint {
+	entry := domain.SearchQuery{
+		Query:           query,
+		NormalizedQuery: normalizeQuery(query),
+		Source:          source,
+		ResultCount:     resultCount,
+	}
+	if userID != 0 {
+		entry.UserHash = helper.AnonymizeUserID(userID)
+	}
+
+	if err := repository.LogSearchQuery(&entry); err != nil {
+		log.Println("Error logging search query:", err)
+		return 0
+	}
+
+	return entry.ID
+}
+
+// RecordClick stores which result the user opened from a search.
+func (s *searchUseCase) RecordClick(userID int, searchID uint, clickedType string, clickedID int) error {
+	switch clickedType {
+	case "video", "creator", "tag", "category":
+	default:
+		return errors.New("type must be one of video, creator, tag, category")
+	}
+
+	if clickedID <= 0 {
+		return errors.New("invalid result ID")
+	}
+
+	return s.repository.RecordSearchClick(searchID, helper.AnonymizeUserID(userID), clickedType, clickedID)
+}
+
+// parseDateRange parses the optional YYYY-MM-DD range, the last 30 days are used by default
+func parseDateRange(startDate, endDate string) (time.Time, time.Time, error) {
+	to := time.Now()
+	from := to.AddDate(0, 0, -30)
+
+	if startDate != "" {
+		parsed, err := time.Parse("2006-01-02", startDate)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("start date must be in YYYY-MM-DD format")
+		}
+		from = parsed
+	}
+
+	if endDate != "" {
+		parsed, err := time.Parse("2006-01-02", endDate)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("end date must be in YYYY-MM-DD format")
+		}
+		// Include the whole end day
+		to = parsed.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	if from.After(to) {
+		return time.Time{}, time.Time{}, errors.New("start date must be before end date")
+	}
+
+	return from, to, nil
+}
+
+// TopQueries returns the most searched queries in the date range.
+func (s *searchUseCase) TopQueries(startDate, endDate string, limit int) ([]models.SearchQueryStat, error) {
+	from, to, err := parseDateRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit < 1 || limit > maxSearchLimit {
+		limit = 20
+	}
+
+	return s.repository.GetTopSearchQueries(from, to, limit)
+}
+
+// ZeroResultQueries returns the most searched queries that found nothing in the date range.
+func (s *searchUseCase) ZeroResultQueries(startDate, endDate string, limit int) ([]models.SearchQueryStat, error) {
+	from, to, err := parseDateRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit < 1 || limit > maxSearchLimit {
+		limit = 20
+	}
+
+	return s.repository.GetZeroResultQueries(from, to, limit)
+}
+
+// SearchTrends returns the search volume over time, optionally for a single query.
+func (s *searchUseCase) SearchTrends(interval, startDate, endDate, query string) ([]models.SearchTrendPoint, error) {
+	from, to, err := parseDateRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	if interval == "" {
+		interval = "day"
+	}
+
+	return s.repository.GetSearchTrends(interval, from, to, normalizeQuery(query))
+}
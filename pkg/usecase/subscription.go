@@ -1,68 +1,485 @@
 package usecase
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"log"
+	"main/pkg/domain"
+	"main/pkg/money"
+	"main/pkg/payments"
 	interfaces "main/pkg/repository/interface"
 	services "main/pkg/usecase/interface"
 	"main/pkg/utils/models"
 	"strconv"
-
-	"github.com/razorpay/razorpay-go"
+	"strings"
+	"time"
 )
 
 type subscriptionUseCase struct {
-	repository interfaces.SubscriptionRepository
+	repository    interfaces.SubscriptionRepository
+	notifications interfaces.NotificationRepository
+	coupons       interfaces.CouponRepository
+	ledger        interfaces.LedgerRepository
+	settings      interfaces.SettingRepository
+	invoices      interfaces.InvoiceRepository
+	taxes         interfaces.TaxRepository
+	gifts         interfaces.GiftRepository
+	tips          interfaces.TipRepository
+	gateway       payments.Gateway
 }
 
-func NewSubscriptionUseCase(repo interfaces.SubscriptionRepository) services.SubscriptionUseCase {
+func NewSubscriptionUseCase(repo interfaces.SubscriptionRepository, notificationRepo interfaces.NotificationRepository, couponRepo interfaces.CouponRepository, ledgerRepo interfaces.LedgerRepository, settingRepo interfaces.SettingRepository, invoiceRepo interfaces.InvoiceRepository, taxRepo interfaces.TaxRepository, giftRepo interfaces.GiftRepository, tipRepo interfaces.TipRepository, gateway payments.Gateway) services.SubscriptionUseCase {
 	return &subscriptionUseCase{
-		repository: repo,
+		repository:    repo,
+		notifications: notificationRepo,
+		coupons:       couponRepo,
+		ledger:        ledgerRepo,
+		settings:      settingRepo,
+		invoices:      invoiceRepo,
+		taxes:         taxRepo,
+		gifts:         giftRepo,
+		tips:          tipRepo,
+		gateway:       gateway,
 	}
 }
 
-// // PurchasePlan handles the purchase of a subscription plan
-// func (i *subscriptionUseCase) PurchasePlan(planID int,creatorID) (string, error) {
-//     // Get the subscription list ID directly from the repository
-//     subscriptionListID, err := i.subscriptionRepository.GetSubscriptionListIDByPlanID(planID,creatorID)
-//     if err != nil {
-//         // Handle the error, plan not found
-//         return "", err
-//     }
+const (
+	// renewalReminderWindow is how long before the period ends the subscriber is reminded to renew
+	renewalReminderWindow = 3 * 24 * time.Hour
+	// gracePeriod is how long a past due subscription keeps access before it expires
+	gracePeriod = 3 * 24 * time.Hour
+	// pendingCheckoutTTL is how long an unpaid checkout stays open
+	pendingCheckoutTTL = 24 * time.Hour
+)
 
-//     link := fmt.Sprintf("http://localhost:1245/users/payment/razorpay?subscription_list_id=%d", subscriptionListID)
+// PurchasePlan opens a checkout for the plan. A user has one subscription and at most one open checkout per creator:
+// choosing a plan again reuses the open checkout, and buying while subscribed renews or changes the current subscription.
+// A user's first subscription to a creator starts the plan's free trial instead, the code is not used then.
+func (i *subscriptionUseCase) PurchasePlan(planID int, creatorID int, userID int, code string) (models.PurchaseResult, error) {
+	if creatorID == userID {
+		return models.PurchaseResult{}, errors.New("cannot subscribe to yourself")
+	}
+
+	plan, err := i.repository.GetPlan(planID)
+	if err != nil {
+		return models.PurchaseResult{}, err
+	}
+	if plan.Archived {
+		return models.PurchaseResult{}, errors.New("plan is no longer offered")
+	}
+	// Global plans from before creator tiers can still be bought for any creator
+	if plan.CreatorID != 0 && plan.CreatorID != creatorID {
+		return models.PurchaseResult{}, errors.New("plan is not offered by this creator")
+	}
 
-//	    return link, nil
-//	}
-//
-// PurchasePlan handles the purchase of a subscription plan
-func (i *subscriptionUseCase) PurchasePlan(planID int, creatorID int, userID int) (string, error) {
-	// Check if the user already has an active subscription
 	existingSubscription, err := i.repository.GetActiveSubscription(creatorID, userID)
 	if err != nil {
-		// Handle the error, e.g., plan not found or database error
-		return "", err
+		return models.PurchaseResult{}, err
+	}
+
+	kind, err := subscriptionKind(i.repository, existingSubscription, plan)
+	if err != nil {
+		return models.PurchaseResult{}, err
 	}
 
-	// If the user is already subscribed, return a message
-	if existingSubscription != nil {
-		return "User already subscribed", nil
+	trial := false
+	if existingSubscription == nil && plan.TrialDays > 0 {
+		subscribedBefore, err := i.repository.HasSubscribedBefore(userID, creatorID)
+		if err != nil {
+			return models.PurchaseResult{}, err
+		}
+		trial = !subscribedBefore
 	}
 
-	// Proceed with the subscription purchase process
+	var coupon *domain.Coupon
+	if code != "" && !trial {
+		found, err := i.checkCoupon(code, userID, creatorID)
+		if err != nil {
+			return models.PurchaseResult{}, err
+		}
+		coupon = &found
+	}
 
-	// Get the subscription list ID directly from the repository
-	subscriptionListID, err := i.repository.GetSubscriptionListIDByPlanID(planID, creatorID, userID)
+	pending, err := i.repository.GetPendingSubscription(creatorID, userID)
 	if err != nil {
-		// Handle the error, e.g., plan not found or database error
-		return "", err
+		return models.PurchaseResult{}, err
+	}
+
+	var subscriptionListID int
+	if pending != nil {
+		subscriptionListID = pending.ID
+		if pending.PlanID != planID || pending.Kind != kind {
+			if err := i.repository.UpdatePendingSubscription(pending.ID, planID, kind); err != nil {
+				return models.PurchaseResult{}, err
+			}
+		}
+	} else {
+		subscriptionListID, err = i.repository.GetSubscriptionListIDByPlanID(planID, creatorID, userID, kind)
+		if err != nil {
+			return models.PurchaseResult{}, err
+		}
+	}
+
+	quote, err := priceQuote(i.repository, i.taxes, i.gateway.Currency(), plan, userID, coupon)
+	if err != nil {
+		return models.PurchaseResult{}, err
+	}
+
+	currency := quote.Price.Currency
+	result := models.PurchaseResult{
+		SubscriptionID: subscriptionListID,
+		Kind:           kind,
+		Price:          quote.Price.Major(),
+		Currency:       currency,
+		Discount:       majorAmount(quote.Discount, currency),
+		Tax:            majorAmount(quote.Tax.Amount, currency),
+		TaxLabel:       taxLabel(quote.Tax),
+		FinalPrice:     majorAmount(quote.Total, currency),
+	}
+
+	if trial {
+		now := time.Now()
+		trialEnd := now.AddDate(0, 0, plan.TrialDays)
+		if err := i.repository.StartTrial(subscriptionListID, now, trialEnd); err != nil {
+			return models.PurchaseResult{}, err
+		}
+		i.notify(userID, "subscription_trial", "Your free trial has started",
+			fmt.Sprintf("Your trial ends on %s, subscribe before then to keep access.", trialEnd.Format("2 Jan 2006")))
+
+		result.Kind = domain.SubscriptionKindTrial
+		result.Tax, result.TaxLabel, result.FinalPrice = 0, "", 0
+		result.TrialEndsAt = &trialEnd
+		return result, nil
+	}
+
+	var couponID *uint
+	if coupon != nil {
+		couponID = &coupon.ID
+		result.Coupon = coupon.Code
+	}
+	if err := i.repository.SetSubscriptionPricing(subscriptionListID, couponID, quote.Discount, quote.Tax); err != nil {
+		return models.PurchaseResult{}, err
 	}
 
 	//link := fmt.Sprintf("http://localhost:1245/users/plans/choose-plan/razorpay?subscription_list_id=%d", subscriptionListID)
-	link := fmt.Sprintf("https://gameverse.cloud/users/plans/choose-plan/razorpay?subscription_list_id=%d", subscriptionListID)
-	return link, nil
+	result.Link = fmt.Sprintf("https://gameverse.cloud/users/plans/choose-plan/razorpay?subscription_list_id=%d", subscriptionListID)
+	return result, nil
 }
 
-func (p *subscriptionUseCase) MakePaymentRazorPay(planID string, userID int) (models.OrderPaymentDetails, error) {
+// checkCoupon finds the coupon by its code and makes sure the user can apply it to a subscription to the creator
+func (i *subscriptionUseCase) checkCoupon(code string, userID, creatorID int) (domain.Coupon, error) {
+	coupon, err := i.coupons.GetCouponByCode(code)
+	if err != nil {
+		return domain.Coupon{}, err
+	}
+
+	if err := i.validCoupon(coupon, userID, creatorID); err != nil {
+		return domain.Coupon{}, err
+	}
+
+	return coupon, nil
+}
+
+func (i *subscriptionUseCase) validCoupon(coupon domain.Coupon, userID, creatorID int) error {
+	if !coupon.Active {
+		return errors.New("coupon is no longer active")
+	}
+	if coupon.ExpiresAt != nil && !coupon.ExpiresAt.After(time.Now()) {
+		return errors.New("coupon has expired")
+	}
+	if coupon.MaxRedemptions > 0 && coupon.RedemptionCount >= coupon.MaxRedemptions {
+		return errors.New("coupon has reached its usage limit")
+	}
+	if coupon.CreatorID != 0 && coupon.CreatorID != creatorID {
+		return errors.New("coupon is not valid for this creator")
+	}
+
+	redeemed, err := i.coupons.HasRedeemed(coupon.ID, userID)
+	if err != nil {
+		return err
+	}
+	if redeemed {
+		return errors.New("coupon has already been used")
+	}
+
+	// First-time coupons of a creator are for new subscribers of that creator, platform ones for users who never subscribed
+	if coupon.FirstTimeOnly {
+		subscribedBefore, err := i.repository.HasSubscribedBefore(userID, coupon.CreatorID)
+		if err != nil {
+			return err
+		}
+		if subscribedBefore {
+			return errors.New("coupon is only for first-time subscribers")
+		}
+	}
+
+	return nil
+}
+
+// couponDiscount is the amount the coupon takes off the price in minor units, never more than the price
+func couponDiscount(coupon domain.Coupon, price money.Money) int64 {
+	discount := money.FromMajor(coupon.DiscountValue, price.Currency).Amount
+	if coupon.DiscountType == domain.CouponPercent {
+		discount = price.Percent(coupon.DiscountValue)
+	}
+
+	if discount > price.Amount {
+		discount = price.Amount
+	}
+
+	return discount
+}
+
+// priceQuote prices the plan for the user: the price of the user's billing country or the plan's own one,
+// less the coupon, with the tax of the country or the default tax.
+func priceQuote(repository interfaces.SubscriptionRepository, taxes interfaces.TaxRepository, platformCurrency string, plan domain.SubscriptionPlan, userID int, coupon *domain.Coupon) (models.PriceQuote, error) {
+	country, err := repository.FindBillingCountry(userID)
+	if err != nil {
+		return models.PriceQuote{}, err
+	}
+
+	quote := models.PriceQuote{Region: country, Price: plan.Price}
+	if country != "" {
+		price, found, err := repository.GetRegionalPrice(plan.ID, country)
+		if err != nil {
+			return models.PriceQuote{}, err
+		}
+		if found {
+			quote.Price = price
+		}
+	}
+
+	net := quote.Price.Amount
+	if coupon != nil {
+		// a fixed discount is an amount in the platform currency, there is no exchange rate to convert it
+		if coupon.DiscountType == domain.CouponFixed && quote.Price.Currency != platformCurrency {
+			return models.PriceQuote{}, fmt.Errorf("coupon %s only applies to prices in %s", coupon.Code, platformCurrency)
+		}
+		quote.Discount = couponDiscount(*coupon, quote.Price)
+		net -= quote.Discount
+	}
+
+	rule, found, err := taxes.GetTaxRule(country)
+	if err != nil {
+		return models.PriceQuote{}, err
+	}
+	if found {
+		quote.Tax = taxQuote(&rule, net)
+	}
+
+	quote.Total = net
+	if !quote.Tax.Inclusive {
+		quote.Total += quote.Tax.Amount
+	}
+
+	return quote, nil
+}
+
+// majorAmount converts an amount in minor units to the major unit of the currency
+func majorAmount(amount int64, currency string) float64 {
+	return money.Money{Amount: amount, Currency: currency}.Major()
+}
+
+// subscriptionKind tells how buying the plan relates to the user's current subscription, plans are ranked by price
+func subscriptionKind(repository interfaces.SubscriptionRepository, current *domain.SubscriptionList, plan domain.SubscriptionPlan) (string, error) {
+	if current == nil {
+		return domain.SubscriptionKindNew, nil
+	}
+	if current.PlanID == plan.ID {
+		return domain.SubscriptionKindRenewal, nil
+	}
+
+	currentPlan, err := repository.GetPlan(current.PlanID)
+	if err != nil {
+		return "", err
+	}
+	if plan.Price.Amount < currentPlan.Price.Amount {
+		return domain.SubscriptionKindDowngrade, nil
+	}
+
+	return domain.SubscriptionKindUpgrade, nil
+}
+
+// subscriptionActivation works out the period of a paid subscription from the user's current one at the time of payment.
+// A renewal extends the current period by the plan's duration. On a plan change the unused days of the current plan
+// are valued at its daily rate and added to the new period at the new plan's daily rate.
+// Gift codes start their subscription the same way.
+func subscriptionActivation(repository interfaces.SubscriptionRepository, subscription domain.SubscriptionList, now time.Time) (models.SubscriptionActivation, error) {
+	plan, err := repository.GetPlan(subscription.PlanID)
+	if err != nil {
+		return models.SubscriptionActivation{}, err
+	}
+
+	activation := models.SubscriptionActivation{
+		Kind:        domain.SubscriptionKindNew,
+		PeriodStart: now,
+		PeriodEnd:   now.AddDate(0, 0, plan.Duration),
+	}
+
+	current, err := repository.GetActiveSubscription(subscription.CreatorID, subscription.UserID)
+	if err != nil {
+		return models.SubscriptionActivation{}, err
+	}
+	if current == nil || current.ID == subscription.ID {
+		return activation, nil
+	}
+
+	activation.PreviousSubscriptionID = &current.ID
+	activation.Kind, err = subscriptionKind(repository, current, plan)
+	if err != nil {
+		return models.SubscriptionActivation{}, err
+	}
+
+	// A past due subscription has no time left, it is renewed or changed from now
+	if current.CurrentPeriodEnd == nil || !current.CurrentPeriodEnd.After(now) {
+		return activation, nil
+	}
+
+	// The unused days of a trial were not paid for, so they carry over on a renewal only
+	if current.PaymentStatus == "TRIAL" && activation.Kind != domain.SubscriptionKindRenewal {
+		return activation, nil
+	}
+
+	if activation.Kind == domain.SubscriptionKindRenewal {
+		if current.CurrentPeriodStart != nil {
+			activation.PeriodStart = *current.CurrentPeriodStart
+		}
+		activation.PeriodEnd = current.CurrentPeriodEnd.AddDate(0, 0, plan.Duration)
+		return activation, nil
+	}
+
+	currentPlan, err := repository.GetPlan(current.PlanID)
+	if err != nil {
+		return models.SubscriptionActivation{}, err
+	}
+	if currentPlan.Duration > 0 && plan.Price.Amount > 0 && plan.Duration > 0 {
+		remaining := current.CurrentPeriodEnd.Sub(now)
+		credit := float64(currentPlan.Price.Amount) / float64(currentPlan.Duration) * remaining.Hours() / 24
+		extraDays := credit / (float64(plan.Price.Amount) / float64(plan.Duration))
+		activation.PeriodEnd = activation.PeriodEnd.Add(time.Duration(extraDays * 24 * float64(time.Hour)))
+	}
+
+	return activation, nil
+}
+
+// activateSubscription starts a paid subscription, paying an order twice does not extend it twice
+func (i *subscriptionUseCase) activateSubscription(subscription domain.SubscriptionList, paymentID string) error {
+	activation, err := subscriptionActivation(i.repository, subscription, time.Now())
+	if err != nil {
+		return err
+	}
+
+	activated, err := i.repository.ActivateSubscription(subscription.ID, paymentID, activation)
+	if err != nil {
+		return err
+	}
+
+	// Recorded even when the order was already activated, a retried payment still gets its charge and invoice if recording them failed before
+	if err := i.recordPayment(subscription, paymentID); err != nil {
+		return err
+	}
+
+	if !activated {
+		return nil
+	}
+
+	if activation.Kind != domain.SubscriptionKindNew {
+		i.notify(subscription.UserID, "subscription_"+activation.Kind, "Your subscription was updated",
+			fmt.Sprintf("Your subscription now runs until %s.", activation.PeriodEnd.Format("2 Jan 2006")))
+	}
+
+	return nil
+}
+
+// recordPayment posts the payment of a gateway order to the ledger and issues its invoice, once per payment.
+func (i *subscriptionUseCase) recordPayment(subscription domain.SubscriptionList, paymentID string) error {
+	if subscription.Amount <= 0 {
+		return nil
+	}
+
+	feePercent, err := platformFeePercent(i.settings)
+	if err != nil {
+		return err
+	}
+
+	transaction := chargeTransaction(subscription, paymentID, feePercent)
+	if _, err := i.ledger.PostTransaction(&transaction); err != nil {
+		return err
+	}
+
+	return i.issueInvoice(subscription.ID, paymentID)
+}
+
+// issueInvoice records who paid what for which period, read back after activation so the invoice has the paid period.
+func (i *subscriptionUseCase) issueInvoice(subscriptionID int, paymentID string) error {
+	subscription, err := i.repository.GetSubscriptionByID(subscriptionID)
+	if err != nil {
+		return err
+	}
+
+	plan, err := i.repository.GetPlan(subscription.PlanID)
+	if err != nil {
+		return err
+	}
+
+	parties, err := i.invoices.GetInvoiceParties(subscription.UserID, subscription.CreatorID)
+	if err != nil {
+		return err
+	}
+
+	var couponCode string
+	if subscription.CouponID != nil {
+		coupon, err := i.coupons.GetCoupon(*subscription.CouponID)
+		if err != nil {
+			return err
+		}
+		couponCode = coupon.Code
+	}
+
+	// an exclusive tax was added on top of the discounted price
+	subtotal := subscription.Amount + subscription.Discount
+	if !subscription.TaxInclusive {
+		subtotal -= subscription.Tax
+	}
+
+	invoice := domain.Invoice{
+		SubscriptionListID: subscription.ID,
+		PaymentID:          paymentID,
+		UserID:             subscription.UserID,
+		CreatorID:          subscription.CreatorID,
+		BuyerName:          parties.BuyerName,
+		BuyerEmail:         parties.BuyerEmail,
+		CreatorName:        parties.CreatorName,
+		PlanName:           plan.Name,
+		PeriodStart:        subscription.CurrentPeriodStart,
+		PeriodEnd:          subscription.CurrentPeriodEnd,
+		Subtotal:           subtotal,
+		Discount:           subscription.Discount,
+		CouponCode:         couponCode,
+		TaxLabel:           subscription.TaxName,
+		TaxRate:            subscription.TaxRate,
+		TaxInclusive:       subscription.TaxInclusive,
+		Tax:                subscription.Tax,
+		Total:              subscription.Amount,
+		Currency:           subscription.Currency,
+		IssuedAt:           time.Now(),
+	}
+
+	_, err = i.invoices.IssueInvoice(&invoice)
+	return err
+}
+
+// recordRefund posts a refund the gateway processed against the charge of its payment, once per refund.
+func (i *subscriptionUseCase) recordRefund(paymentID, refundID string, amount int64) error {
+	_, err := postRefund(i.ledger, paymentID, refundID, amount)
+	return err
+}
+
+// MakePaymentRazorPay prepares the gateway order for a checkout, a code given here replaces the one chosen with the plan.
+// The coupon is checked again since it may have expired or run out meanwhile.
+func (p *subscriptionUseCase) MakePaymentRazorPay(planID string, userID int, code string) (models.OrderPaymentDetails, error) {
 	var orderDetails models.OrderPaymentDetails
 
 	newid, err := strconv.Atoi(planID)
@@ -71,6 +488,20 @@ func (p *subscriptionUseCase) MakePaymentRazorPay(planID string, userID int) (mo
 	}
 	orderDetails.OrderID = newid
 
+	subscription, err := p.repository.GetSubscriptionByID(newid)
+	if err != nil {
+		return models.OrderPaymentDetails{}, err
+	}
+	if subscription.UserID != userID {
+		return models.OrderPaymentDetails{}, errors.New("subscription does not belong to the user")
+	}
+	if subscription.PaymentStatus == "PAID" {
+		return models.OrderPaymentDetails{}, errors.New("subscription is already paid")
+	}
+	if subscription.Status != domain.SubscriptionPending {
+		return models.OrderPaymentDetails{}, errors.New("checkout has expired, choose the plan again")
+	}
+
 	orderDetails.UserID = userID
 
 	//get username
@@ -81,42 +512,725 @@ func (p *subscriptionUseCase) MakePaymentRazorPay(planID string, userID int) (mo
 
 	orderDetails.Username = username
 
-	//get total
-	newfinal, err := p.repository.FindPrice(newid)
+	plan, err := p.repository.GetPlan(subscription.PlanID)
 	if err != nil {
 		return models.OrderPaymentDetails{}, err
 	}
 
-	orderDetails.FinalPrice = newfinal
-
-	client := razorpay.NewClient("rzp_test_hfgxceEmSupD3T", "9UrqCcAM6F5JMXqTFmJBQBGQ")
+	if code != "" {
+		coupon, err := p.checkCoupon(code, userID, subscription.CreatorID)
+		if err != nil {
+			return models.OrderPaymentDetails{}, err
+		}
+		subscription.CouponID = &coupon.ID
+	}
 
-	data := map[string]interface{}{
-		"amount":   int(orderDetails.FinalPrice) * 100,
-		"currency": "INR",
-		"receipt":  "some_receipt_id",
+	var coupon *domain.Coupon
+	if subscription.CouponID != nil {
+		found, err := p.coupons.GetCoupon(*subscription.CouponID)
+		if err != nil {
+			return models.OrderPaymentDetails{}, err
+		}
+		if err := p.validCoupon(found, userID, subscription.CreatorID); err != nil {
+			return models.OrderPaymentDetails{}, fmt.Errorf("coupon %s cannot be applied: %w", found.Code, err)
+		}
+		coupon = &found
+		orderDetails.Coupon = found.Code
 	}
 
-	body, err := client.Order.Create(data, nil)
+	// The price is worked out again at every checkout, the billing country or the tax may have changed since the plan was chosen
+	quote, err := priceQuote(p.repository, p.taxes, p.gateway.Currency(), plan, userID, coupon)
 	if err != nil {
-		return models.OrderPaymentDetails{}, nil
+		return models.OrderPaymentDetails{}, err
+	}
+
+	currency := quote.Price.Currency
+	orderDetails.Discount = majorAmount(quote.Discount, currency)
+	orderDetails.Tax = majorAmount(quote.Tax.Amount, currency)
+	orderDetails.TaxLabel = taxLabel(quote.Tax)
+	orderDetails.FinalPrice = majorAmount(quote.Total, currency)
+	orderDetails.Currency = currency
+
+	if err := p.repository.SetSubscriptionPricing(newid, subscription.CouponID, quote.Discount, quote.Tax); err != nil {
+		return models.OrderPaymentDetails{}, err
+	}
+
+	// Nothing is left to pay, the subscription starts without going through the gateway
+	if quote.Total <= 0 {
+		// An order opened before the coupon was applied is dropped, so nothing is charged in the ledger
+		if err := p.repository.SetGatewayOrder(newid, "", 0, ""); err != nil {
+			return models.OrderPaymentDetails{}, err
+		}
+		subscription.RazorOrderID, subscription.Amount, subscription.Currency = "", 0, ""
+
+		if err := p.activateSubscription(subscription, "free_"+planID); err != nil {
+			return models.OrderPaymentDetails{}, err
+		}
+		orderDetails.FinalPrice = 0
+		orderDetails.Paid = true
+		return orderDetails, nil
 	}
 
-	razorPayOrderID := body["id"].(string)
+	// Reopening the checkout keeps the gateway order, so a payment made on an earlier page is still matched
+	order := payments.Order{ID: subscription.RazorOrderID, Amount: subscription.Amount, Currency: subscription.Currency}
+	if order.ID == "" || order.Amount != quote.Total || order.Currency != currency {
+		order, err = p.gateway.CreateOrder(quote.Total, currency, "subscription_"+planID)
+		if err != nil {
+			return models.OrderPaymentDetails{}, err
+		}
+
+		// The gateway order is kept so the checkout and webhooks can be matched back to this subscription
+		if err := p.repository.SetGatewayOrder(newid, order.ID, order.Amount, order.Currency); err != nil {
+			return models.OrderPaymentDetails{}, err
+		}
+	}
 
-	orderDetails.Razor_id = razorPayOrderID
+	orderDetails.Razor_id = order.ID
+	orderDetails.Amount = order.Amount
+	orderDetails.Currency = order.Currency
+	orderDetails.Gateway = p.gateway.Name()
+	orderDetails.KeyID = p.gateway.KeyID()
 
 	return orderDetails, nil
 }
-func (p *subscriptionUseCase) VerifyPayment(paymentID string, razorID string, orderID string) error {
 
-	err := p.repository.UpdatePaymentDetails(orderID, paymentID, razorID)
+// SimulatePayment completes a checkout on gateways that support it, so the purchase flow can be run without a real payment.
+func (p *subscriptionUseCase) SimulatePayment(razorID string) (models.SimulatedPayment, error) {
+	simulator, ok := p.gateway.(payments.Simulator)
+	if !ok {
+		return models.SimulatedPayment{}, errors.New("the payment gateway does not support simulated payments")
+	}
+
+	payment, signature, err := simulator.SimulatePayment(razorID)
+	if err != nil {
+		return models.SimulatedPayment{}, err
+	}
+
+	return models.SimulatedPayment{
+		PaymentID: payment.ID,
+		RazorID:   payment.OrderID,
+		Signature: signature,
+	}, nil
+}
+
+// VerifyPayment checks the checkout signature before marking the subscription paid, the client supplied IDs are not trusted on their own.
+func (p *subscriptionUseCase) VerifyPayment(userID int, paymentID string, razorID string, orderID string, signature string) error {
+	id, err := strconv.Atoi(orderID)
+	if err != nil {
+		return errors.New("invalid order id")
+	}
+
+	subscription, err := p.repository.GetSubscriptionByID(id)
+	if err != nil {
+		return err
+	}
+
+	// a valid signature only proves the payment, only the user who checked out can activate their subscription
+	if subscription.UserID != userID {
+		return errors.New("subscription does not belong to the user")
+	}
+
+	if subscription.RazorOrderID == "" || subscription.RazorOrderID != razorID {
+		return errors.New("payment does not belong to this order")
+	}
+
+	if !p.gateway.VerifySignature(razorID, paymentID, signature) {
+		return errors.New("invalid payment signature")
+	}
+
+	// The webhook may have confirmed the payment already
+	if subscription.PaymentStatus == "PAID" {
+		if subscription.PaymentID == paymentID {
+			return nil
+		}
+		return errors.New("order is already paid")
+	}
+
+	return p.activateSubscription(subscription, paymentID)
+
+}
+
+// HandleWebhook verifies and stores a payment webhook and applies it, an event that was already processed is skipped.
+func (p *subscriptionUseCase) HandleWebhook(body []byte, signature string, eventID string) error {
+	if !p.gateway.VerifyWebhookSignature(body, signature) {
+		return payments.ErrInvalidSignature
+	}
+
+	webhook, err := payments.ParseWebhook(body)
+	if err != nil {
+		return err
+	}
+
+	// Without an event ID header the body itself identifies the delivery
+	if eventID == "" {
+		sum := sha256.Sum256(body)
+		eventID = hex.EncodeToString(sum[:])
+	}
+
+	event := domain.PaymentEvent{
+		EventID:   eventID,
+		Event:     webhook.Event,
+		Payload:   string(body),
+		Signature: signature,
+		Status:    "received",
+	}
+
+	if _, err := p.repository.StorePaymentEvent(&event); err != nil {
+		return err
+	}
+
+	if event.Status == "processed" || event.Status == "ignored" {
+		return nil
+	}
+
+	return p.processPaymentEvent(event)
+}
+
+// processPaymentEvent applies a stored webhook and records the outcome on it
+func (p *subscriptionUseCase) processPaymentEvent(event domain.PaymentEvent) error {
+	status, err := p.applyPaymentEvent(event)
 	if err != nil {
+		if updateErr := p.repository.UpdatePaymentEventStatus(event.ID, "failed", err.Error()); updateErr != nil {
+			log.Println("Error updating payment event status:", updateErr)
+		}
 		return err
 	}
 
+	return p.repository.UpdatePaymentEventStatus(event.ID, status, "")
+}
+
+func (p *subscriptionUseCase) applyPaymentEvent(event domain.PaymentEvent) (string, error) {
+	webhook, err := payments.ParseWebhook([]byte(event.Payload))
+	if err != nil {
+		return "", err
+	}
+
+	payment := webhook.Payload.Payment.Entity
+	refund := webhook.Payload.Refund.Entity
+
+	switch webhook.Event {
+	case "payment.captured":
+		gift, found, err := p.gifts.FindGiftPurchaseByRazorOrderID(payment.OrderID)
+		if err != nil {
+			return "", err
+		}
+		if found {
+			return p.applyGiftPayment(gift, payment.ID, payment.Amount)
+		}
+
+		tip, found, err := p.tips.FindTipByRazorOrderID(payment.OrderID)
+		if err != nil {
+			return "", err
+		}
+		if found {
+			return p.applyTipPayment(tip, payment.ID, payment.Amount)
+		}
+
+		subscription, err := p.repository.GetSubscriptionByRazorOrderID(payment.OrderID)
+		if err != nil {
+			return "", err
+		}
+
+		if subscription.Amount > 0 && payment.Amount != subscription.Amount {
+			return "", errors.New("captured amount does not match the order amount")
+		}
+
+		if subscription.PaymentStatus == "PAID" || subscription.PaymentStatus == "REFUNDED" {
+			if subscription.PaymentID != payment.ID {
+				return "processed", nil
+			}
+			return "processed", p.recordPayment(subscription, payment.ID)
+		}
+
+		if err := p.activateSubscription(subscription, payment.ID); err != nil {
+			return "", err
+		}
+		return "processed", nil
+
+	case "payment.failed":
+		if err := p.repository.MarkPaymentFailed(payment.OrderID); err != nil {
+			return "", err
+		}
+		return "processed", nil
+
+	// Refunds made from the gateway's dashboard arrive here too, the subscriber keeps access until the period ends
+	case "refund.processed":
+		gift, found, err := p.gifts.FindGiftPurchaseByPaymentID(refund.PaymentID)
+		if err != nil {
+			return "", err
+		}
+		if found {
+			if err := p.giftFulfilment().refund(gift, refund.ID, refund.Amount); err != nil {
+				return "", err
+			}
+			return "processed", nil
+		}
+
+		tip, found, err := p.tips.FindTipByPaymentID(refund.PaymentID)
+		if err != nil {
+			return "", err
+		}
+		if found {
+			if err := p.tipSettlement().refund(tip, refund.ID, refund.Amount); err != nil {
+				return "", err
+			}
+			return "processed", nil
+		}
+
+		subscription, err := p.repository.GetSubscriptionByPaymentID(refund.PaymentID)
+		if err != nil {
+			return "", err
+		}
+
+		record := domain.PaymentRefund{
+			SubscriptionListID: subscription.ID,
+			PaymentID:          refund.PaymentID,
+			RefundID:           refund.ID,
+			Amount:             refund.Amount,
+			Source:             domain.RefundSourceGateway,
+		}
+		if err := p.applyRefund(subscription, &record); err != nil {
+			return "", err
+		}
+		return "processed", nil
+
+	case "payment.dispute.created", "payment.dispute.action_required", "payment.dispute.under_review",
+		"payment.dispute.won", "payment.dispute.lost", "payment.dispute.closed":
+		if err := p.applyDispute(webhook); err != nil {
+			return "", err
+		}
+		return "processed", nil
+	}
+
+	return "ignored", nil
+}
+
+// applyGiftPayment makes the codes of a gift purchase paid through the gateway, unless the checkout did already
+func (p *subscriptionUseCase) applyGiftPayment(gift domain.GiftPurchase, paymentID string, amount int64) (string, error) {
+	if amount != gift.Amount {
+		return "", errors.New("captured amount does not match the order amount")
+	}
+	if gift.Status != domain.GiftPending && gift.PaymentID != paymentID {
+		return "processed", nil
+	}
+
+	if _, err := p.giftFulfilment().fulfil(gift, paymentID); err != nil {
+		return "", err
+	}
+	return "processed", nil
+}
+
+func (p *subscriptionUseCase) giftFulfilment() giftFulfilment {
+	return giftFulfilment{
+		gifts:         p.gifts,
+		subscriptions: p.repository,
+		ledger:        p.ledger,
+		settings:      p.settings,
+		notifications: p.notifications,
+	}
+}
+
+// applyTipPayment records a tip paid through the gateway, unless the checkout did already
+func (p *subscriptionUseCase) applyTipPayment(tip domain.Tip, paymentID string, amount int64) (string, error) {
+	if amount != tip.Amount {
+		return "", errors.New("captured amount does not match the order amount")
+	}
+	if tip.Status != domain.TipPending && tip.PaymentID != paymentID {
+		return "processed", nil
+	}
+
+	if err := p.tipSettlement().settle(tip, paymentID); err != nil {
+		return "", err
+	}
+	return "processed", nil
+}
+
+func (p *subscriptionUseCase) tipSettlement() tipSettlement {
+	return tipSettlement{
+		tips:          p.tips,
+		ledger:        p.ledger,
+		settings:      p.settings,
+		notifications: p.notifications,
+	}
+}
+
+// disputeStatuses maps the dispute webhooks to the status they leave the dispute in
+var disputeStatuses = map[string]string{
+	"payment.dispute.created":         domain.DisputeOpen,
+	"payment.dispute.action_required": domain.DisputeOpen,
+	"payment.dispute.under_review":    domain.DisputeUnderReview,
+	"payment.dispute.won":             domain.DisputeWon,
+	"payment.dispute.lost":            domain.DisputeLost,
+	"payment.dispute.closed":          domain.DisputeClosed,
+}
+
+// applyDispute keeps the dispute in step with the gateway. An open dispute suspends the subscription until it is resolved,
+// a won dispute gives the access back and a lost one is a chargeback, taken out like a refund and ending the subscription.
+func (p *subscriptionUseCase) applyDispute(webhook payments.WebhookEvent) error {
+	entity := webhook.Payload.Dispute.Entity
+
+	subscription, err := p.repository.GetSubscriptionByPaymentID(entity.PaymentID)
+	if err != nil {
+		return err
+	}
+
+	dispute := domain.PaymentDispute{
+		DisputeID:          entity.ID,
+		SubscriptionListID: subscription.ID,
+		PaymentID:          entity.PaymentID,
+		Amount:             entity.Amount,
+		ReasonCode:         entity.ReasonCode,
+		Status:             disputeStatuses[webhook.Event],
+		NeedsReview:        true,
+	}
+	if _, err := p.repository.SaveDispute(&dispute); err != nil {
+		return err
+	}
+
+	switch dispute.Status {
+	case domain.DisputeOpen, domain.DisputeUnderReview:
+		suspended, err := p.repository.SuspendSubscription(subscription.ID)
+		if err != nil {
+			return err
+		}
+		if suspended {
+			p.notify(subscription.UserID, "subscription_suspended", "Your subscription is on hold",
+				"A dispute was raised on your payment, your subscription is on hold until it is resolved.")
+		}
+
+	case domain.DisputeWon:
+		reinstated, err := p.repository.SetSubscriptionStatus(subscription.ID, domain.SubscriptionSuspended, domain.SubscriptionActive)
+		if err != nil {
+			return err
+		}
+		if reinstated {
+			p.notify(subscription.UserID, "subscription_reinstated", "Your subscription is back",
+				"The dispute on your payment was resolved and your subscription is active again.")
+		}
+
+	case domain.DisputeLost:
+		amount := subscription.Amount - subscription.AmountRefunded
+		if dispute.Amount < amount {
+			amount = dispute.Amount
+		}
+		if amount <= 0 {
+			return p.repository.RevokeSubscription(subscription.ID)
+		}
+
+		chargeback := domain.PaymentRefund{
+			SubscriptionListID: subscription.ID,
+			PaymentID:          subscription.PaymentID,
+			RefundID:           dispute.DisputeID,
+			Amount:             amount,
+			Reason:             "chargeback " + dispute.ReasonCode,
+			Source:             domain.RefundSourceChargeback,
+			RevokeAccess:       true,
+		}
+		return p.applyRefund(subscription, &chargeback)
+	}
+
 	return nil
+}
+
+// RefundSubscription refunds all or part of a subscription's payment through the gateway.
+func (p *subscriptionUseCase) RefundSubscription(request models.RefundRequest) (domain.PaymentRefund, error) {
+	subscription, err := p.repository.GetSubscriptionByID(request.SubscriptionID)
+	if err != nil {
+		return domain.PaymentRefund{}, err
+	}
 
+	switch {
+	case subscription.PaymentStatus == "REFUNDED":
+		return domain.PaymentRefund{}, errors.New("payment is already refunded in full")
+	case subscription.PaymentStatus != "PAID" || subscription.PaymentID == "":
+		return domain.PaymentRefund{}, errors.New("only paid subscriptions can be refunded")
+	case subscription.Amount <= 0 || strings.HasPrefix(subscription.PaymentID, "free_"):
+		return domain.PaymentRefund{}, errors.New("nothing was charged for this subscription")
+	}
+
+	remaining := subscription.Amount - subscription.AmountRefunded
+	amount := remaining
+	if request.Amount != 0 {
+		amount = money.FromMajor(request.Amount, subscription.Currency).Amount
+	}
+	if amount <= 0 || amount > remaining {
+		return domain.PaymentRefund{}, fmt.Errorf("refund must be above 0 and at most %s", money.Money{Amount: remaining, Currency: subscription.Currency})
+	}
+
+	gatewayRefund, err := p.gateway.Refund(subscription.PaymentID, amount)
+	if err != nil {
+		return domain.PaymentRefund{}, fmt.Errorf("gateway refused the refund: %w", err)
+	}
+
+	refund := domain.PaymentRefund{
+		SubscriptionListID: subscription.ID,
+		PaymentID:          subscription.PaymentID,
+		RefundID:           gatewayRefund.ID,
+		Amount:             gatewayRefund.Amount,
+		Reason:             strings.TrimSpace(request.Reason),
+		Source:             domain.RefundSourceAdmin,
+		RevokeAccess:       request.RevokeAccess,
+	}
+	if err := p.applyRefund(subscription, &refund); err != nil {
+		return domain.PaymentRefund{}, err
+	}
+
+	return refund, nil
+}
+
+// applyRefund records a refund once, whether the admin's request or the gateway's webhook gets here first,
+// and takes it out of the ledger so it no longer counts as revenue.
+func (p *subscriptionUseCase) applyRefund(subscription domain.SubscriptionList, refund *domain.PaymentRefund) error {
+	created, err := p.repository.RecordRefund(refund)
+	if err != nil {
+		return err
+	}
+
+	// applied even when the webhook recorded the refund first
+	if refund.RevokeAccess {
+		if err := p.repository.RevokeSubscription(subscription.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := p.recordRefund(refund.PaymentID, refund.RefundID, refund.Amount); err != nil {
+		return err
+	}
+
+	if !created {
+		return nil
+	}
+
+	if refund.Source == domain.RefundSourceChargeback {
+		p.notify(subscription.UserID, "subscription_chargeback", "Your subscription has ended",
+			"Your bank reversed the payment for your subscription, so it has ended.")
+		return nil
+	}
+
+	message := fmt.Sprintf("%s of your payment was refunded.", money.Money{Amount: refund.Amount, Currency: subscription.Currency})
+	if refund.RevokeAccess {
+		message += " Your subscription has ended."
+	}
+	p.notify(subscription.UserID, "subscription_refunded", "Your payment was refunded", message)
+
+	return nil
+}
+
+func (p *subscriptionUseCase) ListDisputes(needsReview bool, page, limit int) ([]domain.PaymentDispute, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	disputes, err := p.repository.ListDisputes(needsReview, page, limit)
+	if err != nil {
+		return nil, err
+	}
+	if disputes == nil {
+		disputes = []domain.PaymentDispute{}
+	}
+
+	return disputes, nil
+}
+
+// ReviewDispute closes the review of a dispute, a subscription still suspended by it is reinstated or cancelled.
+func (p *subscriptionUseCase) ReviewDispute(review models.DisputeReview) error {
+	dispute, err := p.repository.GetDispute(review.DisputeID)
+	if err != nil {
+		return err
+	}
+	if !dispute.NeedsReview {
+		return errors.New("dispute was already reviewed")
+	}
+
+	to := domain.SubscriptionCancelled
+	if review.Reinstate {
+		to = domain.SubscriptionActive
+	}
+	if _, err := p.repository.SetSubscriptionStatus(dispute.SubscriptionListID, domain.SubscriptionSuspended, to); err != nil {
+		return err
+	}
+
+	return p.repository.MarkDisputeReviewed(dispute.ID, strings.TrimSpace(review.Note))
+}
+
+// ReplayPaymentEvent applies a stored webhook again, e.g. after fixing what made it fail.
+func (p *subscriptionUseCase) ReplayPaymentEvent(eventID uint) (domain.PaymentEvent, error) {
+	event, err := p.repository.GetPaymentEvent(eventID)
+	if err != nil {
+		return domain.PaymentEvent{}, err
+	}
+
+	if err := p.processPaymentEvent(event); err != nil {
+		return domain.PaymentEvent{}, err
+	}
+
+	return p.repository.GetPaymentEvent(eventID)
+}
+
+func (p *subscriptionUseCase) ListPaymentEvents(status string, page, limit int) ([]domain.PaymentEvent, error) {
+	switch status {
+	case "", "received", "processed", "ignored", "failed":
+	default:
+		return nil, errors.New("status must be one of received, processed, ignored, failed")
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	return p.repository.ListPaymentEvents(status, page, limit)
+}
+
+// ProcessSubscriptionLifecycle sends renewal reminders and moves subscriptions whose period ended along the lifecycle:
+// cancelled ones end, unpaid ones go past due and past due ones expire once the grace period is over.
+// Checkouts left unpaid for longer than pendingCheckoutTTL are expired.
+func (p *subscriptionUseCase) ProcessSubscriptionLifecycle() (models.LifecycleResult, error) {
+	var result models.LifecycleResult
+	now := time.Now()
+
+	due, err := p.repository.GetSubscriptionsForRenewalReminder(now.Add(renewalReminderWindow))
+	if err != nil {
+		return result, err
+	}
+	for _, subscription := range due {
+		p.notify(subscription.UserID, "subscription_renewal", "Your subscription ends soon",
+			fmt.Sprintf("Your subscription ends on %s, renew it to keep access.", subscription.CurrentPeriodEnd.Format("2 Jan 2006")))
+		if err := p.repository.MarkRenewalReminderSent(subscription.ID); err != nil {
+			return result, err
+		}
+		result.Reminded++
+	}
+
+	ended, err := p.repository.GetSubscriptionsEndedBefore(domain.SubscriptionActive, now)
+	if err != nil {
+		return result, err
+	}
+	for _, subscription := range ended {
+		to := domain.SubscriptionPastDue
+		if subscription.CancelAtPeriodEnd {
+			to = domain.SubscriptionCancelled
+		}
+
+		changed, err := p.repository.SetSubscriptionStatus(subscription.ID, domain.SubscriptionActive, to)
+		if err != nil {
+			return result, err
+		}
+		if !changed {
+			continue
+		}
+
+		if to == domain.SubscriptionCancelled {
+			p.notify(subscription.UserID, "subscription_cancelled", "Your subscription has ended", "Your cancelled subscription has reached the end of its period.")
+			result.Cancelled++
+			continue
+		}
+		p.notify(subscription.UserID, "subscription_past_due", "Your subscription is past due",
+			fmt.Sprintf("Renew within %d days to keep access.", int(gracePeriod.Hours()/24)))
+		result.PastDue++
+	}
+
+	lapsed, err := p.repository.GetSubscriptionsEndedBefore(domain.SubscriptionPastDue, now.Add(-gracePeriod))
+	if err != nil {
+		return result, err
+	}
+	for _, subscription := range lapsed {
+		changed, err := p.repository.SetSubscriptionStatus(subscription.ID, domain.SubscriptionPastDue, domain.SubscriptionExpired)
+		if err != nil {
+			return result, err
+		}
+		if !changed {
+			continue
+		}
+		p.notify(subscription.UserID, "subscription_expired", "Your subscription has expired", "Subscribe again to regain access to exclusive videos.")
+		result.Expired++
+	}
+
+	abandoned, err := p.repository.ExpireStalePendingSubscriptions(now.Add(-pendingCheckoutTTL))
+	if err != nil {
+		return result, err
+	}
+	result.Abandoned = int(abandoned)
+
+	return result, nil
+}
+
+// notify stores a notification for the user, a failure here must not stop the lifecycle job
+func (p *subscriptionUseCase) notify(userID int, kind, title, message string) {
+	notification := domain.Notification{
+		UserID:  userID,
+		Type:    kind,
+		Title:   title,
+		Message: message,
+	}
+	if err := p.notifications.CreateNotification(&notification); err != nil {
+		log.Println("Error creating notification:", err)
+	}
+}
+
+// ownSubscription loads the subscription and makes sure it belongs to the user
+func (p *subscriptionUseCase) ownSubscription(userID, subscriptionID int) (domain.SubscriptionList, error) {
+	subscription, err := p.repository.GetSubscriptionByID(subscriptionID)
+	if err != nil {
+		return domain.SubscriptionList{}, err
+	}
+	if subscription.UserID != userID {
+		return domain.SubscriptionList{}, errors.New("subscription does not belong to the user")
+	}
+
+	return subscription, nil
+}
+
+// CancelSubscription stops the subscription from continuing after the paid period, a past due one ends right away.
+func (p *subscriptionUseCase) CancelSubscription(userID, subscriptionID int) error {
+	subscription, err := p.ownSubscription(userID, subscriptionID)
+	if err != nil {
+		return err
+	}
+
+	switch subscription.Status {
+	case domain.SubscriptionActive:
+		if subscription.CancelAtPeriodEnd {
+			return errors.New("subscription is already cancelled")
+		}
+		return p.repository.SetCancelAtPeriodEnd(subscription.ID, true)
+	case domain.SubscriptionPastDue:
+		_, err := p.repository.SetSubscriptionStatus(subscription.ID, domain.SubscriptionPastDue, domain.SubscriptionCancelled)
+		return err
+	}
+
+	return errors.New("only active or past due subscriptions can be cancelled")
+}
+
+// ResumeSubscription undoes a cancellation that has not taken effect yet.
+func (p *subscriptionUseCase) ResumeSubscription(userID, subscriptionID int) error {
+	subscription, err := p.ownSubscription(userID, subscriptionID)
+	if err != nil {
+		return err
+	}
+
+	if subscription.Status != domain.SubscriptionActive || !subscription.CancelAtPeriodEnd {
+		return errors.New("only a subscription cancelled at the end of its period can be resumed")
+	}
+
+	return p.repository.SetCancelAtPeriodEnd(subscription.ID, false)
+}
+
+func (p *subscriptionUseCase) ListSubscriptions(userID int) ([]models.UserSubscription, error) {
+	subscriptions, err := p.repository.ListUserSubscriptions(userID)
+	if err != nil {
+		return nil, err
+	}
+	if subscriptions == nil {
+		subscriptions = []models.UserSubscription{}
+	}
+
+	return subscriptions, nil
 }
 
 // func (p *subscriptionUseCase) StartSubscriptionUpdateJob() {
@@ -143,18 +1257,29 @@ func (u *subscriptionUseCase) GetAnalytics(userID int, startDate string, endDate
 		return models.AnalyticsData{}, err
 	}
 
-	// Fetch revenue for the given date range
-	revenue, err := u.repository.GetRevenue(userID, startDate, endDate)
+	// Revenue is the creator's share of what was charged for subscriptions, gifts and tips, less refunds, as the ledger recorded it
+	totals, err := u.ledger.GetCreatorTotals(userID, startDate, endDate)
 	if err != nil {
 		return models.AnalyticsData{}, err
 	}
 
 	// Create and return the analytics data
 	analyticsData := models.AnalyticsData{
-		SubscribersCount: subscribersCount,
-		Revenue:          revenue,
+		SubscribersCount:  subscribersCount,
+		Currency:          u.gateway.Currency(),
+		RevenueByCurrency: make(map[string]float64),
 		// Add more fields if needed
 	}
+	for _, currency := range totalCurrencies(totals, analyticsData.Currency) {
+		analyticsData.RevenueByCurrency[currency] = majorAmount(totals[currency][domain.LedgerCharge]-totals[currency][domain.LedgerRefund], currency)
+	}
+	analyticsData.Revenue = analyticsData.RevenueByCurrency[analyticsData.Currency]
+
+	tips, err := u.tips.GetTipTotals(userID, startDate, endDate)
+	if err != nil {
+		return models.AnalyticsData{}, err
+	}
+	analyticsData.Tips = majorAmount(tips[analyticsData.Currency], analyticsData.Currency)
 
 	return analyticsData, nil
 }
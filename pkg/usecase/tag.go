@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"errors"
+	"main/pkg/domain"
+	interfaces "main/pkg/repository/interface"
+	services "main/pkg/usecase/interface"
+	"main/pkg/utils/models"
+	"strings"
+)
+
+type tagUseCase struct {
+	repository interfaces.TagRepository
+}
+
+func NewTagUseCase(repo interfaces.TagRepository) services.TagUseCase {
+	return &tagUseCase{
+		repository: repo,
+	}
+}
+
+const maxTagLimit = 100
+
+func validTagPage(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > maxTagLimit {
+		limit = 20
+	}
+	return page, limit
+}
+
+// AddAlias makes the alias resolve to the tag whenever creators use it.
+func (t *tagUseCase) AddAlias(tagID uint, alias string) (domain.TagAlias, error) {
+	if strings.TrimSpace(alias) == "" {
+		return domain.TagAlias{}, errors.New("alias is required")
+	}
+
+	if _, err := t.repository.GetTagByID(tagID); err != nil {
+		return domain.TagAlias{}, err
+	}
+
+	banned, err := t.repository.IsTagBanned(alias)
+	if err != nil {
+		return domain.TagAlias{}, err
+	}
+	if banned {
+		return domain.TagAlias{}, errors.New("a banned tag cannot be used as an alias")
+	}
+
+	if _, found, err := t.repository.FindTagByName(alias); err != nil {
+		return domain.TagAlias{}, err
+	} else if found {
+		return domain.TagAlias{}, errors.New("a tag with that name already exists, merge the tags instead")
+	}
+
+	if _, found, err := t.repository.ResolveTag(alias); err != nil {
+		return domain.TagAlias{}, err
+	} else if found {
+		return domain.TagAlias{}, errors.New("alias already exists")
+	}
+
+	return t.repository.AddAlias(tagID, alias)
+}
+
+func (t *tagUseCase) DeleteAlias(aliasID uint) error {
+	return t.repository.DeleteAlias(aliasID)
+}
+
+func (t *tagUseCase) GetAliases(tagID uint) ([]domain.TagAlias, error) {
+	if _, err := t.repository.GetTagByID(tagID); err != nil {
+		return nil, err
+	}
+
+	return t.repository.GetAliases(tagID)
+}
+
+// MergeTags folds the source tag into the target tag.
+func (t *tagUseCase) MergeTags(sourceID, targetID uint) (models.TagMergeResult, error) {
+	if sourceID == targetID {
+		return models.TagMergeResult{}, errors.New("a tag cannot be merged into itself")
+	}
+
+	return t.repository.MergeTags(sourceID, targetID)
+}
+
+// BanTag stops creators from using the tag and removes it from the videos already using it.
+func (t *tagUseCase) BanTag(tag, reason string) (domain.BannedTag, error) {
+	if strings.TrimSpace(tag) == "" {
+		return domain.BannedTag{}, errors.New("tag is required")
+	}
+
+	banned, err := t.repository.IsTagBanned(tag)
+	if err != nil {
+		return domain.BannedTag{}, err
+	}
+	if banned {
+		return domain.BannedTag{}, errors.New("tag is already banned")
+	}
+
+	return t.repository.BanTag(tag, reason)
+}
+
+func (t *tagUseCase) UnbanTag(bannedID uint) error {
+	return t.repository.UnbanTag(bannedID)
+}
+
+func (t *tagUseCase) GetBannedTags(page, limit int) ([]domain.BannedTag, error) {
+	page, limit = validTagPage(page, limit)
+	return t.repository.GetBannedTags(page, limit)
+}
+
+func (t *tagUseCase) GetTagStats(page, limit int) ([]models.TagStat, error) {
+	page, limit = validTagPage(page, limit)
+	return t.repository.GetTagStats(page, limit)
+}
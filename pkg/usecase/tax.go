@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"main/pkg/domain"
+	interfaces "main/pkg/repository/interface"
+	services "main/pkg/usecase/interface"
+	"main/pkg/utils/models"
+	"math"
+	"regexp"
+	"strings"
+)
+
+type taxUseCase struct {
+	repository interfaces.TaxRepository
+}
+
+func NewTaxUseCase(repo interfaces.TaxRepository) services.TaxUseCase {
+	return &taxUseCase{
+		repository: repo,
+	}
+}
+
+const maxTaxNameLen = 30
+
+var countryCodePattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// countryCode normalises an ISO 3166 alpha-2 country code, e.g. "in" to "IN"
+func countryCode(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !countryCodePattern.MatchString(code) {
+		return "", fmt.Errorf("%q is not a two letter country code", code)
+	}
+
+	return code, nil
+}
+
+// taxQuote works out the tax on an amount in minor units. An inclusive rate takes the tax out of the amount,
+// an exclusive one adds it on top. Without a rule nothing is charged.
+func taxQuote(rule *domain.TaxRule, amount int64) models.TaxQuote {
+	if rule == nil || rule.Rate <= 0 || amount <= 0 {
+		return models.TaxQuote{}
+	}
+
+	quote := models.TaxQuote{Name: rule.Name, Rate: rule.Rate, Inclusive: rule.Inclusive}
+	if rule.Inclusive {
+		quote.Amount = amount - int64(math.Round(float64(amount)*100/(100+rule.Rate)))
+	} else {
+		quote.Amount = int64(math.Round(float64(amount) * rule.Rate / 100))
+	}
+
+	return quote
+}
+
+// taxLabel names the tax for checkouts and invoices, e.g. "GST 18% (included)", empty when no tax is charged
+func taxLabel(tax models.TaxQuote) string {
+	if tax.Amount <= 0 {
+		return ""
+	}
+
+	how := "added"
+	if tax.Inclusive {
+		how = "included"
+	}
+
+	return fmt.Sprintf("%s %g%% (%s)", tax.Name, tax.Rate, how)
+}
+
+func (t *taxUseCase) ListTaxRules() ([]domain.TaxRule, error) {
+	rules, err := t.repository.ListTaxRules()
+	if err != nil {
+		return nil, err
+	}
+	if rules == nil {
+		rules = []domain.TaxRule{}
+	}
+
+	return rules, nil
+}
+
+// SetTaxRule sets the tax charged in the region from the next checkout on, payments already made keep the tax they had.
+func (t *taxUseCase) SetTaxRule(details models.TaxRuleDetails) (domain.TaxRule, error) {
+	region := strings.TrimSpace(details.Region)
+	if region != domain.TaxRegionDefault {
+		var err error
+		if region, err = countryCode(region); err != nil {
+			return domain.TaxRule{}, err
+		}
+	}
+
+	name := strings.TrimSpace(details.Name)
+	if name == "" || len(name) > maxTaxNameLen {
+		return domain.TaxRule{}, fmt.Errorf("name must be between 1 and %d characters", maxTaxNameLen)
+	}
+	if details.Rate <= 0 || details.Rate > 100 {
+		return domain.TaxRule{}, errors.New("rate must be above 0 and at most 100 percent")
+	}
+
+	rule := domain.TaxRule{Region: region, Name: name, Rate: details.Rate, Inclusive: details.Inclusive}
+	if err := t.repository.SaveTaxRule(&rule); err != nil {
+		return domain.TaxRule{}, err
+	}
+
+	return rule, nil
+}
+
+// DeleteTaxRule stops charging tax in the region, buyers there then get the default rule if there is one.
+func (t *taxUseCase) DeleteTaxRule(region string) error {
+	region = strings.ToUpper(strings.TrimSpace(region))
+
+	deleted, err := t.repository.DeleteTaxRule(region)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.New("no tax rule for region " + region)
+	}
+
+	return nil
+}
@@ -0,0 +1,343 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"main/pkg/domain"
+	"main/pkg/money"
+	"main/pkg/payments"
+	interfaces "main/pkg/repository/interface"
+	services "main/pkg/usecase/interface"
+	"main/pkg/utils/models"
+	"strconv"
+	"strings"
+)
+
+type tierUseCase struct {
+	repository interfaces.TierRepository
+	settings   interfaces.SettingRepository
+	gateway    payments.Gateway
+}
+
+func NewTierUseCase(repo interfaces.TierRepository, settingRepo interfaces.SettingRepository, gateway payments.Gateway) services.TierUseCase {
+	return &tierUseCase{
+		repository: repo,
+		settings:   settingRepo,
+		gateway:    gateway,
+	}
+}
+
+const (
+	tierMinPriceKey = "tier_min_price"
+	tierMaxPriceKey = "tier_max_price"
+
+	// used until an admin sets the limits
+	defaultTierMinPrice = 10
+	defaultTierMaxPrice = 10000
+
+	maxTierDuration  = 365
+	maxTierTrialDays = 90
+	maxTierNameLen   = 50
+	maxTierPerksLen  = 1000
+	maxVideosPerTier = 500
+	maxTierRegions   = 250
+)
+
+func (t *tierUseCase) GetTierPriceLimits() (models.TierPriceLimits, error) {
+	limits := models.TierPriceLimits{MinPrice: defaultTierMinPrice, MaxPrice: defaultTierMaxPrice}
+
+	values, err := t.settings.GetSettings(tierMinPriceKey, tierMaxPriceKey)
+	if err != nil {
+		return models.TierPriceLimits{}, err
+	}
+
+	if value, ok := values[tierMinPriceKey]; ok {
+		if limits.MinPrice, err = strconv.ParseFloat(value, 64); err != nil {
+			return models.TierPriceLimits{}, fmt.Errorf("invalid %s setting: %w", tierMinPriceKey, err)
+		}
+	}
+	if value, ok := values[tierMaxPriceKey]; ok {
+		if limits.MaxPrice, err = strconv.ParseFloat(value, 64); err != nil {
+			return models.TierPriceLimits{}, fmt.Errorf("invalid %s setting: %w", tierMaxPriceKey, err)
+		}
+	}
+
+	return limits, nil
+}
+
+// SetTierPriceLimits changes the price range for tiers, existing tiers keep their price until they are edited.
+func (t *tierUseCase) SetTierPriceLimits(limits models.TierPriceLimits) error {
+	if limits.MinPrice <= 0 {
+		return errors.New("min price must be above 0")
+	}
+	if limits.MaxPrice < limits.MinPrice {
+		return errors.New("max price cannot be below the min price")
+	}
+
+	return t.settings.SetSettings(map[string]string{
+		tierMinPriceKey: strconv.FormatFloat(limits.MinPrice, 'f', -1, 64),
+		tierMaxPriceKey: strconv.FormatFloat(limits.MaxPrice, 'f', -1, 64),
+	})
+}
+
+// validateTier checks the tier against the rules and the admin-set price range
+func (t *tierUseCase) validateTier(tier domain.SubscriptionPlan) error {
+	if tier.Name == "" {
+		return errors.New("name is required")
+	}
+	if len(tier.Name) > maxTierNameLen {
+		return fmt.Errorf("name can be at most %d characters", maxTierNameLen)
+	}
+	if len(tier.Perks) > maxTierPerksLen {
+		return fmt.Errorf("perks can be at most %d characters", maxTierPerksLen)
+	}
+	if tier.Duration < 1 || tier.Duration > maxTierDuration {
+		return fmt.Errorf("duration must be between 1 and %d days", maxTierDuration)
+	}
+
+	if tier.TrialDays < 0 || tier.TrialDays > maxTierTrialDays {
+		return fmt.Errorf("trial days must be between 0 and %d", maxTierTrialDays)
+	}
+
+	return t.validatePrice(tier.Price)
+}
+
+// validatePrice keeps prices in the platform currency within the admin-set range, other currencies only need a price
+func (t *tierUseCase) validatePrice(price money.Money) error {
+	if !money.Supported(price.Currency) {
+		return fmt.Errorf("prices cannot be set in %s", price.Currency)
+	}
+	if price.Amount <= 0 {
+		return errors.New("price must be above 0")
+	}
+	if price.Currency != t.gateway.Currency() {
+		return nil
+	}
+
+	limits, err := t.GetTierPriceLimits()
+	if err != nil {
+		return err
+	}
+	if price.Major() < limits.MinPrice || price.Major() > limits.MaxPrice {
+		return fmt.Errorf("price must be between %.2f and %.2f %s", limits.MinPrice, limits.MaxPrice, price.Currency)
+	}
+
+	return nil
+}
+
+// ownTier loads the tier and makes sure it belongs to the creator
+func (t *tierUseCase) ownTier(creatorID, tierID int) (domain.SubscriptionPlan, error) {
+	tier, err := t.repository.GetTier(tierID)
+	if err != nil {
+		return domain.SubscriptionPlan{}, err
+	}
+	if tier.CreatorID != creatorID {
+		return domain.SubscriptionPlan{}, errors.New("tier does not belong to the creator")
+	}
+
+	return tier, nil
+}
+
+func (t *tierUseCase) tierResponse(tier domain.SubscriptionPlan) (models.Tier, error) {
+	videoIDs, err := t.repository.GetTierVideoIDs(tier.ID)
+	if err != nil {
+		return models.Tier{}, err
+	}
+
+	prices, err := t.repository.GetTierPrices(tier.ID)
+	if err != nil {
+		return models.Tier{}, err
+	}
+
+	return models.Tier{
+		ID:             tier.ID,
+		CreatorID:      tier.CreatorID,
+		Name:           tier.Name,
+		Duration:       tier.Duration,
+		Price:          tier.Price,
+		RegionalPrices: tierPrices(prices),
+		Perks:          tier.Perks,
+		TrialDays:      tier.TrialDays,
+		VideoIDs:       videoIDs,
+	}, nil
+}
+
+func tierPrices(prices []domain.PlanPrice) map[string]money.Money {
+	byRegion := make(map[string]money.Money, len(prices))
+	for _, price := range prices {
+		byRegion[price.Region] = price.Price
+	}
+
+	return byRegion
+}
+
+func (t *tierUseCase) CreateTier(creatorID int, input models.AddTier) (models.Tier, error) {
+	currency := strings.ToUpper(strings.TrimSpace(input.Currency))
+	if currency == "" {
+		currency = t.gateway.Currency()
+	}
+
+	tier := domain.SubscriptionPlan{
+		CreatorID: creatorID,
+		Name:      strings.TrimSpace(input.Name),
+		Duration:  input.Duration,
+		Price:     money.FromMajor(input.Price, currency),
+		Perks:     strings.TrimSpace(input.Perks),
+		TrialDays: input.TrialDays,
+	}
+
+	if err := t.validateTier(tier); err != nil {
+		return models.Tier{}, err
+	}
+
+	if err := t.repository.CreateTier(&tier); err != nil {
+		return models.Tier{}, err
+	}
+
+	return t.tierResponse(tier)
+}
+
+// UpdateTier changes the tier, current subscribers keep the period they paid for and get the new terms when they renew.
+func (t *tierUseCase) UpdateTier(creatorID int, input models.UpdateTier) (models.Tier, error) {
+	tier, err := t.ownTier(creatorID, input.ID)
+	if err != nil {
+		return models.Tier{}, err
+	}
+
+	if input.Name != nil {
+		tier.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Duration != nil {
+		tier.Duration = *input.Duration
+	}
+	if input.Price != nil || input.Currency != nil {
+		price, currency := tier.Price.Major(), tier.Price.Currency
+		if input.Price != nil {
+			price = *input.Price
+		}
+		if input.Currency != nil {
+			currency = strings.ToUpper(strings.TrimSpace(*input.Currency))
+		}
+		tier.Price = money.FromMajor(price, currency)
+	}
+	if input.Perks != nil {
+		tier.Perks = strings.TrimSpace(*input.Perks)
+	}
+	if input.TrialDays != nil {
+		tier.TrialDays = *input.TrialDays
+	}
+
+	if err := t.validateTier(tier); err != nil {
+		return models.Tier{}, err
+	}
+
+	if err := t.repository.UpdateTier(tier); err != nil {
+		return models.Tier{}, err
+	}
+
+	return t.tierResponse(tier)
+}
+
+// DeleteTier removes the tier, archived is true when it had subscriptions and was only withdrawn from sale.
+func (t *tierUseCase) DeleteTier(creatorID, tierID int) (bool, error) {
+	if _, err := t.ownTier(creatorID, tierID); err != nil {
+		return false, err
+	}
+
+	return t.repository.DeleteTier(tierID)
+}
+
+func (t *tierUseCase) ListTiers(creatorID int) ([]models.Tier, error) {
+	tiers, err := t.repository.ListCreatorTiers(creatorID)
+	if err != nil {
+		return nil, err
+	}
+	if tiers == nil {
+		tiers = []models.Tier{}
+	}
+
+	for i := range tiers {
+		prices, err := t.repository.GetTierPrices(tiers[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		tiers[i].RegionalPrices = tierPrices(prices)
+	}
+
+	return tiers, nil
+}
+
+// SetTierVideos replaces the exclusive videos the tier unlocks, all of them must be the creator's own exclusive videos.
+func (t *tierUseCase) SetTierVideos(creatorID int, input models.TierVideos) ([]uint, error) {
+	if _, err := t.ownTier(creatorID, input.TierID); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uint]bool)
+	videoIDs := []uint{}
+	for _, id := range input.VideoIDs {
+		if !seen[id] {
+			seen[id] = true
+			videoIDs = append(videoIDs, id)
+		}
+	}
+	if len(videoIDs) > maxVideosPerTier {
+		return nil, fmt.Errorf("a tier can unlock at most %d videos", maxVideosPerTier)
+	}
+
+	if len(videoIDs) > 0 {
+		videos, err := t.repository.GetCreatorVideos(creatorID, videoIDs)
+		if err != nil {
+			return nil, err
+		}
+		if len(videos) != len(videoIDs) {
+			return nil, errors.New("some videos were not found among the creator's videos")
+		}
+		for _, video := range videos {
+			if !video.Exclusive {
+				return nil, fmt.Errorf("video %d is not exclusive", video.ID)
+			}
+		}
+	}
+
+	if err := t.repository.SetTierVideos(input.TierID, videoIDs); err != nil {
+		return nil, err
+	}
+
+	return t.repository.GetTierVideoIDs(input.TierID)
+}
+
+// SetTierPrices replaces the prices buyers in other regions pay for the tier, a region without one pays the tier's own price.
+func (t *tierUseCase) SetTierPrices(creatorID int, input models.TierPrices) (map[string]money.Money, error) {
+	if _, err := t.ownTier(creatorID, input.TierID); err != nil {
+		return nil, err
+	}
+	if len(input.Prices) > maxTierRegions {
+		return nil, fmt.Errorf("a tier can have at most %d regional prices", maxTierRegions)
+	}
+
+	prices := []domain.PlanPrice{}
+	seen := make(map[string]bool)
+	for _, input := range input.Prices {
+		region, err := countryCode(input.Region)
+		if err != nil {
+			return nil, err
+		}
+		if seen[region] {
+			return nil, fmt.Errorf("region %s is listed twice", region)
+		}
+		seen[region] = true
+
+		price := money.FromMajor(input.Price, strings.TrimSpace(input.Currency))
+		if err := t.validatePrice(price); err != nil {
+			return nil, fmt.Errorf("price for %s: %w", region, err)
+		}
+		prices = append(prices, domain.PlanPrice{Region: region, Price: price})
+	}
+
+	if err := t.repository.SetTierPrices(input.TierID, prices); err != nil {
+		return nil, err
+	}
+
+	return tierPrices(prices), nil
+}
@@ -0,0 +1,309 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"main/pkg/domain"
+	"main/pkg/money"
+	"main/pkg/payments"
+	interfaces "main/pkg/repository/interface"
+	services "main/pkg/usecase/interface"
+	"main/pkg/utils/models"
+	"strconv"
+	"strings"
+)
+
+const (
+	tipMinAmountKey = "tip_min_amount"
+	tipMaxAmountKey = "tip_max_amount"
+
+	// used until an admin sets the limits, in the platform currency's major unit
+	defaultTipMinAmount = 10
+	defaultTipMaxAmount = 50000
+
+	maxTipMessageLen = 200
+)
+
+// tipSettlement records paid and refunded tips, shared by the tip checkout and the payment webhook.
+type tipSettlement struct {
+	tips          interfaces.TipRepository
+	ledger        interfaces.LedgerRepository
+	settings      interfaces.SettingRepository
+	notifications interfaces.NotificationRepository
+}
+
+type tipUseCase struct {
+	tipSettlement
+	gateway payments.Gateway
+}
+
+func NewTipUseCase(repo interfaces.TipRepository, ledgerRepo interfaces.LedgerRepository, settingRepo interfaces.SettingRepository, notificationRepo interfaces.NotificationRepository, gateway payments.Gateway) services.TipUseCase {
+	return &tipUseCase{
+		tipSettlement: tipSettlement{
+			tips:          repo,
+			ledger:        ledgerRepo,
+			settings:      settingRepo,
+			notifications: notificationRepo,
+		},
+		gateway: gateway,
+	}
+}
+
+func (t *tipUseCase) GetTipLimits() (models.TipLimits, error) {
+	limits := models.TipLimits{MinAmount: defaultTipMinAmount, MaxAmount: defaultTipMaxAmount}
+
+	values, err := t.settings.GetSettings(tipMinAmountKey, tipMaxAmountKey)
+	if err != nil {
+		return models.TipLimits{}, err
+	}
+
+	if value, ok := values[tipMinAmountKey]; ok {
+		if limits.MinAmount, err = strconv.ParseFloat(value, 64); err != nil {
+			return models.TipLimits{}, fmt.Errorf("invalid %s setting: %w", tipMinAmountKey, err)
+		}
+	}
+	if value, ok := values[tipMaxAmountKey]; ok {
+		if limits.MaxAmount, err = strconv.ParseFloat(value, 64); err != nil {
+			return models.TipLimits{}, fmt.Errorf("invalid %s setting: %w", tipMaxAmountKey, err)
+		}
+	}
+
+	return limits, nil
+}
+
+// SetTipLimits changes the range tips must be in, from the next tip on.
+func (t *tipUseCase) SetTipLimits(limits models.TipLimits) error {
+	if limits.MinAmount <= 0 {
+		return errors.New("min amount must be above 0")
+	}
+	if limits.MaxAmount < limits.MinAmount {
+		return errors.New("max amount cannot be below the min amount")
+	}
+
+	return t.settings.SetSettings(map[string]string{
+		tipMinAmountKey: strconv.FormatFloat(limits.MinAmount, 'f', -1, 64),
+		tipMaxAmountKey: strconv.FormatFloat(limits.MaxAmount, 'f', -1, 64),
+	})
+}
+
+// SendTip opens a gateway order for a tip to the creator, it is paid through the same checkout as subscriptions.
+func (t *tipUseCase) SendTip(userID int, order models.TipOrder) (models.TipCheckout, error) {
+	if order.CreatorID == userID {
+		return models.TipCheckout{}, errors.New("cannot tip yourself")
+	}
+
+	creator, err := t.tips.FindUsername(order.CreatorID)
+	if err != nil {
+		return models.TipCheckout{}, err
+	}
+	if creator == "" {
+		return models.TipCheckout{}, errors.New("creator not found")
+	}
+
+	limits, err := t.GetTipLimits()
+	if err != nil {
+		return models.TipCheckout{}, err
+	}
+	if order.Amount < limits.MinAmount || order.Amount > limits.MaxAmount {
+		return models.TipCheckout{}, fmt.Errorf("tip must be between %.2f and %.2f %s", limits.MinAmount, limits.MaxAmount, t.gateway.Currency())
+	}
+
+	message := strings.TrimSpace(order.Message)
+	if len(message) > maxTipMessageLen {
+		return models.TipCheckout{}, fmt.Errorf("message can be at most %d characters", maxTipMessageLen)
+	}
+
+	videoID := order.VideoID
+	if order.CommentID != nil {
+		// the comment is highlighted under the tipper's name, which an anonymous tip would give away
+		if order.Anonymous {
+			return models.TipCheckout{}, errors.New("an anonymous tip cannot be left on a comment")
+		}
+		comment, err := t.tips.GetComment(*order.CommentID)
+		if err != nil {
+			return models.TipCheckout{}, err
+		}
+		if int(comment.UserID) != userID {
+			return models.TipCheckout{}, errors.New("tips can only be left on your own comments")
+		}
+		if videoID != nil && *videoID != comment.VideoID {
+			return models.TipCheckout{}, errors.New("comment is not on that video")
+		}
+		videoID = &comment.VideoID
+	}
+	if videoID != nil {
+		owner, err := t.tips.GetVideoOwner(*videoID)
+		if err != nil {
+			return models.TipCheckout{}, err
+		}
+		if owner != order.CreatorID {
+			return models.TipCheckout{}, errors.New("video is not the creator's")
+		}
+	}
+
+	amount := money.FromMajor(order.Amount, t.gateway.Currency())
+	tip := domain.Tip{
+		UserID:    userID,
+		CreatorID: order.CreatorID,
+		VideoID:   videoID,
+		CommentID: order.CommentID,
+		Amount:    amount.Amount,
+		Currency:  amount.Currency,
+		Message:   message,
+		Anonymous: order.Anonymous,
+		Status:    domain.TipPending,
+	}
+	if err := t.tips.CreateTip(&tip); err != nil {
+		return models.TipCheckout{}, err
+	}
+
+	gatewayOrder, err := t.gateway.CreateOrder(tip.Amount, tip.Currency, fmt.Sprintf("tip_%d", tip.ID))
+	if err != nil {
+		return models.TipCheckout{}, err
+	}
+	if err := t.tips.SetTipOrder(tip.ID, gatewayOrder.ID); err != nil {
+		return models.TipCheckout{}, err
+	}
+	tip.RazorOrderID = gatewayOrder.ID
+
+	return models.TipCheckout{
+		Tip:      tip,
+		RazorID:  gatewayOrder.ID,
+		Amount:   gatewayOrder.Amount,
+		Currency: gatewayOrder.Currency,
+		Gateway:  t.gateway.Name(),
+		KeyID:    t.gateway.KeyID(),
+	}, nil
+}
+
+// VerifyTipPayment checks the checkout signature before marking the tip paid, the client supplied IDs are not trusted on their own.
+func (t *tipUseCase) VerifyTipPayment(userID int, tipID uint, paymentID, razorID, signature string) (domain.Tip, error) {
+	tip, err := t.tips.GetTip(tipID)
+	if err != nil {
+		return domain.Tip{}, err
+	}
+	if tip.UserID != userID {
+		return domain.Tip{}, errors.New("tip does not belong to the user")
+	}
+
+	if tip.RazorOrderID == "" || tip.RazorOrderID != razorID {
+		return domain.Tip{}, errors.New("payment does not belong to this tip")
+	}
+	if !t.gateway.VerifySignature(razorID, paymentID, signature) {
+		return domain.Tip{}, errors.New("invalid payment signature")
+	}
+
+	// The webhook may have confirmed the payment already
+	if tip.Status != domain.TipPending && tip.PaymentID != paymentID {
+		return domain.Tip{}, errors.New("tip is already paid")
+	}
+
+	if err := t.settle(tip, paymentID); err != nil {
+		return domain.Tip{}, err
+	}
+
+	return t.tips.GetTip(tipID)
+}
+
+// ListSentTips returns the tips the user paid, newest first.
+func (t *tipUseCase) ListSentTips(userID int, page, limit int) ([]models.TipDetails, error) {
+	return t.listTips(models.TipFilter{UserID: userID}, page, limit, false)
+}
+
+// ListReceivedTips returns the tips paid to the creator, newest first, without the tippers who chose to stay anonymous.
+func (t *tipUseCase) ListReceivedTips(creatorID int, page, limit int) ([]models.TipDetails, error) {
+	return t.listTips(models.TipFilter{CreatorID: creatorID}, page, limit, true)
+}
+
+// ListVideoTips returns the tips left on the video, newest first, without the tippers who chose to stay anonymous.
+func (t *tipUseCase) ListVideoTips(videoID uint, page, limit int) ([]models.TipDetails, error) {
+	return t.listTips(models.TipFilter{VideoID: videoID}, page, limit, true)
+}
+
+func (t *tipUseCase) listTips(filter models.TipFilter, page, limit int, hideAnonymous bool) ([]models.TipDetails, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	tips, err := t.tips.ListTips(filter, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if hideAnonymous {
+		for i := range tips {
+			if tips[i].Anonymous {
+				tips[i].UserID, tips[i].Username = 0, ""
+			}
+		}
+	}
+
+	return tips, nil
+}
+
+// settle marks the tip paid and posts it to the ledger, paying an order twice does not record the tip twice.
+func (s tipSettlement) settle(tip domain.Tip, paymentID string) error {
+	paid, err := s.tips.MarkTipPaid(tip.ID, paymentID)
+	if err != nil {
+		return err
+	}
+
+	// Posted even when the tip was already paid, a retried payment still gets its charge if posting it failed before
+	feePercent, err := platformFeePercent(s.settings)
+	if err != nil {
+		return err
+	}
+	transaction := tipChargeTransaction(tip, paymentID, feePercent)
+	if _, err := s.ledger.PostTransaction(&transaction); err != nil {
+		return err
+	}
+
+	if !paid {
+		return nil
+	}
+
+	from := "Someone"
+	if !tip.Anonymous {
+		username, err := s.tips.FindUsername(tip.UserID)
+		if err != nil {
+			log.Println("Error finding the tipper:", err)
+		}
+		if username != "" {
+			from = username
+		}
+	}
+	message := fmt.Sprintf("%s tipped you %s.", from, money.Money{Amount: tip.Amount, Currency: tip.Currency})
+	if tip.Message != "" {
+		message += " \"" + tip.Message + "\""
+	}
+	s.notify(tip.CreatorID, "tip_received", "You received a tip", message)
+
+	return nil
+}
+
+// refund takes a refund the gateway processed out of the ledger, once per refund
+func (s tipSettlement) refund(tip domain.Tip, refundID string, amount int64) error {
+	created, err := postRefund(s.ledger, tip.PaymentID, refundID, amount)
+	if err != nil || !created {
+		return err
+	}
+
+	return s.tips.AddTipRefund(tip.ID, amount)
+}
+
+// notify stores a notification for the user, a failure here must not fail the payment
+func (s tipSettlement) notify(userID int, kind, title, message string) {
+	notification := domain.Notification{
+		UserID:  userID,
+		Type:    kind,
+		Title:   title,
+		Message: message,
+	}
+	if err := s.notifications.CreateNotification(&notification); err != nil {
+		log.Println("Error creating notification:", err)
+	}
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"errors"
+	"log"
 	conf "main/pkg/config"
 	"main/pkg/domain"
 	"main/pkg/helper"
@@ -14,13 +15,17 @@ import (
 )
 
 type userUseCase struct {
-	userRepo interfaces.UserRepository
-	config   conf.Config
+	userRepo        interfaces.UserRepository
+	enforcementRepo interfaces.EnforcementRepository
+	searchRepo      interfaces.SearchRepository
+	config          conf.Config
 }
 
-func NewUserUseCase(repo interfaces.UserRepository) services.UserUseCase {
+func NewUserUseCase(repo interfaces.UserRepository, enforcementRepo interfaces.EnforcementRepository, searchRepo interfaces.SearchRepository) services.UserUseCase {
 	return &userUseCase{
-		userRepo: repo,
+		userRepo:        repo,
+		enforcementRepo: enforcementRepo,
+		searchRepo:      searchRepo,
 	}
 }
 
@@ -76,7 +81,7 @@ func (u *userUseCase) Login(user models.UserLogin) (models.TokenUser, error) {
 	}
 
 	if !permission {
-		return models.TokenUser{}, errors.New("user is blocked by admin")
+		return models.TokenUser{}, u.blockedError(user)
 	}
 
 	// Get the user details in order to check the password, in this case ( The same function can be reused in future )
@@ -89,6 +94,15 @@ func (u *userUseCase) Login(user models.UserLogin) (models.TokenUser, error) {
 		return models.TokenUser{}, errors.New("password incorrect")
 	}
 
+	// checked once the password is right so only the user learns why and until when they are suspended
+	suspension, suspended, err := u.enforcementRepo.GetActiveSuspension(int(user_details.Id))
+	if err != nil {
+		return models.TokenUser{}, err
+	}
+	if suspended {
+		return models.TokenUser{}, errors.New(helper.SuspensionMessage(*suspension.ExpiresAt, suspension.Reason))
+	}
+
 	accessToken, refreshToken, err := helper.GenerateTokensUser(user_details)
 	if err != nil {
 		return models.TokenUser{}, errors.New("could not create token")
@@ -100,6 +114,26 @@ func (u *userUseCase) Login(user models.UserLogin) (models.TokenUser, error) {
 	}, nil
 
 }
+
+// blockedError tells a banned user why they are blocked once their password is right, as a suspended user is told
+func (u *userUseCase) blockedError(user models.UserLogin) error {
+	blocked := errors.New("user is blocked by admin")
+
+	userID, password, found, err := u.enforcementRepo.FindUserCredentials(user.Email)
+	if err != nil || !found || bcrypt.CompareHashAndPassword([]byte(password), []byte(user.Password)) != nil {
+		return blocked
+	}
+	ban, banned, err := u.enforcementRepo.GetActiveBan(userID)
+	if err != nil {
+		return err
+	}
+	if !banned {
+		return blocked
+	}
+
+	return errors.New(helper.BanMessage(ban.Reason))
+}
+
 func (i *userUseCase) ChangePassword(id int, old string, password string, repassword string) error {
 
 	userPassword, err := i.userRepo.GetPassword(id)
@@ -165,22 +199,28 @@ func (u *userUseCase) GetProfile(id int) (*models.UserProfileResponse, error) {
 	return userProfile, nil
 }
 
-const maxReasonLength = 60
+// GetBillingCountry returns the country the user is billed in, empty when it was never set.
+func (u *userUseCase) GetBillingCountry(id int) (models.BillingCountry, error) {
+	country, err := u.userRepo.GetBillingCountry(id)
+	if err != nil {
+		return models.BillingCountry{}, err
+	}
 
-func (u *userUseCase) ReportUser(reporterID, targetID int, reason string) error {
-	// Additional validation if needed
+	return models.BillingCountry{Country: country}, nil
+}
 
-	// Check if the reason length exceeds the limit
-	if len(reason) > maxReasonLength {
-		return errors.New("reason : length exceeds the limit")
+// SetBillingCountry sets the country whose prices and taxes the user's next checkouts get.
+func (u *userUseCase) SetBillingCountry(id int, country string) (models.BillingCountry, error) {
+	code, err := countryCode(country)
+	if err != nil {
+		return models.BillingCountry{}, err
 	}
 
-	// Call the user repository to store the report
-	if err := u.userRepo.StoreReport(reporterID, targetID, reason); err != nil {
-		return err
+	if err := u.userRepo.SetBillingCountry(id, code); err != nil {
+		return models.BillingCountry{}, err
 	}
 
-	return nil
+	return models.BillingCountry{Country: code}, nil
 }
 
 // ToggleFollow toggles the follow status between two users
@@ -228,7 +268,7 @@ func (uc *userUseCase) GetFollowingListWithPagination(userID int, page, limit in
 }
 
 // SearchUsersByNameWithPagination returns a paginated list of users matching the search term in alphabetical order
-func (uc *userUseCase) SearchUsersByNameWithPagination(searchTerm string, page, limit int) ([]domain.User, error) {
+func (uc *userUseCase) SearchUsersByNameWithPagination(userID int, searchTerm string, page, limit int) ([]domain.User, error) {
 	// Additional validation if needed
 
 	// Call the user repository to search for users by name with pagination
@@ -237,6 +277,14 @@ func (uc *userUseCase) SearchUsersByNameWithPagination(searchTerm string, page,
 		return nil, err
 	}
 
+	// Record the search for analytics with every match, not only this page
+	count, err := uc.userRepo.CountUsersByName(searchTerm)
+	if err != nil {
+		log.Println("Error counting user search results:", err)
+	} else {
+		logSearch(uc.searchRepo, userID, searchTerm, "users", count)
+	}
+
 	return searchResults, nil
 }
 func (u *userUseCase) GetSubscriptionPlans() ([]domain.SubscriptionPlan, error) {
@@ -2,12 +2,16 @@ package usecase
 
 import (
 	// "fmt"
+	"errors"
+	"log"
 	"sort"
+	"time"
 
 	// "github.com/agnivade/levenshtein"
 
 	conf "main/pkg/config"
 	"main/pkg/domain"
+	"main/pkg/helper"
 	interfaces "main/pkg/repository/interface"
 	services "main/pkg/usecase/interface"
 	"main/pkg/utils/models"
@@ -15,16 +19,21 @@ import (
 	"github.com/agnivade/levenshtein"
 )
 
+// a view keeps taking progress for this long after the video was opened
+const watchProgressWindow = 24 * time.Hour
+
 // UseCase is a struct representing the video use case.
 type VideoUseCase struct {
-	videoRepo interfaces.VideoRepository
-	conf      conf.Config
+	videoRepo  interfaces.VideoRepository
+	searchRepo interfaces.SearchRepository
+	conf       conf.Config
 }
 
 // NewVideoUseCase creates a new instance of the video use case.
-func NewVideoUseCase(videoRepo interfaces.VideoRepository) services.VideoUseCase {
+func NewVideoUseCase(videoRepo interfaces.VideoRepository, searchRepo interfaces.SearchRepository) services.VideoUseCase {
 	return &VideoUseCase{
-		videoRepo: videoRepo,
+		videoRepo:  videoRepo,
+		searchRepo: searchRepo,
 	}
 }
 
@@ -70,16 +79,20 @@ func (uc *VideoUseCase) ListVideos(userID int, page, limit int) ([]models.Video,
 	return videos, nil
 }
 
-// EditVideoDetails edits the title and description of a video.
-func (uc *VideoUseCase) EditVideoDetails(videoID int, title, description string) error {
+// EditVideoDetails edits the title and description of a video, and its tags when they are given.
+// It returns the banned tags that were left out.
+func (uc *VideoUseCase) EditVideoDetails(videoID int, title, description string, tags *[]string) ([]string, error) {
 	// Validate the input, if necessary
 
 	// Call the repository to edit video details in the database
 	if err := uc.videoRepo.EditVideoDetails(videoID, title, description); err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	if tags == nil {
+		return []string{}, nil
+	}
+	return uc.videoRepo.SetVideoTags(videoID, *tags)
 }
 
 // DeleteVideo deletes video details and URL based on its ID.
@@ -142,6 +155,60 @@ func (uc *VideoUseCase) DeleteVideo(videoID int) error {
 //			return "", ctx.Err()
 //		}
 //	}
+
+// WatchVideo returns the URL of the video and counts the view. An exclusive video needs a subscription
+// to a tier of the creator that unlocks it, creators can always watch their own videos.
+func (uc *VideoUseCase) WatchVideo(userID, videoID int) (string, error) {
+	video, err := uc.videoRepo.GetVideoByID(videoID)
+	if err != nil {
+		return "", err
+	}
+
+	if video.Exclusive && int(video.UserID) != userID {
+		isSubscribed, err := uc.videoRepo.IsUserSubscribed(userID, int(video.UserID), videoID)
+		if err != nil {
+			return "", err
+		}
+
+		if !isSubscribed {
+			return "", errors.New("subscribe to a tier of the creator that includes this video to watch it")
+		}
+	}
+
+	if err := uc.videoRepo.IncrementVideoViews(videoID); err != nil {
+		return "", err
+	}
+
+	view := domain.VideoView{
+		VideoID:   video.ID,
+		UserID:    userID,
+		CreatorID: int(video.UserID),
+	}
+	if err := uc.videoRepo.RecordVideoView(&view); err != nil {
+		return "", err
+	}
+
+	return video.URL, nil
+}
+
+// UpdateWatchProgress records how far the user got into the video they started watching, it feeds the watch time and
+// audience retention of the creator's analytics. Progress is taken for a day after the video was opened.
+func (uc *VideoUseCase) UpdateWatchProgress(userID int, progress models.WatchProgress) error {
+	if progress.Position < 0 {
+		return errors.New("position cannot be negative")
+	}
+
+	found, err := uc.videoRepo.UpdateWatchProgress(userID, progress.VideoID, progress.Position, time.Now().Add(-watchProgressWindow))
+	if err != nil {
+		return err
+	}
+	if !found {
+		return errors.New("watch the video before sending its progress")
+	}
+
+	return nil
+}
+
 func (uc *VideoUseCase) ToggleLikeVideo(userID uint, videoID uint) error {
 	// Check if the user has already liked the video
 	likedByUser := uc.videoRepo.IsLikedByUser(userID, videoID)
@@ -193,14 +260,10 @@ func (uc *VideoUseCase) GetComments(videoID uint) ([]domain.Comment, error) {
 	return comments, nil
 }
 
-// AddVideoTags adds multiple tags to a video.
-func (uc *VideoUseCase) AddVideoTags(tags []string) error {
+// AddVideoTags adds multiple tags to the database, it returns the banned tags that were skipped.
+func (uc *VideoUseCase) AddVideoTags(tags []string) ([]string, error) {
 	// Call the repository function to add tags to the database
-	if err := uc.videoRepo.AddTags(tags); err != nil {
-		return err
-	}
-
-	return nil
+	return uc.videoRepo.AddTags(tags)
 }
 
 // DeleteVideoTagByID deletes a tag from the database based on a tag ID.
@@ -504,5 +567,44 @@ func (uc *VideoUseCase) ListtVideos(page, limit int, sort, order, search string)
 
 	// If needed, you can perform additional business logic or filtering on the videos here
 
+	// The listing is public, so searches made here are logged without a user
+	if search != "" {
+		count, err := uc.videoRepo.CountVideosByTitle(search)
+		if err != nil {
+			log.Println("Error counting video search results:", err)
+		} else {
+			logSearch(uc.searchRepo, 0, search, "videos", count)
+		}
+	}
+
 	return videos, nil
 }
+
+// videoDurationBatch is how many videos one backfill run probes
+const videoDurationBatch = 100
+
+// BackfillVideoDurations probes the length of videos uploaded without one and returns how many were found.
+// A video that cannot be probed is marked so it is not probed again.
+func (uc *VideoUseCase) BackfillVideoDurations() (int, error) {
+	videos, err := uc.videoRepo.ListVideosWithoutDuration(videoDurationBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	found := 0
+	for _, video := range videos {
+		duration, err := helper.ProbeVideoDuration(video.URL)
+		if err != nil {
+			log.Printf("Error probing the duration of video %d: %v\n", video.ID, err)
+			duration = -1
+		} else {
+			found++
+		}
+
+		if err := uc.videoRepo.SetVideoDuration(video.ID, duration); err != nil {
+			return found, err
+		}
+	}
+
+	return found, nil
+}
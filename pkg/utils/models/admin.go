@@ -4,6 +4,27 @@ type AdminLogin struct {
 	Email    string `json:"email,omitempty" validate:"required"`
 	Password string `json:"password" validate:"min=8,max=20"`
 }
+
+// AdminAccount is an admin as other admins see it
+type AdminAccount struct {
+	ID       uint   `json:"id"`
+	Username string `json:"name"`
+	Email    string `json:"email"`
+	Role     string `json:"role"`
+	Disabled bool   `json:"disabled"`
+}
+
+type CreateAdmin struct {
+	Username string `json:"name" binding:"required"`
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+	Role     string `json:"role" binding:"required"` // super_admin, moderator, finance or content_curator
+}
+
+type AdminRole struct {
+	Role string `json:"role" binding:"required"`
+}
+
 type TokenAdmin struct {
 	Username     string
 	RefreshToken string
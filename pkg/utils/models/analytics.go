@@ -0,0 +1,107 @@
+package models
+
+// AnalyticsPoint is the activity of one day, week or month, Date is the first day of the period.
+// Followers, subscribers and revenue are only kept for the whole channel and are 0 on a single video.
+type AnalyticsPoint struct {
+	Date              string             `json:"date"`
+	Views             int64              `json:"views"`
+	WatchSeconds      int64              `json:"watch_seconds"`
+	Likes             int64              `json:"likes"`
+	Comments          int64              `json:"comments"`
+	NewFollowers      int64              `json:"new_followers"`
+	SubscribersGained int64              `json:"subscribers_gained"`
+	SubscribersLost   int64              `json:"subscribers_lost"`
+	Revenue           float64            `json:"revenue"` // in the platform currency
+	RevenueByCurrency map[string]float64 `json:"revenue_by_currency,omitempty"`
+}
+
+// AnalyticsSeries has a point for every period in the range, periods without activity included.
+type AnalyticsSeries struct {
+	Granularity string           `json:"granularity"`
+	StartDate   string           `json:"start_date"`
+	EndDate     string           `json:"end_date"`
+	Currency    string           `json:"currency"`
+	Points      []AnalyticsPoint `json:"points"`
+	Totals      AnalyticsPoint   `json:"totals"`
+}
+
+// VideoAnalytics is the activity on one of the creator's videos over the range.
+type VideoAnalytics struct {
+	VideoID            uint    `json:"video_id"`
+	Title              string  `json:"title"`
+	Duration           int     `json:"duration"`
+	Views              int64   `json:"views"`
+	WatchSeconds       int64   `json:"watch_seconds"`
+	AverageViewSeconds float64 `json:"average_view_seconds"`
+	Likes              int64   `json:"likes"`
+	Comments           int64   `json:"comments"`
+}
+
+// RetentionPoint is how many of the views reached a point of the video.
+type RetentionPoint struct {
+	Percent   int     `json:"percent"` // of the video's duration
+	Second    int     `json:"second"`
+	Views     int64   `json:"views"`
+	Retention float64 `json:"retention"` // percent of all views
+}
+
+type VideoRetention struct {
+	VideoID   uint             `json:"video_id"`
+	Duration  int              `json:"duration"`
+	StartDate string           `json:"start_date"`
+	EndDate   string           `json:"end_date"`
+	Views     int64            `json:"views"`
+	Points    []RetentionPoint `json:"points"`
+}
+
+// WatchProgress is how far into the video the user has watched, in seconds.
+type WatchProgress struct {
+	VideoID  int `json:"video_id" binding:"required"`
+	Position int `json:"position"`
+}
+
+// AnalyticsExport picks the report to export and its range, the last 30 days by default.
+// views, subscribers and revenue have a row per day, week or month, videos a row per video.
+type AnalyticsExport struct {
+	Report      string `json:"report"` // views, subscribers, revenue or videos
+	Format      string `json:"format"` // csv or ndjson
+	StartDate   string `json:"start_date"`
+	EndDate     string `json:"end_date"`
+	Granularity string `json:"granularity"`
+}
+
+type AnalyticsReportSettings struct {
+	Weekly bool   `json:"weekly"`
+	Format string `json:"format"` // csv or ndjson, csv when not given
+}
+
+// DashboardMetric compares a metric with the period of the same length just before, ChangePercent is left out
+// when the metric was 0 or unknown then.
+type DashboardMetric struct {
+	Current       float64  `json:"current"`
+	Previous      float64  `json:"previous"`
+	Change        float64  `json:"change"`
+	ChangePercent *float64 `json:"change_percent,omitempty"`
+}
+
+// Dashboard is the platform's overview for a period. Active users, active subscriptions and the moderation backlog are
+// as of the last day of each period, the others are totals over it. Revenue is what was charged, tax included, before refunds and fees.
+type Dashboard struct {
+	StartDate              string                     `json:"start_date"`
+	EndDate                string                     `json:"end_date"`
+	PreviousStartDate      string                     `json:"previous_start_date"`
+	PreviousEndDate        string                     `json:"previous_end_date"`
+	Currency               string                     `json:"currency"`
+	DailyActiveUsers       DashboardMetric            `json:"daily_active_users"`
+	WeeklyActiveUsers      DashboardMetric            `json:"weekly_active_users"`
+	MonthlyActiveUsers     DashboardMetric            `json:"monthly_active_users"`
+	Signups                DashboardMetric            `json:"signups"`
+	Uploads                DashboardMetric            `json:"uploads"`
+	Views                  DashboardMetric            `json:"views"`
+	WatchSeconds           DashboardMetric            `json:"watch_seconds"`
+	ActiveSubscriptions    DashboardMetric            `json:"active_subscriptions"`
+	GrossRevenue           DashboardMetric            `json:"gross_revenue"` // in the platform currency
+	GrossRevenueByCurrency map[string]DashboardMetric `json:"gross_revenue_by_currency"`
+	Reports                DashboardMetric            `json:"reports"`
+	ModerationBacklog      DashboardMetric            `json:"moderation_backlog"`
+}
@@ -0,0 +1,25 @@
+package models
+
+type AuditFilter struct {
+	AdminID    uint
+	Action     string
+	TargetType string
+	TargetID   string
+	StartDate  string
+	EndDate    string
+}
+
+// AuditEntry is what the audit middleware saw of a privileged request
+type AuditEntry struct {
+	AdminID    uint
+	Action     string
+	Method     string
+	Path       string
+	TargetType string
+	TargetID   string
+	Query      string
+	Body       []byte
+	Before     *string
+	Status     int
+	IP         string
+}
@@ -1,6 +1,35 @@
 package models
 
-type SetNewName struct {
-	Current string `json:"current"`
-	New     string `json:"new"`
+type AddCategory struct {
+	Category    string `json:"category"`
+	ParentID    *uint  `json:"parent_id"`
+	Description string `json:"description"`
+	SortOrder   int    `json:"sort_order"`
+}
+
+// UpdateCategory changes only the fields that are set, a parent_id of 0 moves the category to the top level.
+type UpdateCategory struct {
+	ID          uint    `json:"id" binding:"required"`
+	Category    *string `json:"category"`
+	Slug        *string `json:"slug"`
+	Description *string `json:"description"`
+	ParentID    *uint   `json:"parent_id"`
+	SortOrder   *int    `json:"sort_order"`
+}
+
+type CategoryOrder struct {
+	ID        uint `json:"id" binding:"required"`
+	SortOrder int  `json:"sort_order"`
+}
+
+type CategoryTree struct {
+	ID          uint           `json:"id"`
+	Category    string         `json:"category"`
+	Slug        string         `json:"slug"`
+	ParentID    *uint          `json:"parent_id"`
+	Description string         `json:"description"`
+	IconURL     string         `json:"icon_url"`
+	SortOrder   int            `json:"sort_order"`
+	Videos      int64          `json:"videos"`
+	Children    []CategoryTree `json:"children" gorm:"-"`
 }
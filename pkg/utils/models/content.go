@@ -30,7 +30,9 @@ type RecommendationListResponse struct {
 	URL         string `json:"url"`
 }
 
+// EditVideoDetails replaces the tags of the video when they are given, the tags are left as they are otherwise.
 type EditVideoDetails struct {
-	Title       string `json:"title" binding:"required"`
-	Description string `json:"description" binding:"required"`
+	Title       string    `json:"title" binding:"required"`
+	Description string    `json:"description" binding:"required"`
+	Tags        *[]string `json:"tags"`
 }
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// AddCoupon is a new coupon, creator_id is only taken from admins, a creator's coupons are always scoped to the creator.
+type AddCoupon struct {
+	Code           string     `json:"code" binding:"required"`
+	CreatorID      int        `json:"creator_id"`
+	DiscountType   string     `json:"discount_type" binding:"required"` // percent or fixed
+	DiscountValue  float64    `json:"discount_value" binding:"required"`
+	MaxRedemptions int        `json:"max_redemptions"`
+	FirstTimeOnly  bool       `json:"first_time_only"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+}
+
+// PurchaseResult is what choosing a plan returns, a started trial has no checkout link.
+// Amounts are in the currency's major unit, the final price includes a tax that is added to the price.
+type PurchaseResult struct {
+	SubscriptionID int        `json:"subscription_id"`
+	Kind           string     `json:"kind"`
+	Link           string     `json:"link,omitempty"`
+	Price          float64    `json:"price"`
+	Currency       string     `json:"currency"`
+	Coupon         string     `json:"coupon,omitempty"`
+	Discount       float64    `json:"discount"`
+	Tax            float64    `json:"tax"`
+	TaxLabel       string     `json:"tax_label,omitempty"`
+	FinalPrice     float64    `json:"final_price"`
+	TrialEndsAt    *time.Time `json:"trial_ends_at,omitempty"`
+}
@@ -0,0 +1,40 @@
+package models
+
+// EnforcementRequest takes an action against a user, a takedown names the video or comment instead of the user.
+// Suspensions need a duration, warnings and strikes expire after the default period unless one is given.
+type EnforcementRequest struct {
+	Type          string `json:"type" binding:"required"` // warning, strike, suspension, takedown or ban
+	UserID        int    `json:"user_id"`
+	TargetType    string `json:"target_type"` // video or comment, for takedowns
+	TargetID      int    `json:"target_id"`
+	Reason        string `json:"reason" binding:"required"`
+	DurationHours int    `json:"duration_hours"`
+	CaseID        *uint  `json:"case_id"`
+}
+
+// BlockRequest blocks or unblocks a user, the reason is shown to the user.
+type BlockRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type RevokeRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type AppealRequest struct {
+	ActionID uint   `json:"action_id" binding:"required"`
+	Message  string `json:"message" binding:"required"`
+}
+
+// SuspensionAppeal appeals the current suspension or ban of a user who cannot log in, they prove who they are with their login.
+type SuspensionAppeal struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+	Message  string `json:"message" binding:"required"`
+}
+
+// AppealDecision answers an appeal, overturning it revokes the action.
+type AppealDecision struct {
+	Overturn bool   `json:"overturn"`
+	Response string `json:"response" binding:"required"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"main/pkg/domain"
+	"time"
+)
+
+// GiftOrder buys gift codes for a creator's plan. With a recipient the one code is redeemed for them once paid.
+type GiftOrder struct {
+	PlanID      int  `json:"plan_id" binding:"required"`
+	Quantity    int  `json:"quantity"` // 1 when not given
+	RecipientID *int `json:"recipient_id"`
+	ValidDays   int  `json:"valid_days"` // days the codes can be redeemed for, 90 when not given
+}
+
+// GiftCheckout is the price of a gift purchase and the gateway order to pay it with, amounts are in the major unit.
+// A giveaway has nothing to pay and comes with its codes.
+type GiftCheckout struct {
+	Purchase   domain.GiftPurchase `json:"purchase"`
+	UnitPrice  float64             `json:"unit_price"`
+	Tax        float64             `json:"tax"`
+	TaxLabel   string              `json:"tax_label,omitempty"`
+	FinalPrice float64             `json:"final_price"`
+	Currency   string              `json:"currency"`
+	Paid       bool                `json:"paid"`
+	Codes      []domain.GiftCode   `json:"codes,omitempty"`
+	RazorID    string              `json:"razor_id,omitempty"`
+	Amount     int64               `json:"amount"` // in the currency's minor unit
+	Gateway    string              `json:"gateway,omitempty"`
+	KeyID      string              `json:"key_id,omitempty"`
+}
+
+type GiftRedemption struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// RedeemedGift is the subscription a gift code started or extended.
+type RedeemedGift struct {
+	SubscriptionID int       `json:"subscription_id"`
+	CreatorID      int       `json:"creator_id"`
+	PlanID         int       `json:"plan_id"`
+	Kind           string    `json:"kind"`
+	PeriodEnd      time.Time `json:"period_end"`
+}
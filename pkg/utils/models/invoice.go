@@ -0,0 +1,29 @@
+package models
+
+type InvoiceParties struct {
+	BuyerName   string
+	BuyerEmail  string
+	CreatorName string
+}
+
+// InvoiceLine is a row of the invoice's amounts, formatted for display.
+type InvoiceLine struct {
+	Label  string
+	Amount string
+}
+
+// InvoiceView is an invoice formatted for the HTML and PDF documents.
+type InvoiceView struct {
+	Issuer      string
+	Number      string
+	IssuedAt    string
+	BuyerName   string
+	BuyerEmail  string
+	CreatorName string
+	PlanName    string
+	Period      string
+	PaymentID   string
+	Lines       []InvoiceLine
+	Total       string
+	TaxNote     string
+}
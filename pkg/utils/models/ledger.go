@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// CreatorEarnings are what a creator earned in one currency, in its major unit. The balance is what the platform still owes the creator.
+type CreatorEarnings struct {
+	Earned   float64 `json:"earned"`
+	Refunded float64 `json:"refunded"`
+	PaidOut  float64 `json:"paid_out"`
+	Balance  float64 `json:"balance"`
+	Currency string  `json:"currency"`
+}
+
+// LedgerCharge is a charge in the ledger with what was refunded on the same subscription, in minor units.
+type LedgerCharge struct {
+	TransactionID      uint   `json:"transaction_id"`
+	SubscriptionListID int    `json:"subscription_list_id"`
+	PaymentID          string `json:"payment_id"`
+	Amount             int64  `json:"amount"`
+	Refunded           int64  `json:"refunded"`
+	Currency           string `json:"currency"`
+}
+
+// ReconcileIssue is a difference between the ledger and the gateway or the subscriptions, amounts are in minor units.
+type ReconcileIssue struct {
+	SubscriptionListID int    `json:"subscription_list_id"`
+	PaymentID          string `json:"payment_id"`
+	Issue              string `json:"issue"`
+	LedgerAmount       int64  `json:"ledger_amount"`
+	GatewayAmount      int64  `json:"gateway_amount"`
+	LedgerRefunded     int64  `json:"ledger_refunded"`
+	GatewayRefunded    int64  `json:"gateway_refunded"`
+}
+
+type LedgerReconciliation struct {
+	From                   time.Time        `json:"from"`
+	To                     time.Time        `json:"to"`
+	Charges                int              `json:"charges"`
+	Matched                int              `json:"matched"`
+	Truncated              bool             `json:"truncated"` // more charges than one report checks, narrow the range
+	Issues                 []ReconcileIssue `json:"issues"`
+	UnbalancedTransactions []uint           `json:"unbalanced_transactions"`
+}
+
+type PlatformFee struct {
+	Percent float64 `json:"percent"`
+}
@@ -0,0 +1,28 @@
+package models
+
+// ReportRequest reports a user, video or comment, the reason is optional.
+type ReportRequest struct {
+	TargetType string `json:"target_type" binding:"required"` // user, video or comment
+	TargetID   int    `json:"target_id" binding:"required"`
+	Category   string `json:"category" binding:"required"` // spam, harassment, cheating, impersonation, copyright or other
+	Reason     string `json:"reason"`
+}
+
+type CaseFilter struct {
+	Status     string
+	Category   string
+	TargetType string
+	AssigneeID uint
+}
+
+type CaseNoteRequest struct {
+	Note string `json:"note" binding:"required"`
+}
+
+// CaseResolution closes a case, dismiss leaves the target as it is.
+type CaseResolution struct {
+	Action string `json:"action" binding:"required"` // dismiss, remove_content or block_user
+	Note   string `json:"note"`
+	// Reason is shown to the user whose content is removed or who is blocked, the category is used when it is empty
+	Reason string `json:"reason"`
+}
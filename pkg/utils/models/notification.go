@@ -0,0 +1,8 @@
+package models
+
+import "main/pkg/domain"
+
+type NotificationList struct {
+	Unread        int64                 `json:"unread"`
+	Notifications []domain.Notification `json:"notifications"`
+}
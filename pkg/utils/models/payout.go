@@ -0,0 +1,41 @@
+package models
+
+type PayoutAccountDetails struct {
+	Method        string `json:"method" binding:"required"` // bank or upi
+	AccountHolder string `json:"account_holder"`
+	AccountNumber string `json:"account_number"`
+	IFSC          string `json:"ifsc"`
+	UPIID         string `json:"upi_id"`
+}
+
+// PayoutSettings apply to the batches made after they change, the minimum is in the major unit of each currency.
+type PayoutSettings struct {
+	MinAmount float64 `json:"min_amount"`
+	HoldDays  int     `json:"hold_days"`
+}
+
+// CreatorBalance splits what the platform owes a creator in one currency, in minor units. Eligible is what a batch with
+// the given cutoff could pay, Reserved is already in batches that are not paid yet.
+type CreatorBalance struct {
+	Currency string
+	Total    int64
+	Eligible int64
+	Reserved int64
+}
+
+// PayoutBalance is what a creator has in one currency, in its major unit. Available is what the next batch pays when it reaches the minimum,
+// earnings still within the hold period are on hold and amounts in unpaid batches are in payout.
+type PayoutBalance struct {
+	Balance   float64 `json:"balance"`
+	OnHold    float64 `json:"on_hold"`
+	InPayout  float64 `json:"in_payout"`
+	Available float64 `json:"available"`
+	MinAmount float64 `json:"min_amount"`
+	HoldDays  int     `json:"hold_days"`
+	Currency  string  `json:"currency"`
+}
+
+type MarkPayoutPaid struct {
+	PayoutID  uint   `json:"payout_id" binding:"required"`
+	Reference string `json:"reference" binding:"required"`
+}
@@ -0,0 +1,114 @@
+package models
+
+import "time"
+
+// SearchFilter holds the query and the optional filters of a unified search.
+type SearchFilter struct {
+	Query      string `json:"query"`
+	Type       string `json:"type"`        // all, videos, creators, tags, categories
+	UploadDate string `json:"upload_date"` // hour, today, week, month, year
+	Duration   string `json:"duration"`    // short, medium, long
+	CategoryID int    `json:"category_id"`
+	Access     string `json:"access"` // exclusive, free
+	CreatorID  int    `json:"creator_id"`
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+}
+
+type VideoSearchResult struct {
+	ID          uint      `json:"id"`
+	UserID      uint      `json:"user_id"`
+	Creator     string    `json:"creator"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	URL         string    `json:"url"`
+	CategoryID  int       `json:"category_id"`
+	Category    string    `json:"category"`
+	Duration    int       `json:"duration"`
+	Views       int       `json:"views"`
+	Likes       int       `json:"likes"`
+	Exclusive   bool      `json:"exclusive"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type CreatorSearchResult struct {
+	ID        int    `json:"id"`
+	Username  string `json:"username"`
+	Name      string `json:"name"`
+	Bio       string `json:"bio"`
+	URL       string `json:"url"`
+	Followers int    `json:"followers"`
+}
+
+type TagSearchResult struct {
+	ID    uint   `json:"id"`
+	Tag   string `json:"tag"`
+	Usage int    `json:"usage"`
+}
+
+type CategorySearchResult struct {
+	ID       uint   `json:"id"`
+	Category string `json:"category"`
+	Videos   int    `json:"videos"`
+}
+
+// SearchGroup is one typed block of the unified search response.
+type SearchGroup struct {
+	Type  string      `json:"type"`
+	Count int64       `json:"count"`
+	Items interface{} `json:"items"`
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+	Count int64  `json:"count"`
+}
+
+// SearchFacets holds the per-filter counts of the matching videos.
+type SearchFacets struct {
+	UploadDate []FacetCount `json:"upload_date"`
+	Duration   []FacetCount `json:"duration"`
+	Category   []FacetCount `json:"category"`
+	Access     []FacetCount `json:"access"`
+	Creator    []FacetCount `json:"creator"`
+}
+
+type SearchResults struct {
+	SearchID uint          `json:"search_id"`
+	Query    string        `json:"query"`
+	Total    int64         `json:"total"`
+	Groups   []SearchGroup `json:"groups"`
+	Facets   SearchFacets  `json:"facets"`
+}
+
+type Suggestion struct {
+	Type       string `json:"type"`
+	ID         int    `json:"id"`
+	Text       string `json:"text"`
+	Popularity int64  `json:"popularity"`
+}
+
+type SuggestResults struct {
+	Query       string       `json:"query"`
+	Completions []Suggestion `json:"completions"`
+	DidYouMean  []string     `json:"did_you_mean"`
+}
+
+// SearchQueryStat aggregates the searches made for one normalized query.
+type SearchQueryStat struct {
+	Query            string  `json:"query"`
+	Searches         int64   `json:"searches"`
+	Users            int64   `json:"users"`
+	AvgResults       float64 `json:"avg_results"`
+	Clicks           int64   `json:"clicks"`
+	ClickThroughRate float64 `json:"click_through_rate"`
+}
+
+type SearchTrendPoint struct {
+	Period      time.Time `json:"period"`
+	Searches    int64     `json:"searches"`
+	Users       int64     `json:"users"`
+	ZeroResults int64     `json:"zero_results"`
+	Clicks      int64     `json:"clicks"`
+}
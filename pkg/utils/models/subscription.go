@@ -1,14 +1,98 @@
 package models
 
+import (
+	"main/pkg/money"
+	"time"
+)
+
+// PriceQuote is what a buyer pays for a plan, amounts are in the currency's minor unit.
+// Price is the plan's price in the buyer's region, the total adds a tax that is not included in it.
+type PriceQuote struct {
+	Region   string
+	Price    money.Money
+	Discount int64
+	Tax      TaxQuote
+	Total    int64
+}
+
 type OrderPaymentDetails struct {
 	UserID     int     `json:"user_id"`
 	Username   string  `json:"username"`
 	Razor_id   string  `josn:"razor_id"`
 	OrderID    int     `json:"order_id"`
 	FinalPrice float64 `json:"final_price"`
+	Discount   float64 `json:"discount"`
+	Tax        float64 `json:"tax"`
+	TaxLabel   string  `json:"tax_label"`
+	Coupon     string  `json:"coupon"`
+	Paid       bool    `json:"paid"`   // a coupon covered the whole price, there is nothing left to pay
+	Amount     int64   `json:"amount"` // in the currency's minor unit
+	Currency   string  `json:"currency"`
+	Gateway    string  `json:"gateway"`
+	KeyID      string  `json:"key_id"`
+}
+
+// SimulatedPayment is what a checkout returns, produced by the fake gateway.
+type SimulatedPayment struct {
+	PaymentID string `json:"payment_id"`
+	RazorID   string `json:"razor_id"`
+	Signature string `json:"signature"`
 }
+
+// AnalyticsData has the revenue in the platform currency, and in every currency the creator earned in by currency.
 type AnalyticsData struct {
-	SubscribersCount int     `json:"subscribers_count"`
-	Revenue          float64 `json:"revenue"`
+	SubscribersCount  int                `json:"subscribers_count"`
+	Revenue           float64            `json:"revenue"`
+	Currency          string             `json:"currency"`
+	RevenueByCurrency map[string]float64 `json:"revenue_by_currency"`
+	Tips              float64            `json:"tips"` // tips received in the platform currency before the platform fee, their share is in the revenue
 	// Add more fields as needed
 }
+
+type UserSubscription struct {
+	ID                 int        `json:"id"`
+	CreatorID          int        `json:"creator_id"`
+	Creator            string     `json:"creator"`
+	PlanID             int        `json:"plan_id"`
+	Plan               string     `json:"plan"`
+	Status             string     `json:"status"`
+	Kind               string     `json:"kind"`
+	PaymentStatus      string     `json:"payment_status"`
+	SubscribedAt       *time.Time `json:"subscribed_at"`
+	CurrentPeriodStart *time.Time `json:"current_period_start"`
+	CurrentPeriodEnd   *time.Time `json:"current_period_end"`
+	CancelAtPeriodEnd  bool       `json:"cancel_at_period_end"`
+}
+
+// LifecycleResult counts what one run of the subscription lifecycle job changed.
+type LifecycleResult struct {
+	Reminded  int `json:"reminded"`
+	PastDue   int `json:"past_due"`
+	Cancelled int `json:"cancelled"`
+	Expired   int `json:"expired"`
+	Abandoned int `json:"abandoned"` // pending checkouts that were never paid
+}
+
+// SubscriptionActivation is how a paid subscription starts, worked out from the subscription the user already had.
+type SubscriptionActivation struct {
+	Kind                   string
+	PreviousSubscriptionID *int
+	PeriodStart            time.Time
+	PeriodEnd              time.Time
+}
+
+// RefundRequest is in the currency's major unit, an amount of 0 refunds what is left of the payment.
+// Access is kept until the period ends unless it is revoked.
+type RefundRequest struct {
+	SubscriptionID int     `json:"subscription_id" binding:"required"`
+	Amount         float64 `json:"amount"`
+	RevokeAccess   bool    `json:"revoke_access"`
+	Reason         string  `json:"reason"`
+}
+
+// DisputeReview closes an admin's review of a dispute, a suspended subscription is reinstated or cancelled.
+type DisputeReview struct {
+	DisputeID uint   `json:"dispute_id" binding:"required"`
+	Reinstate bool   `json:"reinstate"`
+	Note      string `json:"note"`
+}
@@ -0,0 +1,18 @@
+package models
+
+// TagStat is the usage of one canonical tag.
+type TagStat struct {
+	ID        uint   `json:"id"`
+	Tag       string `json:"tag"`
+	Videos    int64  `json:"videos"`
+	Followers int64  `json:"followers"`
+	Aliases   int64  `json:"aliases"`
+}
+
+// TagMergeResult reports what was moved from the merged tag into the target tag.
+type TagMergeResult struct {
+	Target    uint  `json:"target"`
+	VideoTags int64 `json:"video_tags"`
+	UserTags  int64 `json:"user_tags"`
+	Aliases   int64 `json:"aliases"`
+}
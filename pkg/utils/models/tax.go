@@ -0,0 +1,21 @@
+package models
+
+// TaxRuleDetails sets the tax of a region, an ISO 3166 country code or * for every region without its own rule.
+type TaxRuleDetails struct {
+	Region    string  `json:"region" binding:"required"`
+	Name      string  `json:"name" binding:"required"`
+	Rate      float64 `json:"rate" binding:"required"` // percent
+	Inclusive bool    `json:"inclusive"`
+}
+
+// TaxQuote is the tax on a price, the amount is in the currency's minor unit.
+type TaxQuote struct {
+	Name      string  `json:"name"`
+	Rate      float64 `json:"rate"`
+	Inclusive bool    `json:"inclusive"`
+	Amount    int64   `json:"amount"`
+}
+
+type BillingCountry struct {
+	Country string `json:"country" binding:"required"` // ISO 3166 country code, e.g. IN
+}
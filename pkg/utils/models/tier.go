@@ -0,0 +1,62 @@
+package models
+
+import "main/pkg/money"
+
+// AddTier takes the price in the major unit of its currency, the platform currency when none is given.
+type AddTier struct {
+	Name      string  `json:"name" binding:"required"`
+	Duration  int     `json:"duration" binding:"required"` // in days
+	Price     float64 `json:"price" binding:"required"`
+	Currency  string  `json:"currency"`
+	Perks     string  `json:"perks"`
+	TrialDays int     `json:"trial_days"`
+}
+
+// UpdateTier changes only the fields that are set.
+type UpdateTier struct {
+	ID        int      `json:"id" binding:"required"`
+	Name      *string  `json:"name"`
+	Duration  *int     `json:"duration"`
+	Price     *float64 `json:"price"`
+	Currency  *string  `json:"currency"`
+	Perks     *string  `json:"perks"`
+	TrialDays *int     `json:"trial_days"`
+}
+
+type TierVideos struct {
+	TierID   int    `json:"tier_id" binding:"required"`
+	VideoIDs []uint `json:"video_ids"`
+}
+
+// RegionalPrice is what buyers in a region pay for a tier, the price is in the major unit of the currency.
+type RegionalPrice struct {
+	Region   string  `json:"region" binding:"required"` // ISO 3166 country code, e.g. US
+	Price    float64 `json:"price" binding:"required"`
+	Currency string  `json:"currency" binding:"required"`
+}
+
+// TierPrices replaces the regional prices of a tier, an empty list leaves only its own price.
+type TierPrices struct {
+	TierID int             `json:"tier_id" binding:"required"`
+	Prices []RegionalPrice `json:"prices"`
+}
+
+type Tier struct {
+	ID             int                    `json:"id"`
+	CreatorID      int                    `json:"creator_id"`
+	Name           string                 `json:"name"`
+	Duration       int                    `json:"duration"`
+	Price          money.Money            `json:"price" gorm:"embedded;embeddedPrefix:price_"`
+	RegionalPrices map[string]money.Money `json:"regional_prices" gorm:"-"`
+	Perks          string                 `json:"perks"`
+	TrialDays      int                    `json:"trial_days"`
+	VideoIDs       []uint                 `json:"video_ids" gorm:"-"`
+	Subscribers    int64                  `json:"subscribers"`
+}
+
+// TierPriceLimits are the lowest and highest price a creator can set on a tier in the platform currency,
+// prices in other currencies only have to be above 0.
+type TierPriceLimits struct {
+	MinPrice float64 `json:"min_price"`
+	MaxPrice float64 `json:"max_price"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"main/pkg/domain"
+	"time"
+)
+
+// TipOrder sends a creator a tip in the platform currency's major unit, optionally on one of their videos
+// or on the tipper's own comment there.
+type TipOrder struct {
+	CreatorID int     `json:"creator_id" binding:"required"`
+	Amount    float64 `json:"amount" binding:"required"`
+	VideoID   *uint   `json:"video_id"`
+	CommentID *uint   `json:"comment_id"`
+	Message   string  `json:"message"`
+	Anonymous bool    `json:"anonymous"`
+}
+
+// TipCheckout is the gateway order to pay a tip with.
+type TipCheckout struct {
+	Tip      domain.Tip `json:"tip"`
+	RazorID  string     `json:"razor_id"`
+	Amount   int64      `json:"amount"` // in the currency's minor unit
+	Currency string     `json:"currency"`
+	Gateway  string     `json:"gateway"`
+	KeyID    string     `json:"key_id"`
+}
+
+// TipLimits are the smallest and largest tip in the platform currency's major unit.
+type TipLimits struct {
+	MinAmount float64 `json:"min_amount"`
+	MaxAmount float64 `json:"max_amount"`
+}
+
+// TipDetails is a paid tip as listings show it, Username is empty for anonymous tips.
+type TipDetails struct {
+	ID        uint      `json:"id"`
+	UserID    int       `json:"user_id,omitempty"`
+	Username  string    `json:"username,omitempty"`
+	CreatorID int       `json:"creator_id"`
+	VideoID   *uint     `json:"video_id"`
+	CommentID *uint     `json:"comment_id"`
+	Amount    float64   `json:"amount"`
+	Currency  string    `json:"currency"`
+	Message   string    `json:"message"`
+	Anonymous bool      `json:"anonymous"`
+	PaidAt    time.Time `json:"paid_at"`
+}
+
+// TipFilter picks the paid tips a listing shows, fields left empty do not filter.
+type TipFilter struct {
+	UserID    int
+	CreatorID int
+	VideoID   uint
+}